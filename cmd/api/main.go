@@ -1,6 +1,8 @@
 package main
 
 import (
+	"net"
+
 	"github.com/pressly/goose/v3"
 	_ "github.com/vincent-tien/bookmark-management/docs"
 	"github.com/vincent-tien/bookmark-management/internal/api"
@@ -61,7 +63,7 @@ func main() {
 		return
 	}
 
-	app := api.New(cfg, redisClient, db, jwtGenerator, jwtValidator)
+	app := api.New(cfg, redisClient, db, jwtGenerator, jwtValidator, net.DefaultResolver)
 	err = app.Start()
 	if err != nil {
 		panic(err)
@@ -28,50 +28,49 @@ const docTemplate = `{
                 "responses": {}
             }
         },
-        "/v1/links/redirect/{code}": {
+        "/v1/domains": {
             "get": {
-                "description": "Redirects user to the original URL based on the short code",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List the custom domains registered by the current user in registration order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Links"
-                ],
-                "summary": "Redirect to original URL",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Short code",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Domains"
                 ],
+                "summary": "List my custom domains",
                 "responses": {
-                    "302": {
-                        "description": "Redirect to original URL"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DomainListResponseDto"
+                        }
                     },
-                    "404": {
-                        "description": "URL not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
-            }
-        },
-        "/v1/links/shorten": {
+            },
             "post": {
-                "description": "Generate a short URL with expiration time",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a domain to serve short links from. Publish the returned TXT record\nand verify the domain before creating links on it. Several users may register a domain\nuntil one of them verifies it",
                 "consumes": [
                     "application/json"
                 ],
@@ -79,65 +78,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Links"
+                    "Domains"
                 ],
-                "summary": "Create a shortened link",
+                "summary": "Register a custom domain",
                 "parameters": [
                     {
-                        "description": "Shorten link request payload",
+                        "description": "Domain to register",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.LinkShortenRequestDto"
+                            "$ref": "#/definitions/dto.DomainRegisterRequestDto"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.LinkShortenResponseDto"
+                            "$ref": "#/definitions/dto.DomainDto"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid request body or domain of this service",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Domain verified by a user or already registered by you",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/response.Response"
                         }
                     }
                 }
             }
         },
-        "/v1/self/info": {
-            "get": {
+        "/v1/domains/{domain}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get user profile information",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove a custom domain together with the links created on it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Domains"
+                ],
+                "summary": "Delete my custom domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domain name",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "User profile",
+                        "description": "Domain deleted",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserProfileResponseDto"
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
                     "401": {
@@ -146,6 +163,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Domain not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -153,50 +176,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/domains/{domain}/verify": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update user profile information (display name and/or email)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Look up the TXT record of the domain and mark the domain verified if it carries the token.\nVerifying a verified domain again does nothing",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Domains"
                 ],
-                "summary": "Update user profile",
+                "summary": "Verify my custom domain",
                 "parameters": [
                     {
-                        "description": "User profile update request payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserProfileRequestDto"
-                        }
+                        "type": "string",
+                        "description": "Domain name",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated successfully",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DomainDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body or validation error",
+                    "404": {
+                        "description": "Domain not found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "409": {
+                        "description": "Domain verified by another user",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "TXT record missing or carrying another token",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -210,39 +242,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/login": {
-            "post": {
-                "description": "Login a user with username and password",
-                "consumes": [
-                    "application/json"
+        "/v1/links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List the short links created by the current user, newest first, optionally filtered by code or destination",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Links"
                 ],
-                "summary": "User Login",
+                "summary": "List my short links",
                 "parameters": [
                     {
-                        "description": "User login request payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginRequestDto"
-                        }
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Links per page (max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search in code and destination URL",
+                        "name": "q",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully logged in user",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginSuccessResponse"
+                            "$ref": "#/definitions/dto.LinkListResponseDto"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -256,41 +305,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/register": {
-            "post": {
-                "description": "Register a new user with display name, email, username, and password",
-                "consumes": [
-                    "application/json"
-                ],
+        "/v1/links/preview/{code}": {
+            "get": {
+                "description": "Shows the destination URL, its page title and warnings for suspicious destinations\nwithout following the link or counting a click. Browsers get an HTML page.\nThe destination of password-protected links is not revealed.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/html"
                 ],
                 "tags": [
-                    "Users"
+                    "Links"
                 ],
-                "summary": "User Registration",
+                "summary": "Preview a short link",
                 "parameters": [
                     {
-                        "description": "User registration request payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RegisterRequestDto"
-                        }
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully registered user",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterSuccessResponse"
+                            "$ref": "#/definitions/dto.LinkPreviewDto"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body or validation error",
+                    "404": {
+                        "description": "URL not found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
@@ -301,48 +346,1511 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "description": "Error code\nexample: VALIDATION_ERROR",
-                    "type": "string"
-                },
-                "message": {
-                    "description": "Error message\nexample: invalid url format",
-                    "type": "string"
-                }
-            }
-        },
-        "dto.LinkShortenRequestDto": {
-            "type": "object",
-            "required": [
-                "url"
-            ],
-            "properties": {
-                "exp": {
-                    "description": "Expiration time in seconds for the shortened link\nMust be greater than or equal to 1\ndescription: Time-to-live of the shortened URL (TTL)\nminimum: 1\nexample: 3600",
-                    "type": "integer"
-                },
-                "url": {
-                    "description": "Original URL that will be shortened\nMust be a valid URL format (http or https)\n\nrequired: true\nformat: url\nexample: https://example.com",
-                    "type": "string"
-                }
-            }
         },
-        "dto.LinkShortenResponseDto": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "description": "Short code of the generated URL\nexample: abc123",
-                    "type": "string"
-                },
-                "message": {
-                    "description": "Success message\nexample: Shorten URL generated successfully!",
-                    "type": "string"
+        "/v1/links/redirect/{code}": {
+            "get": {
+                "description": "Redirects user to the original URL based on the short code.\nAppending \"+\" to the code shows the preview page instead, as does every unconfirmed\nvisit of a link with an interstitial; confirm with the confirm=1 query parameter.\nPassword-protected links expect the password in the X-Link-Password header or the\npassword form field; browsers get an HTML password form instead of a JSON error.\nRequests to a verified custom domain follow the link of that domain.\nLinks redirect with 302 unless created with 301, 307 or 308; permanent redirects without\nclick limit, password, interstitial or routing may be cached, all others are sent with\nCache-Control: no-store.",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Redirect to original URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected link",
+                        "name": "X-Link-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 1 to skip the interstitial page",
+                        "name": "confirm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preview or interstitial page",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkPreviewDto"
+                        }
+                    },
+                    "301": {
+                        "description": "Permanent redirect to original URL, if the link was created with this status"
+                    },
+                    "302": {
+                        "description": "Redirect to original URL"
+                    },
+                    "303": {
+                        "description": "Redirect to original URL after the password form was submitted"
+                    },
+                    "401": {
+                        "description": "Password required or wrong password",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Destination is blocked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found, or scheduled link not yet live (status configurable)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Click limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many password attempts from this client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Redirects user to the original URL based on the short code.\nAppending \"+\" to the code shows the preview page instead, as does every unconfirmed\nvisit of a link with an interstitial; confirm with the confirm=1 query parameter.\nPassword-protected links expect the password in the X-Link-Password header or the\npassword form field; browsers get an HTML password form instead of a JSON error.\nRequests to a verified custom domain follow the link of that domain.\nLinks redirect with 302 unless created with 301, 307 or 308; permanent redirects without\nclick limit, password, interstitial or routing may be cached, all others are sent with\nCache-Control: no-store.",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Redirect to original URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected link",
+                        "name": "X-Link-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 1 to skip the interstitial page",
+                        "name": "confirm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preview or interstitial page",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkPreviewDto"
+                        }
+                    },
+                    "301": {
+                        "description": "Permanent redirect to original URL, if the link was created with this status"
+                    },
+                    "302": {
+                        "description": "Redirect to original URL"
+                    },
+                    "303": {
+                        "description": "Redirect to original URL after the password form was submitted"
+                    },
+                    "401": {
+                        "description": "Password required or wrong password",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Destination is blocked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found, or scheduled link not yet live (status configurable)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Click limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many password attempts from this client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/links/shorten": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a short URL with expiration time. Anonymous callers are allowed;\nwith a bearer token the link is owned by the caller and a higher rate limit applies.\nSigned-in callers shortening the same URL with the same options again get the existing code.\nSigned-in callers may create the link on one of their verified custom domains",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Create a shortened link",
+                "parameters": [
+                    {
+                        "description": "Shorten link request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkShortenRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkShortenResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation error, reserved alias, rejected destination, unknown or unverified domain",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid bearer token or custom domain without bearer token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Alias already taken",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/links/shorten/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shorten up to 500 URLs at once, sent as a JSON array of link requests or as CSV,\neither as a text/csv body or as a multipart upload in the file field. The CSV header\nnames the columns after the JSON fields: url, exp, alias, max_clicks, interstitial, password, domain,\nredirect_status, no_index, referrer_policy, not_before and not_after (RFC 3339),\nand UTM parameters after their query names, e.g. utm_source.\nEvery item is validated and shortened on its own; failed items carry an error instead of a code.\nItems on a custom domain need a verified domain of the caller.\nBulk requests are not deduplicated and every item counts against the shorten rate limit;\nrequests with more items than the remaining quota are rejected as a whole",
+                "consumes": [
+                    "application/json",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Create many shortened links",
+                "parameters": [
+                    {
+                        "description": "Links to shorten",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LinkShortenRequestDto"
+                            }
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file with a header row",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-item codes or errors",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkBulkShortenResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed body, unknown CSV column, no items or too many items",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded or more items than the remaining quota",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/links/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an owned short link together with its click statistics",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Delete my short link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link deleted",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the destination, extend or remove the expiry, disable/enable an owned short link or toggle its interstitial preview page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Update my short link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkUpdateRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation error or rejected destination",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/links/{code}/qr": {
+            "get": {
+                "description": "Render the QR code of the full short URL as a PNG or SVG image.\nResponses carry an ETag and are answered with 304 when If-None-Match matches",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Get short link QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "description": "Image format, defaults to png",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 2048,
+                        "minimum": 64,
+                        "type": "integer",
+                        "description": "Width and height in pixels, defaults to 256",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 16,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Quiet zone in modules, defaults to 4",
+                        "name": "margin",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "L",
+                            "M",
+                            "Q",
+                            "H"
+                        ],
+                        "type": "string",
+                        "description": "Error correction level, defaults to M",
+                        "name": "ecc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Foreground color as six hex digits, defaults to 000000",
+                        "name": "fg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Background color as six hex digits, defaults to ffffff",
+                        "name": "bg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/links/{code}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get click time series and referrer, device, browser and OS breakdowns of an owned short link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Links"
+                ],
+                "summary": "Get short link statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC3339), defaults to 30 days before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Time series bucket size",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count clicks of crawlers and link-preview fetchers",
+                        "name": "include_bots",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom domain of the link",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkStatsResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/self/info": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserProfileResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update user profile information (display name and/or email)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "User profile update request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserProfileRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/login": {
+            "post": {
+                "description": "Login a user with username and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "User login request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully logged in user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/register": {
+            "post": {
+                "description": "Register a new user with display name, email, username, and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "User Registration",
+                "parameters": [
+                    {
+                        "description": "User registration request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully registered user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.DomainDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Registration timestamp\nexample: 2026-01-01T00:00:00Z",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Host name\nexample: go.example.com",
+                    "type": "string"
+                },
+                "txt_record_name": {
+                    "description": "Name of the TXT record to publish for verification\nexample: _bookmark-verify.go.example.com",
+                    "type": "string"
+                },
+                "txt_record_value": {
+                    "description": "Value of the TXT record to publish for verification\nexample: bookmark-verify=4f9c2a7d1e8b6035",
+                    "type": "string"
+                },
+                "verified": {
+                    "description": "Whether ownership was proven; only verified domains serve links\nexample: false",
+                    "type": "boolean"
+                },
+                "verified_at": {
+                    "description": "Verification timestamp, omitted until the domain is verified\nexample: 2026-01-01T00:10:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DomainListResponseDto": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "Domains in registration order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DomainDto"
+                    }
+                }
+            }
+        },
+        "dto.DomainRegisterRequestDto": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Host name short links are served from\nexample: go.example.com",
+                    "type": "string",
+                    "maxLength": 253
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Error code\nexample: VALIDATION_ERROR",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Error message\nexample: invalid url format",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkBulkShortenItemDto": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Short code of the generated URL, omitted if the item failed\nexample: abc123",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Validation or shortening error, omitted if the item succeeded\nexample: destination rejected: domain is blocked",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Position of the item in the request, starting at 0\nexample: 0",
+                    "type": "integer"
+                },
+                "short_url": {
+                    "description": "Public URL of the link, omitted if the item failed\nexample: https://go.example.com/abc123",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Original URL of the item\nexample: https://example.com",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkBulkShortenResponseDto": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Number of links created\nexample: 2",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Number of items that failed\nexample: 1",
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "One result per requested item, in request order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkBulkShortenItemDto"
+                    }
+                }
+            }
+        },
+        "dto.LinkDto": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Short code\nexample: abc123",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Creation timestamp\nexample: 2026-01-01T00:00:00Z",
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Whether the link has been disabled by its owner\nexample: false",
+                    "type": "boolean"
+                },
+                "domain": {
+                    "description": "Custom domain serving the link, omitted for links on the shared host\nexample: go.example.com",
+                    "type": "string"
+                },
+                "expired": {
+                    "description": "Whether the link has expired\nexample: false",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "description": "Expiry timestamp, omitted for links without expiry\nexample: 2026-02-01T00:00:00Z",
+                    "type": "string"
+                },
+                "interstitial": {
+                    "description": "Whether every visit shows the preview page before redirecting\nexample: false",
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "description": "Number of redirects allowed in total, omitted for links without click limit\nexample: 1",
+                    "type": "integer"
+                },
+                "no_index": {
+                    "description": "Whether redirects ask search engines not to index the link\nexample: false",
+                    "type": "boolean"
+                },
+                "not_before": {
+                    "description": "Time before which the link is not yet available, omitted for links live from creation\nexample: 2026-11-01T09:00:00Z",
+                    "type": "string"
+                },
+                "password_protected": {
+                    "description": "Whether visitors must enter a password\nexample: false",
+                    "type": "boolean"
+                },
+                "redirect_status": {
+                    "description": "HTTP status of the redirect\nexample: 302",
+                    "type": "integer"
+                },
+                "referrer_policy": {
+                    "description": "Referrer-Policy header sent with the redirect, omitted for none\nexample: no-referrer",
+                    "type": "string"
+                },
+                "routing": {
+                    "description": "Rules and weighted variants, omitted for links without routing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkRoutingDto"
+                        }
+                    ]
+                },
+                "scheduled": {
+                    "description": "Whether the link is waiting for its not_before time\nexample: false",
+                    "type": "boolean"
+                },
+                "url": {
+                    "description": "Destination URL\nexample: https://example.com",
+                    "type": "string"
+                },
+                "utm": {
+                    "description": "UTM parameters merged into the destination, omitted for links without them",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkUtmDto"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.LinkListResponseDto": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "Links on this page, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkDto"
+                    }
+                },
+                "page": {
+                    "description": "Page number\nexample: 1",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "Number of links per page\nexample: 20",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total number of matching links\nexample: 42",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LinkPreviewDto": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Short code\nexample: abc123",
+                    "type": "string"
+                },
+                "continue_url": {
+                    "description": "URL that follows the link, skipping the interstitial page\nexample: http://localhost:8080/v1/links/redirect/abc123?confirm=1",
+                    "type": "string"
+                },
+                "password_protected": {
+                    "description": "Whether visitors must enter a password; the destination stays hidden until they do\nexample: false",
+                    "type": "boolean"
+                },
+                "short_url": {
+                    "description": "Full short URL\nexample: http://localhost:8080/v1/links/redirect/abc123",
+                    "type": "string"
+                },
+                "suspicious": {
+                    "description": "Whether the destination looks suspicious\nexample: false",
+                    "type": "boolean"
+                },
+                "title": {
+                    "description": "Title of the destination page, omitted when it could not be fetched\nexample: Example Domain",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Destination URL, omitted for password-protected links\nexample: https://example.com",
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "Reasons the destination looks suspicious",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.LinkRoutingDto": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "description": "Rules evaluated in order",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkRuleDto"
+                    }
+                },
+                "variants": {
+                    "description": "Variants of a weighted split, e.g. for A/B tests. A visitor keeps getting the same variant",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkVariantDto"
+                    }
+                }
+            }
+        },
+        "dto.LinkRuleDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "device": {
+                    "description": "Device class of the visitor\nenum: desktop,mobile,tablet\nexample: mobile",
+                    "type": "string",
+                    "enum": [
+                        "desktop",
+                        "mobile",
+                        "tablet"
+                    ]
+                },
+                "language": {
+                    "description": "Preferred language of the visitor from Accept-Language; \"de\" also matches \"de-AT\"\nexample: de",
+                    "type": "string",
+                    "maxLength": 35
+                },
+                "name": {
+                    "description": "Unique name of the rule, letters, digits, \"-\" and \"_\" only\nexample: german-mobile",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "query": {
+                    "description": "Query parameter the short URL must be visited with\nexample: src",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "query_value": {
+                    "description": "Required value of the query parameter, any value when empty\nexample: newsletter",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "url": {
+                    "description": "Destination of matching visitors\nformat: url\nexample: https://m.example.de",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkShortenRequestDto": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "alias": {
+                    "description": "Optional custom alias used as the short code instead of a random one\nLetters, digits, \"-\" and \"_\" only, 3 to 32 characters, unique regardless of case\n\nexample: my-launch",
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "Optional verified custom domain of the caller serving the link instead of the shared host\nAliases only need to be unique on their domain\n\nexample: go.example.com",
+                    "type": "string",
+                    "maxLength": 253
+                },
+                "exp": {
+                    "description": "Expiration time in seconds for the shortened link\nMust be greater than or equal to 1\ndescription: Time-to-live of the shortened URL (TTL)\nminimum: 1\nexample: 3600",
+                    "type": "integer"
+                },
+                "interstitial": {
+                    "description": "Show the preview page with the destination on every visit instead of redirecting immediately\nexample: false",
+                    "type": "boolean"
+                },
+                "max_clicks": {
+                    "description": "Optional number of redirects after which the link stops working and returns 410 Gone\nUse 1 for a one-time (burn after reading) link\nminimum: 1\nexample: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "no_index": {
+                    "description": "Ask search engines not to index the link with an X-Robots-Tag: noindex header\nexample: false",
+                    "type": "boolean"
+                },
+                "not_after": {
+                    "description": "Optional time after which the link stops resolving, RFC 3339\nReplaces the default exp; if exp is also set, the earlier end applies\n\nformat: date-time\nexample: 2026-11-08T09:00:00Z",
+                    "type": "string"
+                },
+                "not_before": {
+                    "description": "Optional time before which the link answers as not yet available, RFC 3339\nexp is counted from this time, so scheduled links get their full lifetime once live\n\nformat: date-time\nexample: 2026-11-01T09:00:00Z",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Optional passphrase visitors must enter before being redirected\nminLength: 4\nAt most 72 bytes, fewer characters outside ASCII\nmaxLength: 72\nexample: s3cret-dashboard",
+                    "type": "string",
+                    "minLength": 4
+                },
+                "redirect_status": {
+                    "description": "Optional HTTP status of the redirect, 302 when omitted\n301 and 308 are permanent and may be cached by browsers, so their repeat visits are not counted\nenum: 301,302,307,308\nexample: 301",
+                    "type": "integer",
+                    "enum": [
+                        301,
+                        302,
+                        307,
+                        308
+                    ]
+                },
+                "referrer_policy": {
+                    "description": "Optional Referrer-Policy header sent with the redirect\nenum: no-referrer,no-referrer-when-downgrade,origin,origin-when-cross-origin,same-origin,strict-origin,strict-origin-when-cross-origin,unsafe-url\nexample: no-referrer",
+                    "type": "string"
+                },
+                "routing": {
+                    "description": "Optional rules and weighted variants sending visitors to other destinations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkRoutingDto"
+                        }
+                    ]
+                },
+                "url": {
+                    "description": "Original URL that will be shortened\nMust be a valid URL format (http or https)\n\nrequired: true\nformat: url\nexample: https://example.com",
+                    "type": "string"
+                },
+                "utm": {
+                    "description": "Optional UTM parameters merged into the destination when the link is followed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkUtmDto"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.LinkShortenResponseDto": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Short code of the generated URL\nexample: abc123",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Success message\nexample: Shorten URL generated successfully!",
+                    "type": "string"
+                },
+                "short_url": {
+                    "description": "Public URL of the link, on its custom domain if it has one\nexample: https://go.example.com/abc123",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkStatsBreakdownDto": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "description": "Number of clicks with this value\nexample: 42",
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Attribute value, e.g. a browser name or referrer host\nexample: Chrome",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkStatsPointDto": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "description": "Number of clicks in the bucket\nexample: 42",
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "Start of the bucket\nexample: 2026-01-01T00:00:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkStatsResponseDto": {
+            "type": "object",
+            "properties": {
+                "all_time_unique_visitors": {
+                    "description": "Approximate number of unique visitors since the link was created\nexample: 950",
+                    "type": "integer"
+                },
+                "browsers": {
+                    "description": "Clicks per browser family",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkStatsBreakdownDto"
+                    }
+                },
+                "code": {
+                    "description": "Short code\nexample: abc123",
+                    "type": "string"
+                },
+                "devices": {
+                    "description": "Clicks per device class (\"bot\" when bots are included)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkStatsBreakdownDto"
+                    }
+                },
+                "from": {
+                    "description": "Start of the range\nexample: 2026-01-01T00:00:00Z",
+                    "type": "string"
+                },
+                "granularity": {
+                    "description": "Size of the time series buckets\nexample: day",
+                    "type": "string"
+                },
+                "include_bots": {
+                    "description": "Whether bot clicks are included in the counts and breakdowns\nexample: false",
+                    "type": "boolean"
+                },
+                "operating_systems": {
+                    "description": "Clicks per operating system",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkStatsBreakdownDto"
+                    }
+                },
+                "referrers": {
+                    "description": "Clicks per referrer host (\"direct\" when no referrer was sent)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkStatsBreakdownDto"
+                    }
+                },
+                "series": {
+                    "description": "Clicks per time bucket, including empty buckets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkStatsPointDto"
+                    }
+                },
+                "to": {
+                    "description": "End of the range\nexample: 2026-01-31T00:00:00Z",
+                    "type": "string"
+                },
+                "total_clicks": {
+                    "description": "Total number of clicks in the range\nexample: 120",
+                    "type": "integer"
+                },
+                "unique_visitors": {
+                    "description": "Approximate number of unique visitors over the whole UTC days touched by the range.\nBots are never counted as visitors\nexample: 80",
+                    "type": "integer"
+                },
+                "variants": {
+                    "description": "Clicks per routing rule or variant (\"default\" for the link's own URL)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LinkStatsVariantDto"
+                    }
+                }
+            }
+        },
+        "dto.LinkStatsVariantDto": {
+            "type": "object",
+            "properties": {
+                "clicks": {
+                    "description": "Number of clicks sent to this rule or variant\nexample: 42",
+                    "type": "integer"
+                },
+                "utm": {
+                    "description": "UTM parameters visitors of this rule or variant get with the link's current settings,\nomitted for links without UTM parameters",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkUtmDto"
+                        }
+                    ]
+                },
+                "value": {
+                    "description": "Name of the rule or variant, \"default\" for the link's own URL\nexample: b",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkUpdateRequestDto": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "description": "Disable or re-enable the link\nexample: true",
+                    "type": "boolean"
+                },
+                "exp": {
+                    "description": "New time-to-live in seconds counted from now; 0 removes the expiry\nminimum: 0\nexample: 86400",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "interstitial": {
+                    "description": "Show or stop showing the preview page on every visit\nexample: true",
+                    "type": "boolean"
+                },
+                "no_index": {
+                    "description": "Ask or stop asking search engines not to index the link\nexample: true",
+                    "type": "boolean"
+                },
+                "not_after": {
+                    "description": "New time after which the link stops resolving; if exp is also set, the earlier end applies\nformat: date-time\nexample: 2026-11-08T09:00:00Z",
+                    "type": "string"
+                },
+                "not_before": {
+                    "description": "New time before which the link is not yet available; a past time makes it live at once\nand the zero time 0001-01-01T00:00:00Z removes the schedule\nformat: date-time\nexample: 2026-11-01T09:00:00Z",
+                    "type": "string"
+                },
+                "redirect_status": {
+                    "description": "New HTTP status of the redirect\nenum: 301,302,307,308\nexample: 308",
+                    "type": "integer",
+                    "enum": [
+                        301,
+                        302,
+                        307,
+                        308
+                    ]
+                },
+                "referrer_policy": {
+                    "description": "New Referrer-Policy header sent with the redirect; an empty string removes it\nexample: no-referrer",
+                    "type": "string"
+                },
+                "routing": {
+                    "description": "New rules and weighted variants replacing the current ones; an empty object removes them",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkRoutingDto"
+                        }
+                    ]
+                },
+                "url": {
+                    "description": "New destination URL\nformat: url\nexample: https://example.com/new",
+                    "type": "string"
+                },
+                "utm": {
+                    "description": "New UTM parameters replacing the current ones; an empty object removes them",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.LinkUtmDto"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.LinkUtmDto": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "description": "Value of utm_campaign\nexample: spring-sale",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "content": {
+                    "description": "Value of utm_content\nexample: {variant}",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "medium": {
+                    "description": "Value of utm_medium\nexample: email",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "source": {
+                    "description": "Value of utm_source\nexample: newsletter",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "term": {
+                    "description": "Value of utm_term\nexample: running shoes",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.LinkVariantDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "url",
+                "weight"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Unique name of the variant, letters, digits, \"-\" and \"_\" only\nexample: b",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "url": {
+                    "description": "Destination of visitors in this variant\nformat: url\nexample: https://example.com/landing-b",
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "Share of visitors relative to the other variants\nminimum: 1\nmaximum: 1000\nexample: 50",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
                 }
             }
         },
@@ -391,7 +1899,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "password": {
-                    "description": "User's password (minimum 8 characters, must contain uppercase, lowercase, number, and special character)\nrequired: true\nminLength: 8\nexample: SecurePass123!",
+                    "description": "User's password (minimum 8 characters, at most 72 bytes, must contain uppercase, lowercase, number, and special character)\nrequired: true\nminLength: 8\nmaxLength: 72\nexample: SecurePass123!",
                     "type": "string",
                     "minLength": 8
                 },
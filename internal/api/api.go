@@ -1,13 +1,20 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/vincent-tien/bookmark-management/docs"
@@ -17,13 +24,22 @@ import (
 	"github.com/vincent-tien/bookmark-management/internal/repository"
 	"github.com/vincent-tien/bookmark-management/internal/routers"
 	"github.com/vincent-tien/bookmark-management/internal/service"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	"github.com/vincent-tien/bookmark-management/pkg/dnsverify"
 	"github.com/vincent-tien/bookmark-management/pkg/jwtUtils"
+	"github.com/vincent-tien/bookmark-management/pkg/pagetitle"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
 	validationPkg "github.com/vincent-tien/bookmark-management/pkg/validation"
 	"gorm.io/gorm"
 )
 
 const (
 	Version = "v1"
+
+	// shutdownTimeout bounds the time requests in flight get to finish on shutdown.
+	shutdownTimeout = 10 * time.Second
+	// analyticsSaltLength is the length of the salt generated when ANALYTICS_SALT is not set.
+	analyticsSaltLength = 32
 )
 
 // Engine defines the interface for the API engine.
@@ -31,7 +47,8 @@ const (
 type Engine interface {
 	// Start starts the HTTP server on the configured port.
 	// It also registers the Swagger documentation endpoint.
-	// Returns an error if the server fails to start.
+	// On SIGINT or SIGTERM it stops gracefully and flushes the recorded clicks.
+	// Returns an error if the server fails to start or to shut down.
 	Start() error
 	// ServeHTTP serves HTTP requests using the underlying gin engine.
 	ServeHTTP(w http.ResponseWriter, r *http.Request)
@@ -44,15 +61,42 @@ type api struct {
 	db           *gorm.DB
 	jwtGen       jwtUtils.JwtGenerator
 	jwtValidator jwtUtils.JwtValidator
+	resolver     dnsverify.Resolver
+	domains      service.Domain
+	policy       destpolicy.Policy
+	clicks       service.ClickRecorder
 }
 
 // Start starts the HTTP server on the configured port.
 // It also registers the Swagger documentation endpoint.
-// Returns an error if the server fails to start.
+// On SIGINT or SIGTERM it stops gracefully and flushes the recorded clicks.
+// Returns an error if the server fails to start or to shut down.
 func (a *api) Start() error {
 	docs.SwaggerInfo.Host = a.cfg.AppHostName
 	a.app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	return a.app.Run(fmt.Sprintf(":%s", a.cfg.AppPort))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: fmt.Sprintf(":%s", a.cfg.AppPort), Handler: a.app}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err = srv.Shutdown(shutdownCtx)
+	}
+
+	// Requests in flight record clicks, so the recorder is closed after the server
+	a.clicks.Close()
+
+	return err
 }
 
 // ServeHTTP serves HTTP requests using the underlying gin engine.
@@ -62,8 +106,9 @@ func (a *api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // New creates and initializes a new API engine instance.
 // It sets up the gin router, registers all endpoints, and returns an Engine interface.
-// The configuration is used to set up the application settings.
-func New(cfg *config.Config, redisClient *redis.Client, db *gorm.DB, jwtGen jwtUtils.JwtGenerator, jwtValidator jwtUtils.JwtValidator) Engine {
+// The configuration is used to set up the application settings; the resolver looks up
+// the TXT records proving the ownership of custom domains.
+func New(cfg *config.Config, redisClient *redis.Client, db *gorm.DB, jwtGen jwtUtils.JwtGenerator, jwtValidator jwtUtils.JwtValidator, resolver dnsverify.Resolver) Engine {
 	a := &api{
 		app:          gin.New(),
 		cfg:          cfg,
@@ -71,12 +116,25 @@ func New(cfg *config.Config, redisClient *redis.Client, db *gorm.DB, jwtGen jwtU
 		db:           db,
 		jwtGen:       jwtGen,
 		jwtValidator: jwtValidator,
+		resolver:     resolver,
 	}
+	a.setTrustedProxies()
 	a.registerValidators()
+	a.domains = a.newDomainService()
+	a.policy = a.newDestinationPolicy()
 	a.registerEP()
 	return a
 }
 
+// setTrustedProxies makes gin read the client IP from X-Forwarded-For only on requests
+// sent by the configured proxies. Rate limits, visitor hashes and click records rely on
+// the client IP, which clients could spoof if every proxy was trusted.
+func (a *api) setTrustedProxies() {
+	if err := a.app.SetTrustedProxies(a.cfg.TrustedProxies); err != nil {
+		panic(fmt.Sprintf("Failed to set trusted proxies: %v", err))
+	}
+}
+
 // registerValidators registers custom validation functions
 func (a *api) registerValidators() {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -86,10 +144,82 @@ func (a *api) registerValidators() {
 	}
 }
 
+// newDestinationPolicy builds the policy short link destinations are checked against.
+// Links back to this service, including its verified custom domains, are rejected so
+// short links cannot redirect in loops.
+func (a *api) newDestinationPolicy() destpolicy.Policy {
+	blocklist, err := destpolicy.LoadDomainList(a.cfg.DestinationBlocklistFile)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load destination blocklist: %v", err))
+	}
+	allowlist, err := destpolicy.LoadDomainList(a.cfg.DestinationAllowlistFile)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load destination allowlist: %v", err))
+	}
+
+	return destpolicy.New(destpolicy.Config{
+		Schemes:       a.cfg.DestinationSchemes,
+		SelfHosts:     a.selfHosts(),
+		CustomDomains: a.domains,
+		Blocklist:     blocklist,
+		Allowlist:     allowlist,
+	})
+}
+
+// selfHosts returns the hosts this service is reachable at.
+func (a *api) selfHosts() []string {
+	hosts := []string{a.cfg.AppHostName}
+	if u, err := url.Parse(a.cfg.ShortUrlBase); err == nil && u.Host != "" {
+		hosts = append(hosts, u.Host)
+	}
+
+	return hosts
+}
+
+// newDomainService builds the custom domain service; the hosts of this service cannot be registered.
+// Verified domains are cached since every request to a custom domain looks its host up.
+func (a *api) newDomainService() service.Domain {
+	return service.NewDomain(repository.NewCachedDomainRepository(a.redisClient, repository.NewDomainRepository(a.db)), a.linkKeys(),
+		a.resolver, a.selfHosts())
+}
+
+// analyticsSalt returns the salt of visitor hashes. Without ANALYTICS_SALT a random salt is
+// generated for this process: hashes still cannot be reversed to IPs, but visitors are
+// counted again after a restart and by every other instance.
+func (a *api) analyticsSalt() string {
+	if a.cfg.AnalyticsSalt != "" {
+		return a.cfg.AnalyticsSalt
+	}
+
+	salt, err := utils.GenerateRandomString(analyticsSaltLength)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate analytics salt: %v", err))
+	}
+	log.Warn().Msg("ANALYTICS_SALT is not set, using a random salt for this process; " +
+		"unique visitors are counted again after restarts and across instances")
+
+	return salt
+}
+
+// linkKeys returns the Redis stores deleted links are removed from.
+func (a *api) linkKeys() service.LinkKeys {
+	return service.LinkKeys{
+		Cache:        repository.NewLinkCache(a.redisClient),
+		Limits:       repository.NewClickLimit(a.redisClient, a.db),
+		Visitors:     repository.NewUniqueVisitor(a.redisClient),
+		Reservations: repository.NewCodeReservation(a.redisClient),
+		Targets:      repository.NewTargetIndex(a.redisClient),
+	}
+}
+
 // registerEP registers all API endpoints and sets up their dependencies.
 func (a *api) registerEP() {
 	a.registerHealthCheckEndpoint()
 	a.registerLinkShortenEndpoint()
+	a.registerLinkStatsEndpoint()
+	a.registerLinkQrEndpoint()
+	a.registerLinkManagementEndpoint()
+	a.registerDomainEndpoint()
 	a.registerUsersEndpoint()
 }
 
@@ -101,16 +231,107 @@ func (a *api) registerHealthCheckEndpoint() {
 	a.app.GET(routers.Endpoints.HealthCheck, healthCheckHandler.DoCheck)
 }
 
-// registerLinkShortenEndpoint registers the link shorten endpoint.
+// registerLinkShortenEndpoint registers the link shorten and redirect endpoints.
 func (a *api) registerLinkShortenEndpoint() {
-	urlStorage := repository.NewUrlStorage(a.redisClient)
-	linkShortenSvc := service.NewUrlShorten(urlStorage)
-	linkShortenHandler := handler.NewLinkShorten(linkShortenSvc)
+	urlStorage := repository.NewCachedUrlStorage(a.redisClient, repository.NewShortLinkStorage(a.db))
+	codes, err := service.NewCodeGenerator(service.CodeGeneratorConfig{
+		Strategy:  a.cfg.CodeStrategy,
+		Length:    a.cfg.CodeLength,
+		MaxLength: a.cfg.CodeMaxLength,
+		Alphabet:  a.cfg.CodeAlphabet,
+		Salt:      a.cfg.CodeSalt,
+	}, urlStorage, repository.NewCodeReservation(a.redisClient))
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create short code generator: %v", err))
+	}
+	linkShortenSvc := service.NewIdempotentUrlShorten(
+		service.NewUrlShorten(urlStorage, codes, repository.NewClickLimit(a.redisClient, a.db), repository.NewRateLimiter(a.redisClient), a.policy),
+		urlStorage, repository.NewTargetIndex(a.redisClient), a.cfg.DedupeAnonymousLinks,
+	)
+	a.clicks = service.NewClickRecorder(repository.NewLinkClick(a.db), repository.NewUniqueVisitor(a.redisClient), a.analyticsSalt())
+	linkPreviewSvc := service.NewLinkPreview(urlStorage, repository.NewPageTitle(a.redisClient), pagetitle.NewFetcher(a.cfg.PreviewFetchTimeout), a.cfg.ShortUrlBase)
+
+	// Shortening is open to anonymous callers; a valid token attributes the link
+	// to its owner and grants the higher signed-in limit.
+	jwtMiddleware := middleware.NewJwtAuth(a.jwtValidator)
+	shortenLimit := middleware.NewRateLimit(repository.NewRateLimiter(a.redisClient), "shorten", middleware.RateLimitPolicy{
+		AnonymousLimit: a.cfg.ShortenLimitAnonymous,
+		UserLimit:      a.cfg.ShortenLimitUser,
+		Window:         a.cfg.ShortenLimitWindow,
+	})
+	linkShortenHandler := handler.NewLinkShorten(linkShortenSvc, a.clicks, linkPreviewSvc, a.domains, shortenLimit, a.cfg.ShortUrlBase,
+		handler.NotYetAvailable{Status: a.cfg.PendingLinkStatus, Message: a.cfg.PendingLinkMessage})
+	// Links are looked up on the custom domain the request was sent to
+	linkDomain := middleware.NewLinkDomain(a.domains)
 
 	apiVersion := a.app.Group(fmt.Sprintf("/%s", Version))
 	{
-		apiVersion.POST(routers.Endpoints.LinkShorten, linkShortenHandler.Create)
-		apiVersion.GET(routers.Endpoints.LinkRedirect, linkShortenHandler.Redirect)
+		apiVersion.POST(routers.Endpoints.LinkShorten, jwtMiddleware.OptionalJwtAuth(), shortenLimit.RateLimit(), linkShortenHandler.Create)
+		// Bulk shortening is meant for tooling and needs a signed-in caller
+		apiVersion.POST(routers.Endpoints.LinkBulk, jwtMiddleware.JwtAuth(), shortenLimit.RateLimit(), linkShortenHandler.CreateBulk)
+		apiVersion.GET(routers.Endpoints.LinkRedirect, linkDomain.Resolve(), linkShortenHandler.Redirect)
+		// Target of the password form of protected links
+		apiVersion.POST(routers.Endpoints.LinkRedirect, linkDomain.Resolve(), linkShortenHandler.Redirect)
+		apiVersion.GET(routers.Endpoints.LinkPreview, linkDomain.Resolve(), linkShortenHandler.Preview)
+	}
+	// Custom domains serve their codes at the root of the host
+	a.app.NoRoute(linkDomain.Resolve(), linkShortenHandler.RedirectCustomDomain)
+}
+
+// registerLinkQrEndpoint registers the public short link QR code endpoint.
+func (a *api) registerLinkQrEndpoint() {
+	urlStorage := repository.NewCachedUrlStorage(a.redisClient, repository.NewShortLinkStorage(a.db))
+	linkQrHandler := handler.NewLinkQr(service.NewLinkQr(urlStorage, a.cfg.ShortUrlBase))
+
+	apiVersion := a.app.Group(fmt.Sprintf("/%s", Version))
+	{
+		apiVersion.GET(routers.Endpoints.LinkQr, linkQrHandler.GetQr)
+	}
+}
+
+// registerLinkStatsEndpoint registers the short link statistics endpoint for link owners.
+func (a *api) registerLinkStatsEndpoint() {
+	linkStatsSvc := service.NewLinkStats(repository.NewShortLinkRepository(a.db), repository.NewLinkClick(a.db), repository.NewUniqueVisitor(a.redisClient))
+	linkStatsHandler := handler.NewLinkStats(linkStatsSvc)
+
+	jwtMiddleware := middleware.NewJwtAuth(a.jwtValidator)
+
+	apiPrivate := a.app.Group(fmt.Sprintf("/%s", Version))
+	apiPrivate.Use(jwtMiddleware.JwtAuth())
+	{
+		apiPrivate.GET(routers.Endpoints.LinkStats, linkStatsHandler.GetStats)
+	}
+}
+
+// registerLinkManagementEndpoint registers the endpoints letting users manage their own short links.
+func (a *api) registerLinkManagementEndpoint() {
+	linkManagementSvc := service.NewLinkManagement(repository.NewShortLinkRepository(a.db), a.linkKeys(), a.policy)
+	linkManagementHandler := handler.NewLinkManagement(linkManagementSvc)
+
+	jwtMiddleware := middleware.NewJwtAuth(a.jwtValidator)
+
+	apiPrivate := a.app.Group(fmt.Sprintf("/%s", Version))
+	apiPrivate.Use(jwtMiddleware.JwtAuth())
+	{
+		apiPrivate.GET(routers.Endpoints.MyLinks, linkManagementHandler.List)
+		apiPrivate.PATCH(routers.Endpoints.MyLink, linkManagementHandler.Update)
+		apiPrivate.DELETE(routers.Endpoints.MyLink, linkManagementHandler.Delete)
+	}
+}
+
+// registerDomainEndpoint registers the endpoints letting users manage their custom domains.
+func (a *api) registerDomainEndpoint() {
+	domainHandler := handler.NewDomain(a.domains)
+
+	jwtMiddleware := middleware.NewJwtAuth(a.jwtValidator)
+
+	apiPrivate := a.app.Group(fmt.Sprintf("/%s", Version))
+	apiPrivate.Use(jwtMiddleware.JwtAuth())
+	{
+		apiPrivate.POST(routers.Endpoints.Domains, domainHandler.Register)
+		apiPrivate.GET(routers.Endpoints.Domains, domainHandler.List)
+		apiPrivate.POST(routers.Endpoints.DomainVerify, domainHandler.Verify)
+		apiPrivate.DELETE(routers.Endpoints.Domain, domainHandler.Delete)
 	}
 }
 
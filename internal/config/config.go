@@ -1,6 +1,11 @@
 package config
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"fmt"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
 
 // Config holds the application configuration settings.
 // Configuration values are loaded from environment variables with defaults.
@@ -9,16 +14,57 @@ type Config struct {
 	ServiceName string `default:"bookmark_service" envconfig:"SERVICE_NAME"` // Name of the service
 	InstanceId  string `envconfig:"INSTANCE_ID"`                             // Unique instance identifier
 	AppHostName string `default:"localhost:8080" envconfig:"APP_HOSTNAME"`
+
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"` // IPs or CIDRs of reverse proxies whose X-Forwarded-For is believed; empty trusts none
+
+	ShortUrlBase string `default:"http://localhost:8080/v1/links/redirect/" envconfig:"SHORT_URL_BASE"` // Public prefix of short URLs, followed by the code
+
+	CodeStrategy  string `default:"random" envconfig:"CODE_STRATEGY"` // How short codes are generated: random, counter or hashids
+	CodeLength    int    `default:"8" envconfig:"CODE_LENGTH"`        // Length of random codes, minimum length of counter and hashids codes
+	CodeMaxLength int    `default:"16" envconfig:"CODE_MAX_LENGTH"`   // Random codes grow up to this length when collisions become frequent
+	CodeAlphabet  string `envconfig:"CODE_ALPHABET"`                  // Characters of short codes, base62 for random and lowercase base36 for counter and hashids codes when empty
+	CodeSalt      string `envconfig:"CODE_SALT"`                      // Secret that keys hashids codes
+
+	DedupeAnonymousLinks bool `default:"false" envconfig:"DEDUPE_ANONYMOUS_LINKS"` // Return the existing code when anonymous callers shorten the same URL again
+
+	AnalyticsSalt string `envconfig:"ANALYTICS_SALT"` // Secret mixed into visitor hashes so they cannot be reversed to IPs, random per process when empty
+
+	ShortenLimitAnonymous int64         `default:"20" envconfig:"SHORTEN_LIMIT_ANONYMOUS"` // Links an anonymous client IP may shorten per window, 0 for no limit
+	ShortenLimitUser      int64         `default:"200" envconfig:"SHORTEN_LIMIT_USER"`     // Links a signed-in user may shorten per window, 0 for no limit
+	ShortenLimitWindow    time.Duration `default:"1h" envconfig:"SHORTEN_LIMIT_WINDOW"`    // Length of the shorten rate limit window
+
+	PendingLinkStatus  int    `default:"404" envconfig:"PENDING_LINK_STATUS"`                        // HTTP status of visits to scheduled links before their not_before time, a 4xx status
+	PendingLinkMessage string `default:"Link is not yet available" envconfig:"PENDING_LINK_MESSAGE"` // Error message of those visits
+
+	PreviewFetchTimeout time.Duration `default:"3s" envconfig:"PREVIEW_FETCH_TIMEOUT"` // How long link previews wait for the destination page title
+
+	DestinationSchemes       []string `default:"http,https" envconfig:"DEST_ALLOWED_SCHEMES"` // URL schemes short links may point to
+	DestinationBlocklistFile string   `envconfig:"DEST_BLOCKLIST_FILE"`                       // File of domains short links must not point to, one per line
+	DestinationAllowlistFile string   `envconfig:"DEST_ALLOWLIST_FILE"`                       // File of the only domains short links may point to, one per line; empty allows all
 }
 
 // NewConfig creates a new Config instance by loading values from environment variables.
 // It uses the envconfig package to process environment variables with the specified prefixes.
-// Returns a pointer to Config and an error if processing fails.
+// Returns a pointer to Config and an error if processing or validation fails.
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 	err := envconfig.Process("", cfg)
 	if err != nil {
 		return nil, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
+
+// Validate checks the settings that would otherwise only fail once a request uses them.
+func (c *Config) Validate() error {
+	// Visits of scheduled links are refused; any other status would misreport them
+	// and an invalid one panics when the response is written
+	if c.PendingLinkStatus < 400 || c.PendingLinkStatus > 499 {
+		return fmt.Errorf("PENDING_LINK_STATUS must be a 4xx status, got %d", c.PendingLinkStatus)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		pendingStatus int
+		expectErr     bool
+	}{
+		{name: "not found", pendingStatus: 404},
+		{name: "too early", pendingStatus: 425},
+		{name: "success status", pendingStatus: 200, expectErr: true},
+		{name: "server error", pendingStatus: 503, expectErr: true},
+		{name: "invalid status", pendingStatus: 42, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{PendingLinkStatus: tc.pendingStatus}
+			err := cfg.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
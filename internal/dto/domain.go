@@ -0,0 +1,80 @@
+package dto
+
+import "strings"
+
+// linkKeySeparator joins the code and the custom domain of a link in its storage key.
+// Codes never contain it, so keys of different domains cannot collide.
+const linkKeySeparator = "@"
+
+// DomainRegisterRequestDto represents a request to register a custom domain
+//
+// swagger:model DomainRegisterRequestDto
+type DomainRegisterRequestDto struct {
+	// Host name short links are served from
+	// example: go.example.com
+	Name string `json:"name" binding:"required,fqdn,max=253"`
+}
+
+// DomainDto represents a custom domain registered by the caller
+//
+// swagger:model DomainDto
+type DomainDto struct {
+	// Host name
+	// example: go.example.com
+	Name string `json:"name"`
+
+	// Whether ownership was proven; only verified domains serve links
+	// example: false
+	Verified bool `json:"verified"`
+
+	// Registration timestamp
+	// example: 2026-01-01T00:00:00Z
+	CreatedAt string `json:"created_at"`
+
+	// Verification timestamp, omitted until the domain is verified
+	// example: 2026-01-01T00:10:00Z
+	VerifiedAt *string `json:"verified_at,omitempty"`
+
+	// Name of the TXT record to publish for verification
+	// example: _bookmark-verify.go.example.com
+	TxtRecordName string `json:"txt_record_name"`
+
+	// Value of the TXT record to publish for verification
+	// example: bookmark-verify=4f9c2a7d1e8b6035
+	TxtRecordValue string `json:"txt_record_value"`
+}
+
+// DomainListResponseDto represents the custom domains of the caller
+//
+// swagger:model DomainListResponseDto
+type DomainListResponseDto struct {
+	// Domains in registration order
+	Items []DomainDto `json:"items"`
+}
+
+// LinkKey returns the key a link is stored under: the code itself on the shared host,
+// "code@domain" on a custom domain.
+func LinkKey(code, domain string) string {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return code
+	}
+
+	return code + linkKeySeparator + domain
+}
+
+// NormalizeDomain returns the canonical form of a domain name: lower case without trailing dot.
+func NormalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// SplitLinkKey splits a storage key into the code and the custom domain, empty for the shared host.
+func SplitLinkKey(key string) (string, string) {
+	code, domain, _ := strings.Cut(key, linkKeySeparator)
+	return code, domain
+}
+
+// IsLinkKey reports whether the code names a link on a custom domain rather than a plain code.
+func IsLinkKey(code string) bool {
+	return strings.Contains(code, linkKeySeparator)
+}
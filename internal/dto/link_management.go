@@ -0,0 +1,192 @@
+package dto
+
+import "time"
+
+const (
+	DefaultLinkPageSize = 20
+	MaxLinkPageSize     = 100
+)
+
+// LinkListQueryDto represents query parameters for listing the caller's short links
+//
+// swagger:model LinkListQueryDto
+type LinkListQueryDto struct {
+	// Page number, starting at 1
+	// example: 1
+	Page int `form:"page" binding:"omitempty,min=1"`
+
+	// Number of links per page
+	// maximum: 100
+	// example: 20
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+
+	// Case-insensitive search in the code and the destination URL
+	// example: example.com
+	Search string `form:"q" binding:"omitempty,max=255"`
+}
+
+// Prepare fills in default values for missing query parameters.
+func (q *LinkListQueryDto) Prepare() {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultLinkPageSize
+	}
+}
+
+// LinkDto represents a short link owned by the caller
+//
+// swagger:model LinkDto
+type LinkDto struct {
+	// Short code
+	// example: abc123
+	Code string `json:"code"`
+
+	// Custom domain serving the link, omitted for links on the shared host
+	// example: go.example.com
+	Domain string `json:"domain,omitempty"`
+
+	// Destination URL
+	// example: https://example.com
+	Url string `json:"url"`
+
+	// Creation timestamp
+	// example: 2026-01-01T00:00:00Z
+	CreatedAt string `json:"created_at"`
+
+	// Expiry timestamp, omitted for links without expiry
+	// example: 2026-02-01T00:00:00Z
+	ExpiresAt *string `json:"expires_at,omitempty"`
+
+	// Time before which the link is not yet available, omitted for links live from creation
+	// example: 2026-11-01T09:00:00Z
+	NotBefore *string `json:"not_before,omitempty"`
+
+	// Whether the link is waiting for its not_before time
+	// example: false
+	Scheduled bool `json:"scheduled"`
+
+	// Whether the link has expired
+	// example: false
+	Expired bool `json:"expired"`
+
+	// Whether the link has been disabled by its owner
+	// example: false
+	Disabled bool `json:"disabled"`
+
+	// Number of redirects allowed in total, omitted for links without click limit
+	// example: 1
+	MaxClicks *int64 `json:"max_clicks,omitempty"`
+
+	// Whether visitors must enter a password
+	// example: false
+	PasswordProtected bool `json:"password_protected"`
+
+	// Whether every visit shows the preview page before redirecting
+	// example: false
+	Interstitial bool `json:"interstitial"`
+
+	// Rules and weighted variants, omitted for links without routing
+	Routing *LinkRoutingDto `json:"routing,omitempty"`
+
+	// UTM parameters merged into the destination, omitted for links without them
+	Utm *LinkUtmDto `json:"utm,omitempty"`
+
+	// HTTP status of the redirect
+	// example: 302
+	RedirectStatus int `json:"redirect_status"`
+
+	// Whether redirects ask search engines not to index the link
+	// example: false
+	NoIndex bool `json:"no_index"`
+
+	// Referrer-Policy header sent with the redirect, omitted for none
+	// example: no-referrer
+	ReferrerPolicy string `json:"referrer_policy,omitempty"`
+}
+
+// LinkListResponseDto represents one page of the caller's short links
+//
+// swagger:model LinkListResponseDto
+type LinkListResponseDto struct {
+	// Links on this page, newest first
+	Items []LinkDto `json:"items"`
+
+	// Page number
+	// example: 1
+	Page int `json:"page"`
+
+	// Number of links per page
+	// example: 20
+	PageSize int `json:"page_size"`
+
+	// Total number of matching links
+	// example: 42
+	Total int64 `json:"total"`
+}
+
+// LinkUpdateRequestDto represents request payload for updating an owned short link.
+// Omitted fields are left unchanged.
+//
+// swagger:model LinkUpdateRequestDto
+type LinkUpdateRequestDto struct {
+	// New destination URL
+	// format: url
+	// example: https://example.com/new
+	Url *string `json:"url" binding:"omitempty,url"`
+
+	// New time-to-live in seconds counted from now; 0 removes the expiry
+	// minimum: 0
+	// example: 86400
+	ExpInSeconds *int `json:"exp" binding:"omitempty,min=0"`
+
+	// New time before which the link is not yet available; a past time makes it live at once
+	// and the zero time 0001-01-01T00:00:00Z removes the schedule
+	// format: date-time
+	// example: 2026-11-01T09:00:00Z
+	NotBefore *time.Time `json:"not_before"`
+
+	// New time after which the link stops resolving; if exp is also set, the earlier end applies
+	// format: date-time
+	// example: 2026-11-08T09:00:00Z
+	NotAfter *time.Time `json:"not_after"`
+
+	// Disable or re-enable the link
+	// example: true
+	Disabled *bool `json:"disabled"`
+
+	// Show or stop showing the preview page on every visit
+	// example: true
+	Interstitial *bool `json:"interstitial"`
+
+	// New rules and weighted variants replacing the current ones; an empty object removes them
+	Routing *LinkRoutingDto `json:"routing"`
+
+	// New UTM parameters replacing the current ones; an empty object removes them
+	Utm *LinkUtmDto `json:"utm"`
+
+	// New HTTP status of the redirect
+	// enum: 301,302,307,308
+	// example: 308
+	RedirectStatus *int `json:"redirect_status" binding:"omitempty,oneof=301 302 307 308"`
+
+	// Ask or stop asking search engines not to index the link
+	// example: true
+	NoIndex *bool `json:"no_index"`
+
+	// New Referrer-Policy header sent with the redirect; an empty string removes it
+	// example: no-referrer
+	ReferrerPolicy *string `json:"referrer_policy" binding:"omitempty,referrer_policy"`
+}
+
+// ExpiresAt returns the expiry requested relative to start, or nil to remove the expiry.
+// It must only be called when ExpInSeconds or NotAfter is set.
+func (r *LinkUpdateRequestDto) ExpiresAt(start time.Time) *time.Time {
+	var expInSeconds int
+	if r.ExpInSeconds != nil {
+		expInSeconds = *r.ExpInSeconds
+	}
+
+	return expiresAt(start, expInSeconds, r.NotAfter)
+}
@@ -0,0 +1,37 @@
+package dto
+
+// LinkPreviewDto describes where a short link goes without following it
+//
+// swagger:model LinkPreviewDto
+type LinkPreviewDto struct {
+	// Short code
+	// example: abc123
+	Code string `json:"code"`
+
+	// Full short URL
+	// example: http://localhost:8080/v1/links/redirect/abc123
+	ShortUrl string `json:"short_url"`
+
+	// URL that follows the link, skipping the interstitial page
+	// example: http://localhost:8080/v1/links/redirect/abc123?confirm=1
+	ContinueUrl string `json:"continue_url"`
+
+	// Destination URL, omitted for password-protected links
+	// example: https://example.com
+	Url string `json:"url,omitempty"`
+
+	// Title of the destination page, omitted when it could not be fetched
+	// example: Example Domain
+	Title string `json:"title,omitempty"`
+
+	// Whether the destination looks suspicious
+	// example: false
+	Suspicious bool `json:"suspicious"`
+
+	// Reasons the destination looks suspicious
+	Warnings []string `json:"warnings"`
+
+	// Whether visitors must enter a password; the destination stays hidden until they do
+	// example: false
+	PasswordProtected bool `json:"password_protected"`
+}
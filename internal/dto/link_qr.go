@@ -0,0 +1,84 @@
+package dto
+
+// Supported QR code formats
+const (
+	QrFormatPng = "png"
+	QrFormatSvg = "svg"
+
+	DefaultQrSize   = 256
+	DefaultQrMargin = 4
+)
+
+// LinkQrQueryDto represents query parameters for the QR code of a short link
+//
+// swagger:model LinkQrQueryDto
+type LinkQrQueryDto struct {
+	// Storage key of the link - set from the path and Domain, not from the query string
+	Code string `form:"-"`
+
+	// Custom domain of the link, omitted for links on the shared host
+	// example: go.example.com
+	Domain string `form:"domain" binding:"omitempty,fqdn,max=253"`
+
+	// Image format
+	// enum: png,svg
+	// example: png
+	Format string `form:"format" binding:"omitempty,oneof=png svg"`
+
+	// Width and height of the image in pixels
+	// minimum: 64
+	// maximum: 2048
+	// example: 256
+	Size int `form:"size" binding:"omitempty,min=64,max=2048"`
+
+	// Width of the quiet zone around the code in modules
+	// minimum: 0
+	// maximum: 16
+	// example: 4
+	Margin *int `form:"margin" binding:"omitempty,min=0,max=16"`
+
+	// Error correction level, from the smallest code (L) to the most damage tolerant (H)
+	// enum: L,M,Q,H
+	// example: M
+	Level string `form:"ecc" binding:"omitempty,oneof=L M Q H"`
+
+	// Foreground color as six hex digits
+	// example: 000000
+	Foreground string `form:"fg" binding:"omitempty,max=7"`
+
+	// Background color as six hex digits
+	// example: ffffff
+	Background string `form:"bg" binding:"omitempty,max=7"`
+}
+
+// Prepare fills in default values for missing query parameters.
+func (q *LinkQrQueryDto) Prepare() {
+	if q.Format == "" {
+		q.Format = QrFormatPng
+	}
+	if q.Size == 0 {
+		q.Size = DefaultQrSize
+	}
+	if q.Margin == nil {
+		margin := DefaultQrMargin
+		q.Margin = &margin
+	}
+	if q.Level == "" {
+		q.Level = "M"
+	}
+	if q.Foreground == "" {
+		q.Foreground = "000000"
+	}
+	if q.Background == "" {
+		q.Background = "ffffff"
+	}
+}
+
+// ContentType returns the MIME type of the requested image format.
+func (q *LinkQrQueryDto) ContentType() string {
+	if q.Format == QrFormatSvg {
+		return "image/svg+xml"
+	}
+
+	return "image/png"
+}
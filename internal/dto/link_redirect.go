@@ -0,0 +1,25 @@
+package dto
+
+// LinkRedirectRequestDto carries what a visitor sent when following a short link
+type LinkRedirectRequestDto struct {
+	// Short code from the path
+	Code string
+
+	// Password of a protected link, empty when none was sent
+	Password string
+
+	// Whether the visitor confirmed the interstitial preview page
+	Confirmed bool
+
+	// User-Agent header, matched by device rules
+	UserAgent string
+
+	// Accept-Language header, matched by language rules
+	AcceptLanguage string
+
+	// Query parameters of the visited short URL, matched by query rules
+	Query map[string][]string
+
+	// Client IP, keeps the variant of a weighted split stable for a visitor
+	IP string
+}
@@ -0,0 +1,125 @@
+package dto
+
+import "github.com/vincent-tien/bookmark-management/pkg/routing"
+
+// LinkRoutingDto represents the rules and weighted variants sending visitors of a link
+// to other destinations. Rules are evaluated in order and the first match wins; visitors
+// no rule matches are split across the variants by weight, or sent to the link's URL
+// if there are none. Names of rules and variants show up in the link's statistics.
+//
+// swagger:model LinkRoutingDto
+type LinkRoutingDto struct {
+	// Rules evaluated in order
+	Rules []LinkRuleDto `json:"rules,omitempty" binding:"omitempty,max=20,dive"`
+
+	// Variants of a weighted split, e.g. for A/B tests. A visitor keeps getting the same variant
+	Variants []LinkVariantDto `json:"variants,omitempty" binding:"omitempty,max=10,dive"`
+}
+
+// LinkRuleDto represents a rule sending visitors matching all of its conditions to its URL.
+// At least one condition is required
+//
+// swagger:model LinkRuleDto
+type LinkRuleDto struct {
+	// Unique name of the rule, letters, digits, "-" and "_" only
+	// example: german-mobile
+	Name string `json:"name" binding:"required,max=32"`
+
+	// Destination of matching visitors
+	// format: url
+	// example: https://m.example.de
+	Url string `json:"url" binding:"required,url"`
+
+	// Device class of the visitor
+	// enum: desktop,mobile,tablet
+	// example: mobile
+	Device string `json:"device,omitempty" binding:"omitempty,oneof=desktop mobile tablet"`
+
+	// Preferred language of the visitor from Accept-Language; "de" also matches "de-AT"
+	// example: de
+	Language string `json:"language,omitempty" binding:"omitempty,max=35"`
+
+	// Query parameter the short URL must be visited with
+	// example: src
+	Query string `json:"query,omitempty" binding:"omitempty,max=64"`
+
+	// Required value of the query parameter, any value when empty
+	// example: newsletter
+	QueryValue string `json:"query_value,omitempty" binding:"omitempty,max=255"`
+}
+
+// LinkVariantDto represents one destination of a weighted split
+//
+// swagger:model LinkVariantDto
+type LinkVariantDto struct {
+	// Unique name of the variant, letters, digits, "-" and "_" only
+	// example: b
+	Name string `json:"name" binding:"required,max=32"`
+
+	// Destination of visitors in this variant
+	// format: url
+	// example: https://example.com/landing-b
+	Url string `json:"url" binding:"required,url"`
+
+	// Share of visitors relative to the other variants
+	// minimum: 1
+	// maximum: 1000
+	// example: 50
+	Weight int `json:"weight" binding:"required,min=1,max=1000"`
+}
+
+// ToRouting converts the request into the stored routing, nil if it has neither rules nor variants.
+func (d *LinkRoutingDto) ToRouting() *routing.Routing {
+	if d == nil || len(d.Rules) == 0 && len(d.Variants) == 0 {
+		return nil
+	}
+
+	r := &routing.Routing{}
+	for _, rule := range d.Rules {
+		r.Rules = append(r.Rules, routing.Rule{
+			Name:       rule.Name,
+			Url:        rule.Url,
+			Device:     rule.Device,
+			Language:   rule.Language,
+			Query:      rule.Query,
+			QueryValue: rule.QueryValue,
+		})
+	}
+	for _, variant := range d.Variants {
+		r.Variants = append(r.Variants, routing.Variant{
+			Name:   variant.Name,
+			Url:    variant.Url,
+			Weight: variant.Weight,
+		})
+	}
+
+	return r
+}
+
+// NewLinkRoutingDto converts a stored routing into its response, nil for links without routing.
+func NewLinkRoutingDto(r *routing.Routing) *LinkRoutingDto {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	d := &LinkRoutingDto{}
+	for _, rule := range r.Rules {
+		d.Rules = append(d.Rules, LinkRuleDto{
+			Name:       rule.Name,
+			Url:        rule.Url,
+			Device:     rule.Device,
+			Language:   rule.Language,
+			Query:      rule.Query,
+			QueryValue: rule.QueryValue,
+		})
+	}
+	for _, variant := range r.Variants {
+		d.Variants = append(d.Variants, LinkVariantDto{
+			Name:   variant.Name,
+			Url:    variant.Url,
+			Weight: variant.Weight,
+		})
+	}
+
+	return d
+}
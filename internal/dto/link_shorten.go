@@ -1,7 +1,11 @@
 package dto
 
+import "time"
+
 const (
 	DefaultExpInSeconds = 3600
+	// MaxBulkShortenItems caps the number of links a single bulk request may create
+	MaxBulkShortenItems = 500
 )
 
 // LinkShortenRequestDto represents request payload for creating a shortened link
@@ -15,6 +19,20 @@ type LinkShortenRequestDto struct {
 	// example: 3600
 	ExpInSeconds int `json:"exp" binding:"omitempty"`
 
+	// Optional time before which the link answers as not yet available, RFC 3339
+	// exp is counted from this time, so scheduled links get their full lifetime once live
+	//
+	// format: date-time
+	// example: 2026-11-01T09:00:00Z
+	NotBefore *time.Time `json:"not_before"`
+
+	// Optional time after which the link stops resolving, RFC 3339
+	// Replaces the default exp; if exp is also set, the earlier end applies
+	//
+	// format: date-time
+	// example: 2026-11-08T09:00:00Z
+	NotAfter *time.Time `json:"not_after"`
+
 	// Original URL that will be shortened
 	// Must be a valid URL format (http or https)
 	//
@@ -22,13 +40,98 @@ type LinkShortenRequestDto struct {
 	// format: url
 	// example: https://example.com
 	Url string `json:"url" binding:"required,url"`
+
+	// Optional custom alias used as the short code instead of a random one
+	// Letters, digits, "-" and "_" only, 3 to 32 characters, unique regardless of case
+	//
+	// example: my-launch
+	Alias string `json:"alias" binding:"omitempty,short_alias"`
+
+	// Optional number of redirects after which the link stops working and returns 410 Gone
+	// Use 1 for a one-time (burn after reading) link
+	// minimum: 1
+	// example: 1
+	MaxClicks int64 `json:"max_clicks" binding:"omitempty,min=1"`
+
+	// Show the preview page with the destination on every visit instead of redirecting immediately
+	// example: false
+	Interstitial bool `json:"interstitial"`
+
+	// Optional passphrase visitors must enter before being redirected
+	// minLength: 4
+	// At most 72 bytes, fewer characters outside ASCII
+	// maxLength: 72
+	// example: s3cret-dashboard
+	Password string `json:"password" binding:"omitempty,min=4,max_bytes=72"`
+
+	// Optional rules and weighted variants sending visitors to other destinations
+	Routing *LinkRoutingDto `json:"routing"`
+
+	// Optional UTM parameters merged into the destination when the link is followed
+	Utm *LinkUtmDto `json:"utm"`
+
+	// Optional HTTP status of the redirect, 302 when omitted
+	// 301 and 308 are permanent and may be cached by browsers, so their repeat visits are not counted
+	// enum: 301,302,307,308
+	// example: 301
+	RedirectStatus int `json:"redirect_status" binding:"omitempty,oneof=301 302 307 308"`
+
+	// Ask search engines not to index the link with an X-Robots-Tag: noindex header
+	// example: false
+	NoIndex bool `json:"no_index"`
+
+	// Optional Referrer-Policy header sent with the redirect
+	// enum: no-referrer,no-referrer-when-downgrade,origin,origin-when-cross-origin,same-origin,strict-origin,strict-origin-when-cross-origin,unsafe-url
+	// example: no-referrer
+	ReferrerPolicy string `json:"referrer_policy" binding:"omitempty,referrer_policy"`
+
+	// Optional verified custom domain of the caller serving the link instead of the shared host
+	// Aliases only need to be unique on their domain
+	//
+	// example: go.example.com
+	Domain string `json:"domain" binding:"omitempty,fqdn,max=253"`
+
+	// Password hash - set by the service from Password, not from the request payload
+	PasswordHash string `json:"-"`
+
+	// Owner ID - set from the JWT context for signed-in callers, not from the request payload
+	OwnerId string `json:"-"`
 }
 
 func (req *LinkShortenRequestDto) Prepare() {
-	// If 0 (missing or explicitly 0), set to default
-	if req.ExpInSeconds == 0 {
+	// If 0 (missing or explicitly 0), set to default, unless not_after ends the link
+	if req.ExpInSeconds == 0 && req.NotAfter == nil {
 		req.ExpInSeconds = DefaultExpInSeconds
 	}
+	// Domains are stored in their canonical form
+	req.Domain = NormalizeDomain(req.Domain)
+}
+
+// ExpiresAt returns the expiry of the link created at now, nil for none.
+// ExpInSeconds counts from the later of now and NotBefore.
+func (req *LinkShortenRequestDto) ExpiresAt(now time.Time) *time.Time {
+	start := now
+	if req.NotBefore != nil && req.NotBefore.After(now) {
+		start = *req.NotBefore
+	}
+
+	return expiresAt(start, req.ExpInSeconds, req.NotAfter)
+}
+
+// expiresAt returns the earlier of the end of a lifetime of expInSeconds from start
+// and notAfter, nil if neither is set. A non-positive expInSeconds sets no lifetime.
+func expiresAt(start time.Time, expInSeconds int, notAfter *time.Time) *time.Time {
+	var end *time.Time
+	if expInSeconds > 0 {
+		t := start.UTC().Add(time.Second * time.Duration(expInSeconds))
+		end = &t
+	}
+	if notAfter != nil && (end == nil || notAfter.Before(*end)) {
+		t := notAfter.UTC()
+		end = &t
+	}
+
+	return end
 }
 
 // LinkShortenResponseDto represents shorten link response
@@ -39,7 +142,52 @@ type LinkShortenResponseDto struct {
 	// example: abc123
 	Code string `json:"code"`
 
+	// Public URL of the link, on its custom domain if it has one
+	// example: https://go.example.com/abc123
+	ShortUrl string `json:"short_url"`
+
 	// Success message
 	// example: Shorten URL generated successfully!
 	Message string `json:"message"`
 }
+
+// LinkBulkShortenItemDto represents the outcome of one item of a bulk shorten request
+//
+// swagger:model LinkBulkShortenItemDto
+type LinkBulkShortenItemDto struct {
+	// Position of the item in the request, starting at 0
+	// example: 0
+	Index int `json:"index"`
+
+	// Original URL of the item
+	// example: https://example.com
+	Url string `json:"url"`
+
+	// Short code of the generated URL, omitted if the item failed
+	// example: abc123
+	Code string `json:"code,omitempty"`
+
+	// Public URL of the link, omitted if the item failed
+	// example: https://go.example.com/abc123
+	ShortUrl string `json:"short_url,omitempty"`
+
+	// Validation or shortening error, omitted if the item succeeded
+	// example: destination rejected: domain is blocked
+	Error string `json:"error,omitempty"`
+}
+
+// LinkBulkShortenResponseDto represents bulk shorten link response
+//
+// swagger:model LinkBulkShortenResponseDto
+type LinkBulkShortenResponseDto struct {
+	// One result per requested item, in request order
+	Items []LinkBulkShortenItemDto `json:"items"`
+
+	// Number of links created
+	// example: 2
+	Created int `json:"created"`
+
+	// Number of items that failed
+	// example: 1
+	Failed int `json:"failed"`
+}
@@ -0,0 +1,155 @@
+package dto
+
+import "time"
+
+// Supported stats granularities
+const (
+	GranularityHour  = "hour"
+	GranularityDay   = "day"
+	GranularityMonth = "month"
+
+	DefaultStatsRange = 30 * 24 * time.Hour
+)
+
+// LinkStatsQueryDto represents query parameters for short link statistics
+//
+// swagger:model LinkStatsQueryDto
+type LinkStatsQueryDto struct {
+	// Storage key of the link - set from the path and Domain, not from the query string
+	Code string `form:"-"`
+
+	// Custom domain of the link, omitted for links on the shared host
+	// example: go.example.com
+	Domain string `form:"domain" binding:"omitempty,fqdn,max=253"`
+
+	// Start of the range (inclusive, RFC3339). Defaults to 30 days before `to`
+	// example: 2026-01-01T00:00:00Z
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// End of the range (exclusive, RFC3339). Defaults to now
+	// example: 2026-01-31T00:00:00Z
+	To time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Size of the time series buckets
+	// enum: hour,day,month
+	// example: day
+	Granularity string `form:"granularity" binding:"omitempty,oneof=hour day month"`
+
+	// Whether clicks of crawlers and link-preview fetchers are counted
+	// example: false
+	IncludeBots bool `form:"include_bots"`
+}
+
+// Prepare fills in default values for missing query parameters.
+func (q *LinkStatsQueryDto) Prepare() {
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-DefaultStatsRange)
+	}
+	if q.Granularity == "" {
+		q.Granularity = GranularityDay
+	}
+	q.From = q.From.UTC()
+	q.To = q.To.UTC()
+}
+
+// LinkStatsPointDto represents the number of clicks in one time bucket
+//
+// swagger:model LinkStatsPointDto
+type LinkStatsPointDto struct {
+	// Start of the bucket
+	// example: 2026-01-01T00:00:00Z
+	Time string `json:"time"`
+
+	// Number of clicks in the bucket
+	// example: 42
+	Clicks int64 `json:"clicks"`
+}
+
+// LinkStatsBreakdownDto represents the number of clicks sharing one attribute value
+//
+// swagger:model LinkStatsBreakdownDto
+type LinkStatsBreakdownDto struct {
+	// Attribute value, e.g. a browser name or referrer host
+	// example: Chrome
+	Value string `json:"value"`
+
+	// Number of clicks with this value
+	// example: 42
+	Clicks int64 `json:"clicks"`
+}
+
+// LinkStatsVariantDto represents the number of clicks sent to one routing rule or variant
+//
+// swagger:model LinkStatsVariantDto
+type LinkStatsVariantDto struct {
+	// Name of the rule or variant, "default" for the link's own URL
+	// example: b
+	Value string `json:"value"`
+
+	// Number of clicks sent to this rule or variant
+	// example: 42
+	Clicks int64 `json:"clicks"`
+
+	// UTM parameters visitors of this rule or variant get with the link's current settings,
+	// omitted for links without UTM parameters
+	Utm *LinkUtmDto `json:"utm,omitempty"`
+}
+
+// LinkStatsResponseDto represents click statistics of a short link
+//
+// swagger:model LinkStatsResponseDto
+type LinkStatsResponseDto struct {
+	// Short code
+	// example: abc123
+	Code string `json:"code"`
+
+	// Start of the range
+	// example: 2026-01-01T00:00:00Z
+	From string `json:"from"`
+
+	// End of the range
+	// example: 2026-01-31T00:00:00Z
+	To string `json:"to"`
+
+	// Size of the time series buckets
+	// example: day
+	Granularity string `json:"granularity"`
+
+	// Whether bot clicks are included in the counts and breakdowns
+	// example: false
+	IncludeBots bool `json:"include_bots"`
+
+	// Total number of clicks in the range
+	// example: 120
+	TotalClicks int64 `json:"total_clicks"`
+
+	// Approximate number of unique visitors over the whole UTC days touched by the range.
+	// Bots are never counted as visitors
+	// example: 80
+	UniqueVisitors int64 `json:"unique_visitors"`
+
+	// Approximate number of unique visitors since the link was created
+	// example: 950
+	AllTimeUniqueVisitors int64 `json:"all_time_unique_visitors"`
+
+	// Clicks per time bucket, including empty buckets
+	Series []LinkStatsPointDto `json:"series"`
+
+	// Clicks per referrer host ("direct" when no referrer was sent)
+	Referrers []LinkStatsBreakdownDto `json:"referrers"`
+
+	// Clicks per device class ("bot" when bots are included)
+	Devices []LinkStatsBreakdownDto `json:"devices"`
+
+	// Clicks per browser family
+	Browsers []LinkStatsBreakdownDto `json:"browsers"`
+
+	// Clicks per operating system
+	OperatingSystems []LinkStatsBreakdownDto `json:"operating_systems"`
+
+	// Clicks per routing rule or variant ("default" for the link's own URL)
+	Variants []LinkStatsVariantDto `json:"variants"`
+}
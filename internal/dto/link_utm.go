@@ -0,0 +1,66 @@
+package dto
+
+import "github.com/vincent-tien/bookmark-management/pkg/utm"
+
+// LinkUtmDto represents the UTM parameters merged into the destination of a link when it is
+// followed. Parameters the destination already carries are never overwritten. Values may contain
+// the placeholders {code} and {variant}, replaced by the short code and by the routing rule or
+// variant the visitor was sent to.
+//
+// swagger:model LinkUtmDto
+type LinkUtmDto struct {
+	// Value of utm_source
+	// example: newsletter
+	Source string `json:"source,omitempty" binding:"omitempty,max=255"`
+
+	// Value of utm_medium
+	// example: email
+	Medium string `json:"medium,omitempty" binding:"omitempty,max=255"`
+
+	// Value of utm_campaign
+	// example: spring-sale
+	Campaign string `json:"campaign,omitempty" binding:"omitempty,max=255"`
+
+	// Value of utm_term
+	// example: running shoes
+	Term string `json:"term,omitempty" binding:"omitempty,max=255"`
+
+	// Value of utm_content
+	// example: {variant}
+	Content string `json:"content,omitempty" binding:"omitempty,max=255"`
+}
+
+// ToParams converts the request into the stored parameters, nil if none is set.
+func (d *LinkUtmDto) ToParams() *utm.Params {
+	if d == nil {
+		return nil
+	}
+
+	p := &utm.Params{
+		Source:   d.Source,
+		Medium:   d.Medium,
+		Campaign: d.Campaign,
+		Term:     d.Term,
+		Content:  d.Content,
+	}
+	if p.IsEmpty() {
+		return nil
+	}
+
+	return p
+}
+
+// NewLinkUtmDto converts stored parameters into their response, nil for links without UTM parameters.
+func NewLinkUtmDto(p *utm.Params) *LinkUtmDto {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	return &LinkUtmDto{
+		Source:   p.Source,
+		Medium:   p.Medium,
+		Campaign: p.Campaign,
+		Term:     p.Term,
+		Content:  p.Content,
+	}
+}
@@ -15,11 +15,12 @@ type RegisterRequestDto struct {
 	// example: john@example.com
 	Email string `json:"email" binding:"required,email"`
 
-	// User's password (minimum 8 characters, must contain uppercase, lowercase, number, and special character)
+	// User's password (minimum 8 characters, at most 72 bytes, must contain uppercase, lowercase, number, and special character)
 	// required: true
 	// minLength: 8
+	// maxLength: 72
 	// example: SecurePass123!
-	Password string `json:"password" binding:"required,min=8,strong_password"`
+	Password string `json:"password" binding:"required,min=8,max_bytes=72,strong_password"`
 
 	// User's unique username
 	// required: true
@@ -5,3 +5,20 @@ import "errors"
 var ErrKeyAlreadyExists = errors.New("key already exists")
 var ErrUrlNotFound = errors.New("url not found")
 var ErrInvalidAuth = errors.New("invalid username or password")
+var ErrAliasTaken = errors.New("alias is already taken")
+var ErrAliasReserved = errors.New("alias is reserved")
+var ErrInvalidStatsRange = errors.New("invalid stats range")
+var ErrLinkExhausted = errors.New("link has reached its click limit")
+var ErrPasswordRequired = errors.New("password required")
+var ErrWrongPassword = errors.New("wrong password")
+var ErrTooManyAttempts = errors.New("too many attempts")
+var ErrInvalidQrColor = errors.New("invalid QR code color")
+var ErrConfirmationRequired = errors.New("link requires confirmation")
+var ErrDestinationBlocked = errors.New("destination is blocked")
+var ErrDomainTaken = errors.New("domain is already registered")
+var ErrDomainReserved = errors.New("domain is reserved")
+var ErrDomainNotFound = errors.New("domain not found")
+var ErrDomainNotVerified = errors.New("domain is not verified")
+var ErrLinkNotYetActive = errors.New("link is not yet active")
+var ErrInvalidSchedule = errors.New("invalid link schedule")
+var ErrPasswordTooLong = errors.New("password is longer than 72 bytes")
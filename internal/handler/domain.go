@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/service"
+	"github.com/vincent-tien/bookmark-management/pkg/response"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+)
+
+// Domain defines the interface for the custom domain handlers.
+// It lets signed-in users register, verify and remove the domains serving their links.
+type Domain interface {
+	// Register registers a custom domain for the caller.
+	Register(c *gin.Context)
+	// List returns the caller's custom domains.
+	List(c *gin.Context)
+	// Verify checks the DNS TXT record of one of the caller's domains.
+	Verify(c *gin.Context)
+	// Delete removes one of the caller's domains.
+	Delete(c *gin.Context)
+}
+
+type domain struct {
+	svc service.Domain
+}
+
+// NewDomain creates and returns a new custom domain handler instance.
+func NewDomain(svc service.Domain) Domain {
+	return &domain{
+		svc: svc,
+	}
+}
+
+// Register registers a custom domain for the caller.
+//
+//	@Summary		Register a custom domain
+//	@Description	Register a domain to serve short links from. Publish the returned TXT record
+//	@Description	and verify the domain before creating links on it. Several users may register a domain
+//	@Description	until one of them verifies it
+//	@Tags			Domains
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.DomainRegisterRequestDto	true	"Domain to register"
+//	@Success		201		{object}	dto.DomainDto
+//	@Failure		400		{object}	response.Response	"Invalid request body or domain of this service"
+//	@Failure		401		{object}	response.Response	"Unauthorized"
+//	@Failure		409		{object}	response.Response	"Domain verified by a user or already registered by you"
+//	@Failure		500		{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/domains [post]
+func (h *domain) Register(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	var req dto.DomainRegisterRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.InputFieldError(err))
+		return
+	}
+
+	res, err := h.svc.Register(c.Request.Context(), userId, req)
+	if err != nil {
+		h.handleError(c, err, "Failed to register domain")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.Success(res))
+}
+
+// List returns the caller's custom domains.
+//
+//	@Summary		List my custom domains
+//	@Description	List the custom domains registered by the current user in registration order
+//	@Tags			Domains
+//	@Produce		json
+//	@Success		200	{object}	dto.DomainListResponseDto
+//	@Failure		401	{object}	response.Response	"Unauthorized"
+//	@Failure		500	{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/domains [get]
+func (h *domain) List(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	res, err := h.svc.ListDomains(c.Request.Context(), userId)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list domains")
+		c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(res))
+}
+
+// Verify checks the DNS TXT record of one of the caller's domains.
+//
+//	@Summary		Verify my custom domain
+//	@Description	Look up the TXT record of the domain and mark the domain verified if it carries the token.
+//	@Description	Verifying a verified domain again does nothing
+//	@Tags			Domains
+//	@Produce		json
+//	@Param			domain	path		string	true	"Domain name"
+//	@Success		200		{object}	dto.DomainDto
+//	@Failure		401		{object}	response.Response	"Unauthorized"
+//	@Failure		404		{object}	response.Response	"Domain not found"
+//	@Failure		409		{object}	response.Response	"Domain verified by another user"
+//	@Failure		422		{object}	response.Response	"TXT record missing or carrying another token"
+//	@Failure		500		{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/domains/{domain}/verify [post]
+func (h *domain) Verify(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	res, err := h.svc.Verify(c.Request.Context(), userId, c.Param("domain"))
+	if err != nil {
+		h.handleError(c, err, "Failed to verify domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(res))
+}
+
+// Delete removes one of the caller's domains.
+//
+//	@Summary		Delete my custom domain
+//	@Description	Remove a custom domain together with the links created on it
+//	@Tags			Domains
+//	@Produce		json
+//	@Param			domain	path		string	true	"Domain name"
+//	@Success		200		{object}	response.Response	"Domain deleted"
+//	@Failure		401		{object}	response.Response	"Unauthorized"
+//	@Failure		404		{object}	response.Response	"Domain not found"
+//	@Failure		500		{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/domains/{domain} [delete]
+func (h *domain) Delete(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	if err := h.svc.DeleteDomain(c.Request.Context(), userId, c.Param("domain")); err != nil {
+		h.handleError(c, err, "Failed to delete domain")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Delete domain successfully!",
+	})
+}
+
+func (h *domain) handleError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, e.ErrDomainNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	case errors.Is(err, e.ErrDomainReserved):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, e.ErrDomainTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, e.ErrDomainNotVerified):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "TXT record not found, publish it and try again"})
+		return
+	}
+
+	log.Error().Err(err).Msg(msg)
+	c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/middleware"
+	"github.com/vincent-tien/bookmark-management/internal/service/mocks"
+)
+
+func TestDomain_Register(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupRequest   func(ctx *gin.Context)
+		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.Domain
+		expectedStatus int
+		expectedResp   string
+	}{
+		{
+			name: "success case",
+			setupRequest: func(ctx *gin.Context) {
+				setupDomainRequest(ctx, http.MethodPost, "", `{"name":"go.example.com"}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Register", ctx.Request.Context(), testLinkOwnerID, dto.DomainRegisterRequestDto{Name: "go.example.com"}).
+					Return(dto.DomainDto{Name: "go.example.com", TxtRecordName: "_bookmark-verify.go.example.com"}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResp:   `"txt_record_name":"_bookmark-verify.go.example.com"`,
+		},
+		{
+			name: "bad request - not a domain name",
+			setupRequest: func(ctx *gin.Context) {
+				setupDomainRequest(ctx, http.MethodPost, "", `{"name":"not a domain"}`, testLinkOwnerID)
+			},
+			setupMockSvc:   newUnusedDomainSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "bad request - domain of this service",
+			setupRequest: func(ctx *gin.Context) {
+				setupDomainRequest(ctx, http.MethodPost, "", `{"name":"sho.rt"}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Register", ctx.Request.Context(), testLinkOwnerID, dto.DomainRegisterRequestDto{Name: "sho.rt"}).Return(dto.DomainDto{}, e.ErrDomainReserved)
+				return mockSvc
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "domain is reserved",
+		},
+		{
+			name: "conflict - domain already registered",
+			setupRequest: func(ctx *gin.Context) {
+				setupDomainRequest(ctx, http.MethodPost, "", `{"name":"go.example.com"}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Register", ctx.Request.Context(), testLinkOwnerID, dto.DomainRegisterRequestDto{Name: "go.example.com"}).Return(dto.DomainDto{}, e.ErrDomainTaken)
+				return mockSvc
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResp:   "domain is already registered",
+		},
+		{
+			name: "unauthorized - missing user id",
+			setupRequest: func(ctx *gin.Context) {
+				setupDomainRequest(ctx, http.MethodPost, "", `{"name":"go.example.com"}`, "")
+			},
+			setupMockSvc:   newUnusedDomainSvc,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   "Invalid Token",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			tc.setupRequest(ctx)
+
+			handler := NewDomain(tc.setupMockSvc(t, ctx))
+			handler.Register(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+func TestDomain_Verify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.Domain
+		expectedStatus int
+		expectedResp   string
+	}{
+		{
+			name: "success case",
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Verify", ctx.Request.Context(), testLinkOwnerID, "go.example.com").Return(dto.DomainDto{Name: "go.example.com", Verified: true}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `"verified":true`,
+		},
+		{
+			name: "unprocessable - record not published",
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Verify", ctx.Request.Context(), testLinkOwnerID, "go.example.com").Return(dto.DomainDto{}, e.ErrDomainNotVerified)
+				return mockSvc
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResp:   "TXT record not found",
+		},
+		{
+			name: "not found - domain of another user",
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Verify", ctx.Request.Context(), testLinkOwnerID, "go.example.com").Return(dto.DomainDto{}, e.ErrDomainNotFound)
+				return mockSvc
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   "Domain not found",
+		},
+		{
+			name: "internal server error - lookup failed",
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				mockSvc := mocks.NewDomain(t)
+				mockSvc.On("Verify", ctx.Request.Context(), testLinkOwnerID, "go.example.com").Return(dto.DomainDto{}, errors.New("i/o timeout"))
+				return mockSvc
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   "Something went wrong",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			setupDomainRequest(ctx, http.MethodPost, "go.example.com", "", testLinkOwnerID)
+
+			handler := NewDomain(tc.setupMockSvc(t, ctx))
+			handler.Verify(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+func TestDomain_Delete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		deleteErr      error
+		expectedStatus int
+		expectedResp   string
+	}{
+		{name: "success case", expectedStatus: http.StatusOK, expectedResp: "Delete domain successfully!"},
+		{name: "not found", deleteErr: e.ErrDomainNotFound, expectedStatus: http.StatusNotFound, expectedResp: "Domain not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			setupDomainRequest(ctx, http.MethodDelete, "go.example.com", "", testLinkOwnerID)
+			mockSvc := mocks.NewDomain(t)
+			mockSvc.On("DeleteDomain", ctx.Request.Context(), testLinkOwnerID, "go.example.com").Return(tc.deleteErr)
+
+			handler := NewDomain(mockSvc)
+			handler.Delete(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+func newUnusedDomainSvc(t *testing.T, ctx *gin.Context) *mocks.Domain {
+	return mocks.NewDomain(t)
+}
+
+// setupDomainRequest prepares a custom domain request, optionally for one domain and authenticated as userId
+func setupDomainRequest(ctx *gin.Context, method, domain, body, userId string) {
+	path := "/v1/domains"
+	if domain != "" {
+		path += "/" + domain
+		ctx.Params = gin.Params{gin.Param{Key: "domain", Value: domain}}
+	}
+	ctx.Request = httptest.NewRequest(method, path, strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	if userId != "" {
+		ctx.Set(middleware.UserIDKey, userId)
+	}
+}
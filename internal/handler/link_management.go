@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/service"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	"github.com/vincent-tien/bookmark-management/pkg/response"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
+)
+
+// LinkManagement defines the interface for the "my links" handlers.
+// It lets signed-in users list and manage the short links they created.
+type LinkManagement interface {
+	// List returns one page of the caller's links.
+	List(c *gin.Context)
+	// Update changes the destination, expiry or disabled flag of an owned link.
+	Update(c *gin.Context)
+	// Delete deletes an owned link.
+	Delete(c *gin.Context)
+}
+
+type linkManagement struct {
+	svc service.LinkManagement
+}
+
+// NewLinkManagement creates and returns a new link management handler instance.
+func NewLinkManagement(svc service.LinkManagement) LinkManagement {
+	return &linkManagement{
+		svc: svc,
+	}
+}
+
+// List returns one page of the caller's links.
+//
+//	@Summary		List my short links
+//	@Description	List the short links created by the current user, newest first, optionally filtered by code or destination
+//	@Tags			Links
+//	@Produce		json
+//	@Param			page		query		int		false	"Page number, starting at 1"
+//	@Param			page_size	query		int		false	"Links per page (max 100)"
+//	@Param			q			query		string	false	"Search in code and destination URL"
+//	@Success		200			{object}	dto.LinkListResponseDto
+//	@Failure		400			{object}	response.Response	"Invalid query parameters"
+//	@Failure		401			{object}	response.Response	"Unauthorized"
+//	@Failure		500			{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/links [get]
+func (h *linkManagement) List(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	var q dto.LinkListQueryDto
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, response.InputFieldError(err))
+		return
+	}
+	q.Prepare()
+
+	res, err := h.svc.ListLinks(c.Request.Context(), userId, q)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list links")
+		c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(res))
+}
+
+// Update changes the destination, expiry or disabled flag of an owned link.
+//
+//	@Summary		Update my short link
+//	@Description	Change the destination, extend or remove the expiry, disable/enable an owned short link or toggle its interstitial preview page
+//	@Tags			Links
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string						true	"Short code"
+//	@Param			domain	query		string						false	"Custom domain of the link"
+//	@Param			request	body		dto.LinkUpdateRequestDto	true	"Fields to change"
+//	@Success		200		{object}	dto.LinkDto
+//	@Failure		400		{object}	response.Response	"Invalid request body, validation error or rejected destination"
+//	@Failure		401		{object}	response.Response	"Unauthorized"
+//	@Failure		404		{object}	response.Response	"Link not found"
+//	@Failure		500		{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/links/{code} [patch]
+func (h *linkManagement) Update(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	var req dto.LinkUpdateRequestDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.InputFieldError(err))
+		return
+	}
+
+	res, err := h.svc.UpdateLink(c.Request.Context(), userId, dto.LinkKey(c.Param("code"), c.Query("domain")), req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update link")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(res))
+}
+
+// Delete deletes an owned link.
+//
+//	@Summary		Delete my short link
+//	@Description	Delete an owned short link together with its click statistics
+//	@Tags			Links
+//	@Produce		json
+//	@Param			code	path		string	true	"Short code"
+//	@Param			domain	query		string	false	"Custom domain of the link"
+//	@Success		200		{object}	response.Response	"Link deleted"
+//	@Failure		401		{object}	response.Response	"Unauthorized"
+//	@Failure		404		{object}	response.Response	"Link not found"
+//	@Failure		500		{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/links/{code} [delete]
+func (h *linkManagement) Delete(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	if err := h.svc.DeleteLink(c.Request.Context(), userId, dto.LinkKey(c.Param("code"), c.Query("domain"))); err != nil {
+		h.handleError(c, err, "Failed to delete link")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Delete link successfully!",
+	})
+}
+
+func (h *linkManagement) handleError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, e.ErrUrlNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		return
+	case errors.Is(err, destpolicy.ErrRejected), errors.Is(err, routing.ErrInvalidRouting),
+		errors.Is(err, utm.ErrInvalidTemplate), errors.Is(err, e.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Error().Err(err).Msg(msg)
+	c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+}
@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/middleware"
+	"github.com/vincent-tien/bookmark-management/internal/service/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+)
+
+const testLinkOwnerID = "deb745af-1a62-4efa-99a0-f06b274bd993"
+
+func TestLinkManagement_List(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupRequest   func(ctx *gin.Context)
+		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement
+		expectedStatus int
+		expectedResp   string
+	}{
+		{
+			name: "success case",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodGet, "", "?page=2&page_size=5&q=golang", "", testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("ListLinks", ctx.Request.Context(), testLinkOwnerID, dto.LinkListQueryDto{Page: 2, PageSize: 5, Search: "golang"}).
+					Return(dto.LinkListResponseDto{Items: []dto.LinkDto{{Code: "abc"}}, Page: 2, PageSize: 5, Total: 6}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `"total":6`,
+		},
+		{
+			name: "defaults are applied",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodGet, "", "", "", testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("ListLinks", ctx.Request.Context(), testLinkOwnerID, dto.LinkListQueryDto{Page: 1, PageSize: dto.DefaultLinkPageSize}).
+					Return(dto.LinkListResponseDto{Items: []dto.LinkDto{}, Page: 1, PageSize: dto.DefaultLinkPageSize}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `"items":[]`,
+		},
+		{
+			name: "bad request - page size too large",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodGet, "", "?page_size=1000", "", testLinkOwnerID)
+			},
+			setupMockSvc:   newUnusedLinkManagementSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "unauthorized - missing user id",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodGet, "", "", "", "")
+			},
+			setupMockSvc:   newUnusedLinkManagementSvc,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   "Invalid Token",
+		},
+		{
+			name: "internal server error",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodGet, "", "", "", testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("ListLinks", ctx.Request.Context(), testLinkOwnerID, mock.Anything).Return(dto.LinkListResponseDto{}, errors.New("database error"))
+				return mockSvc
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   "Something went wrong",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			tc.setupRequest(ctx)
+
+			handler := NewLinkManagement(tc.setupMockSvc(t, ctx))
+			handler.List(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+func TestLinkManagement_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupRequest   func(ctx *gin.Context)
+		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement
+		expectedStatus int
+		expectedResp   string
+	}{
+		{
+			name: "success case",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{"url":"https://go.dev","exp":0,"disabled":true}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("UpdateLink", ctx.Request.Context(), testLinkOwnerID, "abc", mock.MatchedBy(func(r dto.LinkUpdateRequestDto) bool {
+					return *r.Url == "https://go.dev" && *r.ExpInSeconds == 0 && *r.Disabled
+				})).Return(dto.LinkDto{Code: "abc", Url: "https://go.dev", Disabled: true}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `"disabled":true`,
+		},
+		{
+			name: "bad request - invalid url",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{"url":"not a url"}`, testLinkOwnerID)
+			},
+			setupMockSvc:   newUnusedLinkManagementSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Url is invalid url",
+		},
+		{
+			name: "bad request - negative expiry",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{"exp":-1}`, testLinkOwnerID)
+			},
+			setupMockSvc:   newUnusedLinkManagementSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "ExpInSeconds is invalid min",
+		},
+		{
+			name: "unauthorized - missing user id",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{}`, "")
+			},
+			setupMockSvc:   newUnusedLinkManagementSvc,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   "Invalid Token",
+		},
+		{
+			name: "not found",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{"disabled":false}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("UpdateLink", ctx.Request.Context(), testLinkOwnerID, "abc", mock.Anything).Return(dto.LinkDto{}, e.ErrUrlNotFound)
+				return mockSvc
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   "URL not found",
+		},
+		{
+			name: "bad request - destination rejected",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{"url":"https://evil.example"}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("UpdateLink", ctx.Request.Context(), testLinkOwnerID, "abc", mock.Anything).Return(dto.LinkDto{}, destpolicy.ErrDomainBlocked)
+				return mockSvc
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "destination rejected: domain is blocked",
+		},
+		{
+			name: "internal server error",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodPatch, "abc", "", `{"disabled":false}`, testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("UpdateLink", ctx.Request.Context(), testLinkOwnerID, "abc", mock.Anything).Return(dto.LinkDto{}, errors.New("database error"))
+				return mockSvc
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   "Something went wrong",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			tc.setupRequest(ctx)
+
+			handler := NewLinkManagement(tc.setupMockSvc(t, ctx))
+			handler.Update(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+func TestLinkManagement_Delete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupRequest   func(ctx *gin.Context)
+		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement
+		expectedStatus int
+		expectedResp   string
+	}{
+		{
+			name: "success case",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodDelete, "abc", "", "", testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("DeleteLink", ctx.Request.Context(), testLinkOwnerID, "abc").Return(nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   "Delete link successfully!",
+		},
+		{
+			name: "success case - link on a custom domain",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodDelete, "abc", "?domain=go.example.com", "", testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("DeleteLink", ctx.Request.Context(), testLinkOwnerID, "abc@go.example.com").Return(nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   "Delete link successfully!",
+		},
+		{
+			name: "unauthorized - missing user id",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodDelete, "abc", "", "", "")
+			},
+			setupMockSvc:   newUnusedLinkManagementSvc,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   "Invalid Token",
+		},
+		{
+			name: "not found",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkManagementRequest(ctx, http.MethodDelete, "abc", "", "", testLinkOwnerID)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+				mockSvc := mocks.NewLinkManagement(t)
+				mockSvc.On("DeleteLink", ctx.Request.Context(), testLinkOwnerID, "abc").Return(e.ErrUrlNotFound)
+				return mockSvc
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   "URL not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			tc.setupRequest(ctx)
+
+			handler := NewLinkManagement(tc.setupMockSvc(t, ctx))
+			handler.Delete(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+// newUnusedLinkManagementSvc returns a mock service that fails the test if it is called
+func newUnusedLinkManagementSvc(t *testing.T, ctx *gin.Context) *mocks.LinkManagement {
+	return mocks.NewLinkManagement(t)
+}
+
+// setupLinkManagementRequest prepares a "my links" request, optionally for one code and authenticated as userId
+func setupLinkManagementRequest(ctx *gin.Context, method, code, query, body, userId string) {
+	path := "/v1/links"
+	if code != "" {
+		path += "/" + code
+		ctx.Params = gin.Params{gin.Param{Key: "code", Value: code}}
+	}
+	ctx.Request = httptest.NewRequest(method, path+query, strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	if userId != "" {
+		ctx.Set(middleware.UserIDKey, userId)
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/rs/zerolog/log"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/pkg/response"
+)
+
+// previewTemplate shows browsers where a link goes before they follow it.
+var previewTemplate = template.Must(template.New("link_preview").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Link preview</title></head>
+<body>
+<p>The link {{.ShortUrl}} leads to:</p>
+{{if .PasswordProtected}}<p>A password protected page.</p>
+{{else}}{{if .Title}}<h1>{{.Title}}</h1>
+{{end}}<p><code>{{.Url}}</code></p>
+{{end}}{{if .Suspicious}}<div role="alert">
+<p>Be careful, this link looks suspicious:</p>
+<ul>{{range .Warnings}}<li>{{.}}</li>{{end}}</ul>
+</div>
+{{end}}<p><a href="{{.ContinueUrl}}" rel="noreferrer">Continue</a></p>
+</body>
+</html>`))
+
+// Preview LinkPreview godoc
+//
+// @Summary      Preview a short link
+// @Description  Shows the destination URL, its page title and warnings for suspicious destinations
+// @Description  without following the link or counting a click. Browsers get an HTML page.
+// @Description  The destination of password-protected links is not revealed.
+// @Tags         Links
+// @Produce      json,html
+// @Param        code path string true "Short code"
+// @Success      200 {object} dto.LinkPreviewDto
+// @Failure      404 {object} dto.ErrorResponse "URL not found"
+// @Failure      500 {object} response.Response "Internal server error"
+// @Router       /v1/links/preview/{code} [get]
+func (s *linkShorten) Preview(c *gin.Context) {
+	s.renderPreview(c, c.Param("code"))
+}
+
+// renderPreview answers with the preview of the link, as a page for browsers and as JSON otherwise.
+// The code is looked up on the custom domain serving the request.
+func (s *linkShorten) renderPreview(c *gin.Context, code string) {
+	key, ok := linkKey(c, code)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		return
+	}
+
+	res, err := s.preview.Preview(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, e.ErrUrlNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+			return
+		}
+		if errors.Is(err, e.ErrLinkNotYetActive) {
+			s.notYetAvailable(c)
+			return
+		}
+
+		log.Error().Err(err).Msg("Failed to preview link")
+		c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+		return
+	}
+
+	// Previews must not be served from a cache once the link changes
+	c.Header("Cache-Control", "no-store")
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		c.Render(http.StatusOK, render.HTML{Template: previewTemplate, Data: res})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(res))
+}
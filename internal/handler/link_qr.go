@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/service"
+	"github.com/vincent-tien/bookmark-management/pkg/response"
+)
+
+// qrCacheControl lets browsers and CDNs keep QR codes for a day; the image only
+// depends on the short URL and the query, so it never goes stale while the link exists.
+const qrCacheControl = "public, max-age=86400"
+
+// LinkQr defines the interface for short link QR code handlers.
+type LinkQr interface {
+	// GetQr returns the QR code image of a short link.
+	GetQr(c *gin.Context)
+}
+
+type linkQr struct {
+	svc service.LinkQr
+}
+
+// NewLinkQr creates and returns a new QR code handler instance.
+func NewLinkQr(svc service.LinkQr) LinkQr {
+	return &linkQr{
+		svc: svc,
+	}
+}
+
+// GetQr returns the QR code image of a short link.
+//
+//	@Summary		Get short link QR code
+//	@Description	Render the QR code of the full short URL as a PNG or SVG image.
+//	@Description	Responses carry an ETag and are answered with 304 when If-None-Match matches
+//	@Tags			Links
+//	@Produce		png,image/svg+xml,json
+//	@Param			code	path		string	true	"Short code"
+//	@Param			format	query		string	false	"Image format, defaults to png"	Enums(png, svg)
+//	@Param			size	query		int		false	"Width and height in pixels, defaults to 256"	minimum(64)	maximum(2048)
+//	@Param			margin	query		int		false	"Quiet zone in modules, defaults to 4"	minimum(0)	maximum(16)
+//	@Param			ecc		query		string	false	"Error correction level, defaults to M"	Enums(L, M, Q, H)
+//	@Param			fg		query		string	false	"Foreground color as six hex digits, defaults to 000000"
+//	@Param			bg		query		string	false	"Background color as six hex digits, defaults to ffffff"
+//	@Param			domain	query		string	false	"Custom domain of the link"
+//	@Success		200		{file}		binary	"QR code image"
+//	@Success		304		"Not modified"
+//	@Failure		400		{object}	response.Response	"Invalid query parameters"
+//	@Failure		404		{object}	response.Response	"Link not found"
+//	@Failure		500		{object}	response.Response	"Internal server error"
+//	@Router			/v1/links/{code}/qr [get]
+func (h *linkQr) GetQr(c *gin.Context) {
+	var q dto.LinkQrQueryDto
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, response.InputFieldError(err))
+		return
+	}
+	q.Code = dto.LinkKey(c.Param("code"), q.Domain)
+	q.Prepare()
+
+	img, err := h.svc.Render(c.Request.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, e.ErrInvalidQrColor):
+			c.JSON(http.StatusBadRequest, response.InvalidRequestError)
+		case errors.Is(err, e.ErrUrlNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		default:
+			log.Error().Err(err).Msg("Failed to render QR code")
+			c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+		}
+		return
+	}
+
+	sum := sha256.Sum256(img)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", qrCacheControl)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, q.ContentType(), img)
+}
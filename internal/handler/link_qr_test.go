@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/service/mocks"
+)
+
+// testQrEtag is the ETag of the image "qr-image"
+const testQrEtag = `"d431e1da75c75078f8403614c2b53ce8"`
+
+func TestLinkQr_GetQr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		setupRequest    func(ctx *gin.Context)
+		setupMockSvc    func(t *testing.T, ctx *gin.Context) *mocks.LinkQr
+		expectedStatus  int
+		expectedResp    string
+		expectedType    string
+		expectedHeaders map[string]string
+	}{
+		{
+			name: "success case - defaults",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+				mockSvc := mocks.NewLinkQr(t)
+				mockSvc.On("Render", ctx.Request.Context(), mock.MatchedBy(func(q dto.LinkQrQueryDto) bool {
+					return q.Code == "abc" && q.Format == dto.QrFormatPng && q.Size == dto.DefaultQrSize &&
+						*q.Margin == dto.DefaultQrMargin && q.Level == "M" && q.Foreground == "000000" && q.Background == "ffffff"
+				})).Return([]byte("qr-image"), nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   "qr-image",
+			expectedType:   "image/png",
+			expectedHeaders: map[string]string{
+				"ETag":          testQrEtag,
+				"Cache-Control": "public, max-age=86400",
+			},
+		},
+		{
+			name: "success case - svg without margin",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "?format=svg&size=512&margin=0&ecc=H&fg=1a2b3c&bg=fafafa")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+				mockSvc := mocks.NewLinkQr(t)
+				mockSvc.On("Render", ctx.Request.Context(), mock.MatchedBy(func(q dto.LinkQrQueryDto) bool {
+					return q.Format == dto.QrFormatSvg && q.Size == 512 && *q.Margin == 0 &&
+						q.Level == "H" && q.Foreground == "1a2b3c" && q.Background == "fafafa"
+				})).Return([]byte("<svg/>"), nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   "<svg/>",
+			expectedType:   "image/svg+xml",
+		},
+		{
+			name: "not modified - matching ETag",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "")
+				ctx.Request.Header.Set("If-None-Match", testQrEtag)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+				mockSvc := mocks.NewLinkQr(t)
+				mockSvc.On("Render", ctx.Request.Context(), mock.Anything).Return([]byte("qr-image"), nil)
+				return mockSvc
+			},
+			expectedStatus:  http.StatusNotModified,
+			expectedHeaders: map[string]string{"ETag": testQrEtag},
+		},
+		{
+			name: "bad request - size too large",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "?size=10000")
+			},
+			setupMockSvc:   newUnusedLinkQrSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "bad request - unknown error correction level",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "?ecc=X")
+			},
+			setupMockSvc:   newUnusedLinkQrSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "bad request - invalid color",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "?fg=red")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+				mockSvc := mocks.NewLinkQr(t)
+				mockSvc.On("Render", ctx.Request.Context(), mock.Anything).Return(nil, e.ErrInvalidQrColor)
+				return mockSvc
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "not found",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+				mockSvc := mocks.NewLinkQr(t)
+				mockSvc.On("Render", ctx.Request.Context(), mock.Anything).Return(nil, e.ErrUrlNotFound)
+				return mockSvc
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   "URL not found",
+		},
+		{
+			name: "internal server error",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkQrRequest(ctx, "abc", "")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+				mockSvc := mocks.NewLinkQr(t)
+				mockSvc.On("Render", ctx.Request.Context(), mock.Anything).Return(nil, errors.New("database error"))
+				return mockSvc
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   "Something went wrong",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			tc.setupRequest(ctx)
+
+			mockSvc := tc.setupMockSvc(t, ctx)
+			handler := NewLinkQr(mockSvc)
+			handler.GetQr(ctx)
+			// Flush the status like the engine does, 304 responses have no body
+			ctx.Writer.WriteHeaderNow()
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+			if tc.expectedType != "" {
+				assert.Equal(t, tc.expectedType, rec.Header().Get("Content-Type"))
+			}
+			for key, value := range tc.expectedHeaders {
+				assert.Equal(t, value, rec.Header().Get(key))
+			}
+		})
+	}
+}
+
+// newUnusedLinkQrSvc returns a mock service that fails the test if it is called
+func newUnusedLinkQrSvc(t *testing.T, ctx *gin.Context) *mocks.LinkQr {
+	return mocks.NewLinkQr(t)
+}
+
+// setupLinkQrRequest prepares a QR code request for the code
+func setupLinkQrRequest(ctx *gin.Context, code, query string) {
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/"+code+"/qr"+query, nil)
+	ctx.Params = gin.Params{gin.Param{Key: "code", Value: code}}
+}
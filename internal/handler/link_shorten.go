@@ -1,52 +1,145 @@
 package handler
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"html/template"
+	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
 	"github.com/rs/zerolog/log"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
 	e "github.com/vincent-tien/bookmark-management/internal/errors"
 	"github.com/vincent-tien/bookmark-management/internal/service"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
 )
 
+const (
+	// linkPasswordHeader carries the password of a protected link for API clients.
+	linkPasswordHeader = "X-Link-Password"
+
+	// maxBulkBodyBytes caps the size of a bulk shorten request, JSON or CSV.
+	maxBulkBodyBytes = 2 << 20
+)
+
+// bulkCsvColumns are the CSV header names understood by bulk shortening, named after
+// the JSON fields of dto.LinkShortenRequestDto and, for UTM parameters, their query names.
+var bulkCsvColumns = []string{
+	"url", "exp", "alias", "max_clicks", "interstitial", "password", "domain",
+	"redirect_status", "no_index", "referrer_policy", "not_before", "not_after",
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+}
+
+// passwordFormTemplate is shown to browsers opening a password-protected link.
+var passwordFormTemplate = template.Must(template.New("link_password").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Password required</title></head>
+<body>
+<form method="post">
+<p>{{.Message}}: the link {{.Code}} is password protected.</p>
+<input type="password" name="password" autofocus required>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>`))
+
 // LinkShorten defines the interface for link shortening handlers.
 // It provides methods to handle link shortening operations.
 type LinkShorten interface {
 	// Create handles the creation of a shortened link.
 	// It validates the request, generates a short code, and stores the mapping.
 	Create(c *gin.Context)
+	// CreateBulk handles the creation of many shortened links from a JSON array or a CSV upload.
+	// Every item is validated and shortened on its own and gets its own result.
+	CreateBulk(c *gin.Context)
 	// Redirect handles the redirection to the original URL based on the code.
 	// It retrieves the original URL and redirects the user to it.
+	// On a custom domain the code names the link of that domain.
 	Redirect(c *gin.Context)
+	// RedirectCustomDomain serves the short URLs of custom domains, whose codes sit at the root
+	// of the host. Requests to other hosts get 404 Not Found.
+	RedirectCustomDomain(c *gin.Context)
+	// Preview shows where a short link goes without following it.
+	Preview(c *gin.Context)
+}
+
+// NotYetAvailable is the answer to visits of scheduled links before they go live.
+type NotYetAvailable struct {
+	// Status is the HTTP status, e.g. 404 to not reveal that the link exists
+	Status int
+	// Message is the error message of the JSON body
+	Message string
+}
+
+// QuotaCharger charges requests doing the work of many against the caller's rate limit.
+// It is implemented by middleware.RateLimit.
+type QuotaCharger interface {
+	// Charge counts n further requests of the caller; if they exceed the remaining quota
+	// the request is aborted with 429 Too Many Requests and false is returned.
+	Charge(c *gin.Context, n int64) bool
 }
 
 type linkShorten struct {
-	svc service.UrlShorten
+	svc       service.UrlShorten
+	recorder  service.ClickRecorder
+	preview   service.LinkPreview
+	domains   service.Domain
+	bulkQuota QuotaCharger
+	baseUrl   string
+	pending   NotYetAvailable
 }
 
 // NewLinkShorten creates and returns a new link shortening handler instance.
-// It initializes the handler with a URL shortening service.
+// It initializes the handler with a URL shortening service, a click recorder
+// that receives every successful redirect, the preview service behind
+// preview and interstitial pages, the custom domain service checking the
+// domains links are created on, the shorten rate limit every item of a
+// bulk request is charged against, the public prefix short URLs on the
+// shared host start with, e.g. https://sho.rt/, and the answer to visits
+// of scheduled links before they go live.
 // Returns a LinkShorten interface implementation.
-func NewLinkShorten(svc service.UrlShorten) LinkShorten {
+func NewLinkShorten(svc service.UrlShorten, recorder service.ClickRecorder, preview service.LinkPreview, domains service.Domain, bulkQuota QuotaCharger, baseUrl string, pending NotYetAvailable) LinkShorten {
 	return &linkShorten{
-		svc: svc,
+		svc:       svc,
+		recorder:  recorder,
+		preview:   preview,
+		domains:   domains,
+		bulkQuota: bulkQuota,
+		baseUrl:   baseUrl,
+		pending:   pending,
 	}
 }
 
 // Create CreateShortLink godoc
 //
 // @Summary      Create a shortened link
-// @Description  Generate a short URL with expiration time
+// @Description  Generate a short URL with expiration time. Anonymous callers are allowed;
+// @Description  with a bearer token the link is owned by the caller and a higher rate limit applies.
+// @Description  Signed-in callers shortening the same URL with the same options again get the existing code.
+// @Description  Signed-in callers may create the link on one of their verified custom domains
 // @Tags         Links
 // @Accept       json
 // @Produce      json
 // @Param        request body dto.LinkShortenRequestDto true "Shorten link request payload"
 // @Success      200 {object} dto.LinkShortenResponseDto
-// @Failure      400 {object} dto.ErrorResponse "Invalid request body or validation error"
+// @Failure      400 {object} dto.ErrorResponse "Invalid request body, validation error, reserved alias, rejected destination, unknown or unverified domain"
+// @Failure      401 {object} dto.ErrorResponse "Invalid bearer token or custom domain without bearer token"
+// @Failure      409 {object} dto.ErrorResponse "Alias already taken"
+// @Failure      429 {object} dto.ErrorResponse "Rate limit exceeded"
 // @Failure      500 {object} dto.ErrorResponse "Internal server error"
+// @Security     BearerAuth
 // @Router       /v1/links/shorten [post]
 func (s *linkShorten) Create(c *gin.Context) {
 	var req dto.LinkShortenRequestDto
@@ -57,34 +150,183 @@ func (s *linkShorten) Create(c *gin.Context) {
 	}
 
 	req.Prepare()
+	// Attribute the link to signed-in callers so it shows up in their links
+	if userId, ok := utils.GetUserIDFromContext(c); ok {
+		req.OwnerId = userId
+	}
+	if req.Domain != "" && req.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Custom domains require a signed-in caller"})
+		return
+	}
+	if req.Domain != "" {
+		err = s.domains.CheckUsable(c.Request.Context(), req.OwnerId, req.Domain)
+	}
 
-	code, err := s.svc.Shorten(c, req)
+	var code string
+	if err == nil {
+		code, err = s.svc.Shorten(c.Request.Context(), req)
+	}
 
 	if err != nil {
+		switch {
+		case errors.Is(err, e.ErrAliasReserved), errors.Is(err, destpolicy.ErrRejected),
+			errors.Is(err, routing.ErrInvalidRouting), errors.Is(err, utm.ErrInvalidTemplate),
+			errors.Is(err, e.ErrDomainNotFound), errors.Is(err, e.ErrDomainNotVerified),
+			errors.Is(err, e.ErrInvalidSchedule), errors.Is(err, e.ErrPasswordTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, e.ErrAliasTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
 		log.Error().Err(err).Msg("Failed to shorten URL")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
 
 	res := dto.LinkShortenResponseDto{
-		Code:    code,
-		Message: "Shorten URL generated successfully!",
+		Code:     code,
+		ShortUrl: service.ShortUrl(s.baseUrl, dto.LinkKey(code, req.Domain)),
+		Message:  "Shorten URL generated successfully!",
 	}
 	c.JSON(http.StatusCreated, res)
 }
 
+// CreateBulk CreateShortLinks godoc
+//
+// @Summary      Create many shortened links
+// @Description  Shorten up to 500 URLs at once, sent as a JSON array of link requests or as CSV,
+// @Description  either as a text/csv body or as a multipart upload in the file field. The CSV header
+// @Description  names the columns after the JSON fields: url, exp, alias, max_clicks, interstitial, password, domain,
+// @Description  redirect_status, no_index, referrer_policy, not_before and not_after (RFC 3339),
+// @Description  and UTM parameters after their query names, e.g. utm_source.
+// @Description  Every item is validated and shortened on its own; failed items carry an error instead of a code.
+// @Description  Items on a custom domain need a verified domain of the caller.
+// @Description  Bulk requests are not deduplicated and every item counts against the shorten rate limit;
+// @Description  requests with more items than the remaining quota are rejected as a whole
+// @Tags         Links
+// @Accept       json,text/csv,mpfd
+// @Produce      json
+// @Param        request body []dto.LinkShortenRequestDto false "Links to shorten"
+// @Param        file formData file false "CSV file with a header row"
+// @Success      200 {object} dto.LinkBulkShortenResponseDto "Per-item codes or errors"
+// @Failure      400 {object} dto.ErrorResponse "Malformed body, unknown CSV column, no items or too many items"
+// @Failure      401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure      429 {object} dto.ErrorResponse "Rate limit exceeded or more items than the remaining quota"
+// @Failure      500 {object} dto.ErrorResponse "Internal server error"
+// @Security     BearerAuth
+// @Router       /v1/links/shorten/bulk [post]
+func (s *linkShorten) CreateBulk(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkBodyBytes)
+	items, err := readBulkItems(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No links to shorten"})
+		return
+	}
+	if len(items) > dto.MaxBulkShortenItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d links can be shortened at once", dto.MaxBulkShortenItems)})
+		return
+	}
+	// The rate limit middleware already counted the request as the first item
+	if !s.bulkQuota.Charge(c, int64(len(items)-1)) {
+		return
+	}
+
+	userId, _ := utils.GetUserIDFromContext(c)
+	res := dto.LinkBulkShortenResponseDto{Items: make([]dto.LinkBulkShortenItemDto, len(items))}
+	// Items mostly share a domain, so each domain is checked once
+	domainErrs := map[string]error{}
+	var reqs []dto.LinkShortenRequestDto
+	var positions []int
+	for i, item := range items {
+		res.Items[i] = dto.LinkBulkShortenItemDto{Index: i, Url: item.req.Url}
+		if item.err == nil {
+			item.err = binding.Validator.ValidateStruct(&item.req)
+		}
+		if item.err != nil {
+			res.Items[i].Error = item.err.Error()
+			continue
+		}
+
+		item.req.Prepare()
+		item.req.OwnerId = userId
+		if item.req.Domain != "" {
+			domainErr, checked := domainErrs[item.req.Domain]
+			if !checked {
+				domainErr = s.domains.CheckUsable(c.Request.Context(), userId, item.req.Domain)
+				domainErrs[item.req.Domain] = domainErr
+			}
+			if domainErr != nil {
+				res.Items[i].Error = bulkItemError(domainErr)
+				continue
+			}
+		}
+		reqs = append(reqs, item.req)
+		positions = append(positions, i)
+	}
+
+	if len(reqs) > 0 {
+		results, err := s.svc.ShortenMany(c.Request.Context(), reqs)
+		if err != nil {
+			log.Error().Err(err).Int("links", len(reqs)).Msg("Failed to shorten URLs")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		for j, i := range positions {
+			if results[j].Err != nil {
+				res.Items[i].Error = bulkItemError(results[j].Err)
+				continue
+			}
+			res.Items[i].Code = results[j].Code
+			res.Items[i].ShortUrl = service.ShortUrl(s.baseUrl, dto.LinkKey(results[j].Code, reqs[j].Domain))
+		}
+	}
+
+	for _, item := range res.Items {
+		if item.Error != "" {
+			res.Failed++
+		} else {
+			res.Created++
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
 // Redirect RedirectLink godoc
 //
 // @Summary      Redirect to original URL
-// @Description  Redirects user to the original URL based on the short code
+// @Description  Redirects user to the original URL based on the short code.
+// @Description  Appending "+" to the code shows the preview page instead, as does every unconfirmed
+// @Description  visit of a link with an interstitial; confirm with the confirm=1 query parameter.
+// @Description  Password-protected links expect the password in the X-Link-Password header or the
+// @Description  password form field; browsers get an HTML password form instead of a JSON error.
+// @Description  Requests to a verified custom domain follow the link of that domain.
+// @Description  Links redirect with 302 unless created with 301, 307 or 308; permanent redirects without
+// @Description  click limit, password, interstitial or routing may be cached, all others are sent with
+// @Description  Cache-Control: no-store.
 // @Tags         Links
-// @Accept       json
-// @Produce      json
+// @Accept       json,x-www-form-urlencoded
+// @Produce      json,html
 // @Param        code path string true "Short code"
+// @Param        X-Link-Password header string false "Password of a protected link"
+// @Param        confirm query string false "Set to 1 to skip the interstitial page"
+// @Success      200 {object} dto.LinkPreviewDto "Preview or interstitial page"
+// @Success      301 "Permanent redirect to original URL, if the link was created with this status"
 // @Success      302 "Redirect to original URL"
-// @Failure      404 {object} dto.ErrorResponse "URL not found"
+// @Success      303 "Redirect to original URL after the password form was submitted"
+// @Failure      401 {object} dto.ErrorResponse "Password required or wrong password"
+// @Failure      403 {object} dto.ErrorResponse "Destination is blocked"
+// @Failure      404 {object} dto.ErrorResponse "URL not found, or scheduled link not yet live (status configurable)"
+// @Failure      410 {object} dto.ErrorResponse "Click limit reached"
+// @Failure      429 {object} dto.ErrorResponse "Too many password attempts from this client"
 // @Failure      500 {object} dto.ErrorResponse "Internal server error"
 // @Router       /v1/links/redirect/{code} [get]
+// @Router       /v1/links/redirect/{code} [post]
 func (s *linkShorten) Redirect(c *gin.Context) {
 	rawCode := c.Param("code")
 	code := strings.TrimPrefix(rawCode, "/")
@@ -93,12 +335,60 @@ func (s *linkShorten) Redirect(c *gin.Context) {
 		return
 	}
 
-	url, err := s.svc.GetUrl(c, code)
+	// A trailing "+" asks for the preview instead of the destination
+	if previewCode, ok := strings.CutSuffix(code, "+"); ok {
+		s.renderPreview(c, previewCode)
+		return
+	}
+
+	key, ok := linkKey(c, code)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		return
+	}
+
+	req := dto.LinkRedirectRequestDto{
+		Code:      key,
+		Password:  c.GetHeader(linkPasswordHeader),
+		Confirmed: c.Query("confirm") == "1",
+		// Routing rules match on these
+		UserAgent:      c.Request.UserAgent(),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		Query:          c.Request.URL.Query(),
+		IP:             c.ClientIP(),
+	}
+	if req.Password == "" {
+		req.Password = c.PostForm("password")
+	}
+
+	dest, err := s.svc.GetUrl(c.Request.Context(), req)
 	if err != nil {
+		switch {
 		// Check if it's a not found error
-		if errors.Is(err, e.ErrUrlNotFound) {
+		case errors.Is(err, e.ErrUrlNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
 			return
+		case errors.Is(err, e.ErrDestinationBlocked):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Destination is blocked"})
+			return
+		case errors.Is(err, e.ErrLinkExhausted):
+			c.JSON(http.StatusGone, gin.H{"error": "Link is no longer available"})
+			return
+		case errors.Is(err, e.ErrLinkNotYetActive):
+			s.notYetAvailable(c)
+			return
+		case errors.Is(err, e.ErrConfirmationRequired):
+			s.renderPreview(c, code)
+			return
+		case errors.Is(err, e.ErrPasswordRequired):
+			s.passwordChallenge(c, code, "Password required")
+			return
+		case errors.Is(err, e.ErrWrongPassword):
+			s.passwordChallenge(c, code, "Wrong password")
+			return
+		case errors.Is(err, e.ErrTooManyAttempts):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many wrong passwords, try again later"})
+			return
 		}
 
 		log.Error().Err(err).Msg("Failed to get URL")
@@ -107,6 +397,261 @@ func (s *linkShorten) Redirect(c *gin.Context) {
 		return
 	}
 
+	s.recorder.Record(service.ClickEvent{
+		Code:      key,
+		ClickedAt: time.Now(),
+		Referrer:  c.Request.Referer(),
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+		Variant:   dest.Variant,
+	})
+
+	setRedirectHeaders(c, dest)
+
+	// A submitted password form is answered with See Other so the browser follows with a GET
+	if c.Request.Method == http.MethodPost {
+		c.Redirect(http.StatusSeeOther, dest.Url)
+		return
+	}
+
 	// Redirect to the original URL
-	c.Redirect(http.StatusFound, url)
+	c.Redirect(dest.Status, dest.Url)
+}
+
+// setRedirectHeaders sets the caching, indexing and referrer headers of a redirect.
+// Redirects that must not be cached are marked no-store, so every visit reaches the
+// service and is counted.
+func setRedirectHeaders(c *gin.Context, dest service.Destination) {
+	if dest.MaxAge > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(dest.MaxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "no-store")
+	}
+	if dest.NoIndex {
+		c.Header("X-Robots-Tag", "noindex")
+	}
+	if dest.ReferrerPolicy != "" {
+		c.Header("Referrer-Policy", dest.ReferrerPolicy)
+	}
+}
+
+// notYetAvailable answers a visit of a scheduled link before it goes live. The answer must
+// not be cached, so the link works for everyone the moment it is activated.
+func (s *linkShorten) notYetAvailable(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(s.pending.Status, gin.H{"error": s.pending.Message})
+}
+
+// RedirectCustomDomain serves short URLs at the root of verified custom domains.
+func (s *linkShorten) RedirectCustomDomain(c *gin.Context) {
+	code := strings.TrimPrefix(c.Request.URL.Path, "/")
+	if utils.GetLinkDomainFromContext(c) == "" || code == "" || strings.Contains(code, "/") {
+		c.String(http.StatusNotFound, "404 page not found")
+		return
+	}
+
+	c.Params = append(c.Params, gin.Param{Key: "code", Value: code})
+	s.Redirect(c)
+}
+
+// linkKey returns the storage key of the code on the custom domain serving the request.
+// Codes already naming a domain are refused, so links of custom domains cannot be
+// followed from other hosts.
+func linkKey(c *gin.Context, code string) (string, bool) {
+	if dto.IsLinkKey(code) {
+		return "", false
+	}
+
+	return dto.LinkKey(code, utils.GetLinkDomainFromContext(c)), true
+}
+
+// passwordChallenge answers a request for a protected link without a valid password.
+// Browsers get a password form posting back to the same URL, API clients a JSON error.
+func (s *linkShorten) passwordChallenge(c *gin.Context, code, message string) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		c.Render(http.StatusUnauthorized, render.HTML{
+			Template: passwordFormTemplate,
+			Data: gin.H{
+				"Code":    code,
+				"Message": message,
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+}
+
+// bulkItem is one parsed item of a bulk request; err is set if the item could not be parsed.
+type bulkItem struct {
+	req dto.LinkShortenRequestDto
+	err error
+}
+
+// readBulkItems reads the items of a bulk request from a JSON array, a CSV body or a CSV upload.
+func readBulkItems(c *gin.Context) ([]bulkItem, error) {
+	switch c.ContentType() {
+	case "text/csv":
+		return readBulkCsv(c.Request.Body)
+	case binding.MIMEMultipartPOSTForm:
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.New("CSV upload expected in the file field")
+		}
+		f, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return readBulkCsv(f)
+	}
+
+	// Items are validated one by one, so the array is decoded without binding
+	var reqs []dto.LinkShortenRequestDto
+	if err := json.NewDecoder(c.Request.Body).Decode(&reqs); err != nil {
+		return nil, err
+	}
+
+	items := make([]bulkItem, len(reqs))
+	for i, req := range reqs {
+		items[i].req = req
+	}
+
+	return items, nil
+}
+
+// readBulkCsv reads bulk items from CSV with a header row naming the columns.
+// Reading stops one row past the item limit; cells that cannot be parsed fail their row only.
+func readBulkCsv(r io.Reader) ([]bulkItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// Rows with the wrong number of fields fail on their own in parseBulkCsvRecord
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(bulkCsvColumns, name) {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[i] = name
+	}
+	if !slices.Contains(columns, "url") {
+		return nil, errors.New("CSV header must contain a url column")
+	}
+
+	var items []bulkItem
+	for len(items) <= dto.MaxBulkShortenItems {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, parseBulkCsvRecord(columns, record))
+	}
+
+	return items, nil
+}
+
+// parseBulkCsvRecord fills a request from a CSV row; empty cells keep their default.
+// Rows with more or fewer fields than the header fail as a whole.
+func parseBulkCsvRecord(columns, record []string) bulkItem {
+	var item bulkItem
+	if len(record) != len(columns) {
+		item.err = fmt.Errorf("row has %d fields, header has %d", len(record), len(columns))
+		return item
+	}
+
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch columns[i] {
+		case "url":
+			item.req.Url = value
+		case "exp":
+			item.req.ExpInSeconds, err = strconv.Atoi(value)
+		case "alias":
+			item.req.Alias = value
+		case "max_clicks":
+			item.req.MaxClicks, err = strconv.ParseInt(value, 10, 64)
+		case "interstitial":
+			item.req.Interstitial, err = strconv.ParseBool(value)
+		case "password":
+			item.req.Password = value
+		case "domain":
+			item.req.Domain = value
+		case "redirect_status":
+			item.req.RedirectStatus, err = strconv.Atoi(value)
+		case "no_index":
+			item.req.NoIndex, err = strconv.ParseBool(value)
+		case "referrer_policy":
+			item.req.ReferrerPolicy = value
+		case "not_before":
+			item.req.NotBefore, err = parseBulkTime(value)
+		case "not_after":
+			item.req.NotAfter, err = parseBulkTime(value)
+		case "utm_source":
+			bulkUtm(&item.req).Source = value
+		case "utm_medium":
+			bulkUtm(&item.req).Medium = value
+		case "utm_campaign":
+			bulkUtm(&item.req).Campaign = value
+		case "utm_term":
+			bulkUtm(&item.req).Term = value
+		case "utm_content":
+			bulkUtm(&item.req).Content = value
+		}
+		if err != nil && item.err == nil {
+			item.err = fmt.Errorf("invalid %s %q", columns[i], value)
+		}
+	}
+
+	return item
+}
+
+// parseBulkTime parses an RFC 3339 time of a CSV cell.
+func parseBulkTime(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// bulkUtm returns the UTM parameters of a CSV row's request, adding them on first use.
+func bulkUtm(r *dto.LinkShortenRequestDto) *dto.LinkUtmDto {
+	if r.Utm == nil {
+		r.Utm = &dto.LinkUtmDto{}
+	}
+
+	return r.Utm
+}
+
+// bulkItemError returns the message of a failed bulk item; unexpected errors are logged and hidden.
+func bulkItemError(err error) string {
+	if errors.Is(err, e.ErrAliasReserved) || errors.Is(err, e.ErrAliasTaken) || errors.Is(err, destpolicy.ErrRejected) ||
+		errors.Is(err, routing.ErrInvalidRouting) || errors.Is(err, utm.ErrInvalidTemplate) ||
+		errors.Is(err, e.ErrDomainNotFound) || errors.Is(err, e.ErrDomainNotVerified) ||
+		errors.Is(err, e.ErrInvalidSchedule) || errors.Is(err, e.ErrPasswordTooLong) {
+		return err.Error()
+	}
+
+	log.Error().Err(err).Msg("Failed to shorten URL")
+	return "Internal Server Error"
 }
@@ -5,28 +5,47 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
 	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/middleware"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
 	"github.com/vincent-tien/bookmark-management/internal/routers"
+	"github.com/vincent-tien/bookmark-management/internal/service"
 	"github.com/vincent-tien/bookmark-management/internal/service/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
 )
 
+// testShortUrlBase is the public prefix of short URLs in handler tests.
+const testShortUrlBase = "https://sho.rt/"
+
+// testNotYetAvailable answers visits of scheduled links in handler tests.
+var testNotYetAvailable = NotYetAvailable{Status: http.StatusNotFound, Message: "Link is not yet available"}
+
+// testBulkQuota is the shorten rate limit bulk requests are charged against in handler tests.
+const testBulkQuota = 5
+
 func TestLinkShorten_Create(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		name           string
-		setupRequest   func(ctx *gin.Context)
-		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten
-		expectedStatus int
-		expectedResp   string
+		name             string
+		setupRequest     func(ctx *gin.Context)
+		setupMockSvc     func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten
+		setupMockDomains func(t *testing.T, ctx *gin.Context) *mocks.Domain
+		expectedStatus   int
+		expectedResp     string
 	}{
 		{
 			name: "success case",
@@ -41,14 +60,38 @@ func TestLinkShorten_Create(t *testing.T) {
 			},
 			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
 				mockSvc := mocks.NewUrlShorten(t)
-				mockSvc.On("Shorten", ctx, dto.LinkShortenRequestDto{
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
 					ExpInSeconds: 3600,
 					Url:          "https://google.com",
 				}).Return("foobar", nil)
 				return mockSvc
 			},
 			expectedStatus: http.StatusCreated,
-			expectedResp:   `{"code":"foobar","message":"Shorten URL generated successfully!"}`,
+			expectedResp:   `{"code":"foobar","short_url":"https://sho.rt/foobar","message":"Shorten URL generated successfully!"}`,
+		},
+		{
+			name: "success case - signed-in caller owns the link",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+				ctx.Set(middleware.UserIDKey, "deb745af-1a62-4efa-99a0-f06b274bd993")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					OwnerId:      "deb745af-1a62-4efa-99a0-f06b274bd993",
+				}).Return("foobar", nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResp:   `{"code":"foobar","short_url":"https://sho.rt/foobar","message":"Shorten URL generated successfully!"}`,
 		},
 		{
 			name: "bad request - invalid JSON",
@@ -89,7 +132,7 @@ func TestLinkShorten_Create(t *testing.T) {
 			},
 			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
 				mockSvc := mocks.NewUrlShorten(t)
-				mockSvc.On("Shorten", ctx, dto.LinkShortenRequestDto{
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
 					ExpInSeconds: 3600,
 					Url:          "https://google.com",
 				}).Return("", errors.New("redis error"))
@@ -98,6 +141,169 @@ func TestLinkShorten_Create(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedResp:   "",
 		},
+		{
+			name: "bad request - destination rejected",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "http://10.0.0.1/admin",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "http://10.0.0.1/admin",
+				}).Return("", destpolicy.ErrPrivateAddress)
+				return mockSvc
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   `{"error":"destination rejected: private or local addresses are not allowed"}`,
+		},
+		{
+			name: "success case - custom domain",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "launch",
+					Domain:       "Go.Example.com",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+				ctx.Set(middleware.UserIDKey, "user-123")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "launch",
+					Domain:       "go.example.com",
+					OwnerId:      "user-123",
+				}).Return("launch", nil)
+				return mockSvc
+			},
+			setupMockDomains: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				domains := mocks.NewDomain(t)
+				domains.On("CheckUsable", ctx.Request.Context(), "user-123", "go.example.com").Return(nil)
+				return domains
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResp:   `{"code":"launch","short_url":"https://go.example.com/launch","message":"Shorten URL generated successfully!"}`,
+		},
+		{
+			name: "bad request - unverified custom domain",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Domain:       "go.example.com",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+				ctx.Set(middleware.UserIDKey, "user-123")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			setupMockDomains: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				domains := mocks.NewDomain(t)
+				domains.On("CheckUsable", ctx.Request.Context(), "user-123", "go.example.com").Return(e.ErrDomainNotVerified)
+				return domains
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   `{"error":"domain is not verified"}`,
+		},
+		{
+			name: "unauthorized - custom domain of an anonymous caller",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Domain:       "go.example.com",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   `{"error":"Custom domains require a signed-in caller"}`,
+		},
+		{
+			name: "conflict - alias already taken",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "my-launch",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "my-launch",
+				}).Return("", e.ErrAliasTaken)
+				return mockSvc
+			},
+			expectedStatus: http.StatusConflict,
+			expectedResp:   `{"error":"alias is already taken"}`,
+		},
+		{
+			name: "bad request - reserved alias",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "swagger",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("Shorten", ctx.Request.Context(), dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "swagger",
+				}).Return("", e.ErrAliasReserved)
+				return mockSvc
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   `{"error":"alias is reserved"}`,
+		},
+		{
+			name: "bad request - alias with invalid characters",
+			setupRequest: func(ctx *gin.Context) {
+				reqBody := dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://google.com",
+					Alias:        "my/launch",
+				}
+				jsonData, _ := json.Marshal(reqBody)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getEndpoint(), bytes.NewBuffer(jsonData))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -109,7 +315,11 @@ func TestLinkShorten_Create(t *testing.T) {
 			tc.setupRequest(ctx)
 
 			mockSvc := tc.setupMockSvc(t, ctx)
-			handler := NewLinkShorten(mockSvc)
+			domains := mocks.NewDomain(t)
+			if tc.setupMockDomains != nil {
+				domains = tc.setupMockDomains(t, ctx)
+			}
+			handler := NewLinkShorten(mockSvc, mocks.NewClickRecorder(t), mocks.NewLinkPreview(t), domains, nil, testShortUrlBase, testNotYetAvailable)
 			handler.Create(ctx)
 
 			assert.Equal(t, tc.expectedStatus, rec.Code)
@@ -130,16 +340,269 @@ func TestLinkShorten_Create(t *testing.T) {
 	}
 }
 
+func TestLinkShorten_CreateBulk(t *testing.T) {
+	t.Parallel()
+
+	launch := time.Date(2099, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name             string
+		setupRequest     func(ctx *gin.Context)
+		setupMockSvc     func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten
+		setupMockDomains func(t *testing.T, ctx *gin.Context) *mocks.Domain
+		expectedStatus   int
+		expectedResp     string
+	}{
+		{
+			name: "json array with per-item results",
+			setupRequest: func(ctx *gin.Context) {
+				body := `[{"url":"https://google.com"},{"url":"not a url"},{"url":"https://evil.example","exp":60},{"url":"https://go.dev","alias":"taken"}]`
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(body))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+				ctx.Set(middleware.UserIDKey, "deb745af-1a62-4efa-99a0-f06b274bd993")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("ShortenMany", ctx.Request.Context(), []dto.LinkShortenRequestDto{
+					{Url: "https://google.com", ExpInSeconds: 3600, OwnerId: "deb745af-1a62-4efa-99a0-f06b274bd993"},
+					{Url: "https://evil.example", ExpInSeconds: 60, OwnerId: "deb745af-1a62-4efa-99a0-f06b274bd993"},
+					{Url: "https://go.dev", ExpInSeconds: 3600, Alias: "taken", OwnerId: "deb745af-1a62-4efa-99a0-f06b274bd993"},
+				}).Return([]service.ShortenResult{
+					{Code: "foobar"},
+					{Err: destpolicy.ErrDomainBlocked},
+					{Err: e.ErrAliasTaken},
+				}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp: `{"items":[{"index":0,"url":"https://google.com","code":"foobar","short_url":"https://sho.rt/foobar"},` +
+				`{"index":1,"url":"not a url","error":"Key: 'LinkShortenRequestDto.Url' Error:Field validation for 'Url' failed on the 'url' tag"},` +
+				`{"index":2,"url":"https://evil.example","error":"destination rejected: domain is blocked"},` +
+				`{"index":3,"url":"https://go.dev","error":"alias is already taken"}],"created":1,"failed":3}`,
+		},
+		{
+			name: "csv body with custom domains",
+			setupRequest: func(ctx *gin.Context) {
+				body := "url,alias,domain\nhttps://google.com,launch,go.example.com\nhttps://go.dev,,links.example.org\nhttps://go.dev/doc,,GO.example.com\n"
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(body))
+				ctx.Request.Header.Set("Content-Type", "text/csv")
+				ctx.Set(middleware.UserIDKey, "deb745af-1a62-4efa-99a0-f06b274bd993")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("ShortenMany", ctx.Request.Context(), []dto.LinkShortenRequestDto{
+					{Url: "https://google.com", ExpInSeconds: 3600, Alias: "launch", Domain: "go.example.com", OwnerId: "deb745af-1a62-4efa-99a0-f06b274bd993"},
+					{Url: "https://go.dev/doc", ExpInSeconds: 3600, Domain: "go.example.com", OwnerId: "deb745af-1a62-4efa-99a0-f06b274bd993"},
+				}).Return([]service.ShortenResult{{Code: "launch"}, {Code: "foobar"}}, nil)
+				return mockSvc
+			},
+			setupMockDomains: func(t *testing.T, ctx *gin.Context) *mocks.Domain {
+				domains := mocks.NewDomain(t)
+				// Each domain is checked once
+				domains.On("CheckUsable", ctx.Request.Context(), "deb745af-1a62-4efa-99a0-f06b274bd993", "go.example.com").Return(nil).Once()
+				domains.On("CheckUsable", ctx.Request.Context(), "deb745af-1a62-4efa-99a0-f06b274bd993", "links.example.org").Return(e.ErrDomainNotVerified).Once()
+				return domains
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp: `{"items":[{"index":0,"url":"https://google.com","code":"launch","short_url":"https://go.example.com/launch"},` +
+				`{"index":1,"url":"https://go.dev","error":"domain is not verified"},` +
+				`{"index":2,"url":"https://go.dev/doc","code":"foobar","short_url":"https://go.example.com/foobar"}],"created":2,"failed":1}`,
+		},
+		{
+			name: "csv body",
+			setupRequest: func(ctx *gin.Context) {
+				body := "URL,exp,interstitial,utm_source,utm_campaign,redirect_status,no_index,not_before\n" +
+					"https://google.com,60,true,newsletter,{code},301,true,2099-01-01T09:00:00Z\nhttps://go.dev,soon,,,,,,\n"
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(body))
+				ctx.Request.Header.Set("Content-Type", "text/csv")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("ShortenMany", ctx.Request.Context(), []dto.LinkShortenRequestDto{
+					{Url: "https://google.com", ExpInSeconds: 60, Interstitial: true, Utm: &dto.LinkUtmDto{Source: "newsletter", Campaign: "{code}"},
+						RedirectStatus: http.StatusMovedPermanently, NoIndex: true, NotBefore: &launch},
+				}).Return([]service.ShortenResult{{Code: "foobar"}}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp: `{"items":[{"index":0,"url":"https://google.com","code":"foobar","short_url":"https://sho.rt/foobar"},` +
+				`{"index":1,"url":"https://go.dev","error":"invalid exp \"soon\""}],"created":1,"failed":1}`,
+		},
+		{
+			name: "csv upload",
+			setupRequest: func(ctx *gin.Context) {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				part, _ := writer.CreateFormFile("file", "links.csv")
+				_, _ = part.Write([]byte("url,max_clicks\nhttps://google.com,1\n"))
+				_ = writer.Close()
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), body)
+				ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("ShortenMany", ctx.Request.Context(), []dto.LinkShortenRequestDto{
+					{Url: "https://google.com", ExpInSeconds: 3600, MaxClicks: 1},
+				}).Return([]service.ShortenResult{{Code: "foobar"}}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `{"items":[{"index":0,"url":"https://google.com","code":"foobar","short_url":"https://sho.rt/foobar"}],"created":1,"failed":0}`,
+		},
+		{
+			name: "csv rows with wrong field counts",
+			setupRequest: func(ctx *gin.Context) {
+				body := "url,alias\nhttps://google.com,launch\nhttps://go.dev\nhttps://go.dev/doc,doc,extra\n"
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(body))
+				ctx.Request.Header.Set("Content-Type", "text/csv")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("ShortenMany", ctx.Request.Context(), []dto.LinkShortenRequestDto{
+					{Url: "https://google.com", ExpInSeconds: 3600, Alias: "launch"},
+				}).Return([]service.ShortenResult{{Code: "launch"}}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp: `{"items":[{"index":0,"url":"https://google.com","code":"launch","short_url":"https://sho.rt/launch"},` +
+				`{"index":1,"url":"","error":"row has 1 fields, header has 2"},` +
+				`{"index":2,"url":"","error":"row has 3 fields, header has 2"}],"created":1,"failed":2}`,
+		},
+		{
+			name: "too many requests - more items than the remaining quota",
+			setupRequest: func(ctx *gin.Context) {
+				body := "url\n" + strings.Repeat("https://google.com\n", testBulkQuota+2)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(body))
+				ctx.Request.Header.Set("Content-Type", "text/csv")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedResp:   `{"error":"Too many requests"}`,
+		},
+		{
+			name: "bad request - unknown csv column",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader("url,expires\nhttps://google.com,60\n"))
+				ctx.Request.Header.Set("Content-Type", "text/csv")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   `{"error":"unknown CSV column \"expires\""}`,
+		},
+		{
+			name: "bad request - upload without file",
+			setupRequest: func(ctx *gin.Context) {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				_ = writer.WriteField("url", "https://google.com")
+				_ = writer.Close()
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), body)
+				ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - invalid json",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(`{"url":"https://google.com"}`))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - no items",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(`[]`))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   `{"error":"No links to shorten"}`,
+		},
+		{
+			name: "bad request - too many items",
+			setupRequest: func(ctx *gin.Context) {
+				body := "url\n" + strings.Repeat("https://google.com\n", dto.MaxBulkShortenItems+1)
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(body))
+				ctx.Request.Header.Set("Content-Type", "text/csv")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   `{"error":"At most 500 links can be shortened at once"}`,
+		},
+		{
+			name: "internal server error - batch failed",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader(`[{"url":"https://google.com"}]`))
+				ctx.Request.Header.Set("Content-Type", "application/json")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("ShortenMany", ctx.Request.Context(), mock.Anything).Return(nil, errors.New("database down"))
+				return mockSvc
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   `{"error":"Internal Server Error"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+			tc.setupRequest(ctx)
+
+			mockSvc := tc.setupMockSvc(t, ctx)
+			domains := mocks.NewDomain(t)
+			if tc.setupMockDomains != nil {
+				domains = tc.setupMockDomains(t, ctx)
+			}
+			// The handler runs without the middleware, so the whole quota is left for the items after the first
+			quota := middleware.NewRateLimit(repository.NewRateLimiter(redisPkg.InitMockRedis(t)), "shorten", middleware.RateLimitPolicy{
+				AnonymousLimit: testBulkQuota,
+				UserLimit:      testBulkQuota,
+				Window:         time.Minute,
+			})
+			handler := NewLinkShorten(mockSvc, mocks.NewClickRecorder(t), mocks.NewLinkPreview(t), domains, quota, testShortUrlBase, testNotYetAvailable)
+			handler.CreateBulk(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedResp != "" {
+				assert.Equal(t, tc.expectedResp, strings.TrimSpace(rec.Body.String()))
+			} else {
+				assert.Contains(t, rec.Body.String(), "error")
+			}
+		})
+	}
+}
+
 func TestLinkShorten_GetUrl(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		name           string
-		setupRequest   func(ctx *gin.Context)
-		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten
-		expectedStatus int
-		expectedResp   string
-		expectedLoc    string
+		name             string
+		setupRequest     func(ctx *gin.Context)
+		setupMockSvc     func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten
+		setupMockPreview func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview
+		expectedStatus   int
+		expectedResp     string
+		expectedLoc      string
 	}{
 		{
 			name: "success case",
@@ -149,13 +612,54 @@ func TestLinkShorten_GetUrl(t *testing.T) {
 			},
 			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
 				mockSvc := mocks.NewUrlShorten(t)
-				mockSvc.On("GetUrl", ctx, "foobar").Return("https://google.com", nil)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "foobar"})).Return(service.Destination{Url: "https://google.com", Variant: routing.DefaultVariant, Status: http.StatusFound}, nil)
 				return mockSvc
 			},
 			expectedStatus: http.StatusFound,
 			expectedResp:   "",
 			expectedLoc:    "https://google.com",
 		},
+		{
+			name: "success case - custom domain",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "https://go.example.com/foobar", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "foobar"}}
+				ctx.Set(middleware.LinkDomainKey, "go.example.com")
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "foobar@go.example.com"})).Return(service.Destination{Url: "https://go.dev", Variant: routing.DefaultVariant, Status: http.StatusFound}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://go.dev",
+		},
+		{
+			name: "scheduled link not yet available",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/launch", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "launch"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "launch"})).Return(service.Destination{}, e.ErrLinkNotYetActive)
+				return mockSvc
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   `{"error":"Link is not yet available"}`,
+		},
+		{
+			name: "not found - code naming a custom domain",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/foobar@go.example.com", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "foobar@go.example.com"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   `{"error":"URL not found"}`,
+		},
 		{
 			name: "bad request - empty code",
 			setupRequest: func(ctx *gin.Context) {
@@ -177,13 +681,166 @@ func TestLinkShorten_GetUrl(t *testing.T) {
 			},
 			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
 				mockSvc := mocks.NewUrlShorten(t)
-				mockSvc.On("GetUrl", ctx, "nonexistent").Return("", e.ErrUrlNotFound)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "nonexistent"})).Return(service.Destination{}, e.ErrUrlNotFound)
 				return mockSvc
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedResp:   `{"error":"URL not found"}`,
 			expectedLoc:    "",
 		},
+		{
+			name: "gone - click limit reached",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/one-time", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "one-time"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "one-time"})).Return(service.Destination{}, e.ErrLinkExhausted)
+				return mockSvc
+			},
+			expectedStatus: http.StatusGone,
+			expectedResp:   `{"error":"Link is no longer available"}`,
+			expectedLoc:    "",
+		},
+		{
+			name: "success case - password in header",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/secret", nil)
+				ctx.Request.Header.Set("X-Link-Password", "open-sesame")
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "secret"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "secret", Password: "open-sesame"})).Return(service.Destination{Url: "https://google.com", Variant: routing.DefaultVariant, Status: http.StatusFound}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com",
+		},
+		{
+			name: "success case - password form submitted",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/links/redirect/secret", strings.NewReader("password=open-sesame"))
+				ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "secret"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "secret", Password: "open-sesame"})).Return(service.Destination{Url: "https://google.com", Variant: routing.DefaultVariant, Status: http.StatusFound}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusSeeOther,
+			expectedLoc:    "https://google.com",
+		},
+		{
+			name: "unauthorized - password required",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/secret", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "secret"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "secret"})).Return(service.Destination{}, e.ErrPasswordRequired)
+				return mockSvc
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   `{"error":"Password required"}`,
+		},
+		{
+			name: "unauthorized - wrong password",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/secret", nil)
+				ctx.Request.Header.Set("X-Link-Password", "guess")
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "secret"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "secret", Password: "guess"})).Return(service.Destination{}, e.ErrWrongPassword)
+				return mockSvc
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   `{"error":"Wrong password"}`,
+		},
+		{
+			name: "forbidden - destination blocked",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/12345678", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "12345678"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "12345678"})).Return(service.Destination{}, e.ErrDestinationBlocked)
+				return mockSvc
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedResp:   `{"error":"Destination is blocked"}`,
+		},
+		{
+			name: "too many requests - password attempts exhausted",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/secret", nil)
+				ctx.Request.Header.Set("X-Link-Password", "guess")
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "secret"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "secret", Password: "guess"})).Return(service.Destination{}, e.ErrTooManyAttempts)
+				return mockSvc
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedResp:   `{"error":"Too many wrong passwords, try again later"}`,
+		},
+		{
+			name: "success case - interstitial confirmed",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/careful?confirm=1", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "careful"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "careful", Confirmed: true})).Return(service.Destination{Url: "https://google.com", Variant: routing.DefaultVariant, Status: http.StatusFound}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com",
+		},
+		{
+			name: "preview - interstitial not confirmed",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/careful", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "careful"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				mockSvc := mocks.NewUrlShorten(t)
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "careful"})).Return(service.Destination{}, e.ErrConfirmationRequired)
+				return mockSvc
+			},
+			setupMockPreview: func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview {
+				mockPreview := mocks.NewLinkPreview(t)
+				mockPreview.On("Preview", ctx.Request.Context(), "careful").Return(dto.LinkPreviewDto{Code: "careful", Url: "https://google.com"}, nil)
+				return mockPreview
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `{"data":{"code":"careful","short_url":"","continue_url":"","url":"https://google.com","suspicious":false,"warnings":null,"password_protected":false},"message":"Success"}`,
+		},
+		{
+			name: "preview - code with plus suffix",
+			setupRequest: func(ctx *gin.Context) {
+				ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/foobar+", nil)
+				ctx.Params = gin.Params{gin.Param{Key: "code", Value: "/foobar+"}}
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
+				return mocks.NewUrlShorten(t)
+			},
+			setupMockPreview: func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview {
+				mockPreview := mocks.NewLinkPreview(t)
+				mockPreview.On("Preview", ctx.Request.Context(), "foobar").Return(dto.LinkPreviewDto{Code: "foobar", Url: "https://google.com"}, nil)
+				return mockPreview
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `{"data":{"code":"foobar","short_url":"","continue_url":"","url":"https://google.com","suspicious":false,"warnings":null,"password_protected":false},"message":"Success"}`,
+		},
 		{
 			name: "internal server error",
 			setupRequest: func(ctx *gin.Context) {
@@ -192,7 +849,7 @@ func TestLinkShorten_GetUrl(t *testing.T) {
 			},
 			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.UrlShorten {
 				mockSvc := mocks.NewUrlShorten(t)
-				mockSvc.On("GetUrl", ctx, "testcode").Return("", errors.New("redis connection error"))
+				mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "testcode"})).Return(service.Destination{}, errors.New("redis connection error"))
 				return mockSvc
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -210,8 +867,21 @@ func TestLinkShorten_GetUrl(t *testing.T) {
 			tc.setupRequest(ctx)
 
 			mockSvc := tc.setupMockSvc(t, ctx)
-			handler := NewLinkShorten(mockSvc)
+			mockRecorder := mocks.NewClickRecorder(t)
+			if tc.expectedStatus == http.StatusFound || tc.expectedStatus == http.StatusSeeOther {
+				// Every successful redirect is recorded for analytics, under the key of the link
+				mockRecorder.On("Record", mock.MatchedBy(func(ev service.ClickEvent) bool {
+					return ev.Code == dto.LinkKey(ctx.Param("code"), ctx.GetString(middleware.LinkDomainKey))
+				})).Once()
+			}
+			mockPreview := mocks.NewLinkPreview(t)
+			if tc.setupMockPreview != nil {
+				mockPreview = tc.setupMockPreview(t, ctx)
+			}
+			handler := NewLinkShorten(mockSvc, mockRecorder, mockPreview, mocks.NewDomain(t), nil, testShortUrlBase, testNotYetAvailable)
 			handler.Redirect(ctx)
+			// Flush the status like the engine does, redirects of POST requests have no body
+			ctx.Writer.WriteHeaderNow()
 
 			assert.Equal(t, tc.expectedStatus, rec.Code)
 			if tc.expectedResp != "" {
@@ -229,6 +899,226 @@ func TestLinkShorten_GetUrl(t *testing.T) {
 	}
 }
 
+// redirectRequest matches the redirect request of the link, ignoring the visitor details
+// routing rules are evaluated against.
+func redirectRequest(want dto.LinkRedirectRequestDto) any {
+	return mock.MatchedBy(func(r dto.LinkRedirectRequestDto) bool {
+		return r.Code == want.Code && r.Password == want.Password && r.Confirmed == want.Confirmed
+	})
+}
+
+func TestLinkShorten_GetUrl_Routing(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/routed?src=email", nil)
+	ctx.Request.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)")
+	ctx.Request.Header.Set("Accept-Language", "de-AT,de;q=0.9")
+	ctx.Params = gin.Params{gin.Param{Key: "code", Value: "routed"}}
+
+	mockSvc := mocks.NewUrlShorten(t)
+	mockSvc.On("GetUrl", ctx.Request.Context(), dto.LinkRedirectRequestDto{
+		Code:           "routed",
+		UserAgent:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)",
+		AcceptLanguage: "de-AT,de;q=0.9",
+		Query:          map[string][]string{"src": {"email"}},
+		IP:             "192.0.2.1",
+	}).Return(service.Destination{Url: "https://example.com/news", Variant: "newsletter", Status: http.StatusFound}, nil)
+	mockRecorder := mocks.NewClickRecorder(t)
+	// The chosen rule is recorded with the click
+	mockRecorder.On("Record", mock.MatchedBy(func(ev service.ClickEvent) bool {
+		return ev.Code == "routed" && ev.Variant == "newsletter"
+	})).Once()
+
+	handler := NewLinkShorten(mockSvc, mockRecorder, mocks.NewLinkPreview(t), mocks.NewDomain(t), nil, testShortUrlBase, testNotYetAvailable)
+	handler.Redirect(ctx)
+	ctx.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://example.com/news", rec.Header().Get("Location"))
+}
+
+func TestLinkShorten_GetUrl_RedirectOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                 string
+		dest                 service.Destination
+		expectedStatus       int
+		expectedCacheControl string
+		expectedRobotsTag    string
+		expectedReferrer     string
+	}{
+		{
+			name:                 "temporary redirect",
+			dest:                 service.Destination{Url: "https://example.com", Status: http.StatusTemporaryRedirect},
+			expectedStatus:       http.StatusTemporaryRedirect,
+			expectedCacheControl: "no-store",
+		},
+		{
+			name: "cacheable permanent redirect",
+			dest: service.Destination{Url: "https://example.com", Status: http.StatusMovedPermanently, MaxAge: 24 * time.Hour,
+				NoIndex: true, ReferrerPolicy: "no-referrer"},
+			expectedStatus:       http.StatusMovedPermanently,
+			expectedCacheControl: "public, max-age=86400",
+			expectedRobotsTag:    "noindex",
+			expectedReferrer:     "no-referrer",
+		},
+		{
+			name:                 "permanent redirect that must not be cached",
+			dest:                 service.Destination{Url: "https://example.com", Status: http.StatusPermanentRedirect},
+			expectedStatus:       http.StatusPermanentRedirect,
+			expectedCacheControl: "no-store",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/opts", nil)
+			ctx.Params = gin.Params{gin.Param{Key: "code", Value: "opts"}}
+
+			mockSvc := mocks.NewUrlShorten(t)
+			mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "opts"})).Return(tc.dest, nil)
+			mockRecorder := mocks.NewClickRecorder(t)
+			mockRecorder.On("Record", mock.Anything).Once()
+			handler := NewLinkShorten(mockSvc, mockRecorder, mocks.NewLinkPreview(t), mocks.NewDomain(t), nil, testShortUrlBase, testNotYetAvailable)
+			handler.Redirect(ctx)
+			ctx.Writer.WriteHeaderNow()
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Equal(t, "https://example.com", rec.Header().Get("Location"))
+			assert.Equal(t, tc.expectedCacheControl, rec.Header().Get("Cache-Control"))
+			assert.Equal(t, tc.expectedRobotsTag, rec.Header().Get("X-Robots-Tag"))
+			assert.Equal(t, tc.expectedReferrer, rec.Header().Get("Referrer-Policy"))
+		})
+	}
+}
+
+func TestLinkShorten_GetUrl_PasswordForm(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/redirect/secret", nil)
+	ctx.Request.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+	ctx.Params = gin.Params{gin.Param{Key: "code", Value: "secret"}}
+
+	mockSvc := mocks.NewUrlShorten(t)
+	mockSvc.On("GetUrl", ctx.Request.Context(), redirectRequest(dto.LinkRedirectRequestDto{Code: "secret"})).Return(service.Destination{}, e.ErrPasswordRequired)
+	handler := NewLinkShorten(mockSvc, mocks.NewClickRecorder(t), mocks.NewLinkPreview(t), mocks.NewDomain(t), nil, testShortUrlBase, testNotYetAvailable)
+	handler.Redirect(ctx)
+
+	// Browsers get a form posting the password back to the same URL
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), `<form method="post">`)
+	assert.Contains(t, rec.Body.String(), `name="password"`)
+}
+
+func TestLinkShorten_Preview(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		accept           string
+		setupMockPreview func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview
+		expectedStatus   int
+		expectedType     string
+		expectedResp     []string
+	}{
+		{
+			name:   "success case - json",
+			accept: "application/json",
+			setupMockPreview: func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview {
+				mockPreview := mocks.NewLinkPreview(t)
+				mockPreview.On("Preview", ctx.Request.Context(), "abc").Return(dto.LinkPreviewDto{Code: "abc", Url: "http://1.2.3.4/", Suspicious: true, Warnings: []string{"warning"}}, nil)
+				return mockPreview
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			expectedResp:   []string{`"url":"http://1.2.3.4/"`, `"suspicious":true`, `"warnings":["warning"]`},
+		},
+		{
+			name:   "success case - html page for browsers",
+			accept: "text/html,application/xhtml+xml,*/*;q=0.8",
+			setupMockPreview: func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview {
+				mockPreview := mocks.NewLinkPreview(t)
+				mockPreview.On("Preview", ctx.Request.Context(), "abc").Return(dto.LinkPreviewDto{
+					Code:        "abc",
+					ShortUrl:    "https://sho.rt/abc",
+					ContinueUrl: "https://sho.rt/abc?confirm=1",
+					Url:         "https://example.com/?q=<script>",
+					Title:       "Example Domain",
+					Suspicious:  true,
+					Warnings:    []string{"The destination does not use a secure connection (HTTPS)"},
+				}, nil)
+				return mockPreview
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/html",
+			expectedResp: []string{
+				"<h1>Example Domain</h1>",
+				"https://example.com/?q=&lt;script&gt;",
+				"<li>The destination does not use a secure connection (HTTPS)</li>",
+				`href="https://sho.rt/abc?confirm=1"`,
+			},
+		},
+		{
+			name:   "not found",
+			accept: "application/json",
+			setupMockPreview: func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview {
+				mockPreview := mocks.NewLinkPreview(t)
+				mockPreview.On("Preview", ctx.Request.Context(), "abc").Return(dto.LinkPreviewDto{}, e.ErrUrlNotFound)
+				return mockPreview
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   []string{`{"error":"URL not found"}`},
+		},
+		{
+			name:   "internal server error",
+			accept: "application/json",
+			setupMockPreview: func(t *testing.T, ctx *gin.Context) *mocks.LinkPreview {
+				mockPreview := mocks.NewLinkPreview(t)
+				mockPreview.On("Preview", ctx.Request.Context(), "abc").Return(dto.LinkPreviewDto{}, errors.New("redis connection error"))
+				return mockPreview
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   []string{"Something went wrong"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/preview/abc", nil)
+			ctx.Request.Header.Set("Accept", tc.accept)
+			ctx.Params = gin.Params{gin.Param{Key: "code", Value: "abc"}}
+
+			handler := NewLinkShorten(mocks.NewUrlShorten(t), mocks.NewClickRecorder(t), tc.setupMockPreview(t, ctx), mocks.NewDomain(t), nil, testShortUrlBase, testNotYetAvailable)
+			handler.Preview(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.expectedType != "" {
+				assert.Contains(t, rec.Header().Get("Content-Type"), tc.expectedType)
+			}
+			for _, expected := range tc.expectedResp {
+				assert.Contains(t, rec.Body.String(), expected)
+			}
+		})
+	}
+}
+
 func getEndpoint() string {
 	return fmt.Sprintf("/v1/%s", routers.Endpoints.LinkShorten)
 }
+
+func getBulkEndpoint() string {
+	return fmt.Sprintf("/v1/%s", routers.Endpoints.LinkBulk)
+}
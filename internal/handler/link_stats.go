@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/service"
+	"github.com/vincent-tien/bookmark-management/pkg/response"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+)
+
+// LinkStats defines the interface for short link analytics handlers.
+type LinkStats interface {
+	// GetStats returns click statistics of a short link to its owner.
+	GetStats(c *gin.Context)
+}
+
+type linkStats struct {
+	svc service.LinkStats
+}
+
+// NewLinkStats creates and returns a new link analytics handler instance.
+func NewLinkStats(svc service.LinkStats) LinkStats {
+	return &linkStats{
+		svc: svc,
+	}
+}
+
+// GetStats returns click statistics of a short link to its owner.
+//
+//	@Summary		Get short link statistics
+//	@Description	Get click time series and referrer, device, browser and OS breakdowns of an owned short link
+//	@Tags			Links
+//	@Produce		json
+//	@Param			code		path		string	true	"Short code"
+//	@Param			from		query		string	false	"Start of the range (RFC3339), defaults to 30 days before to"
+//	@Param			to			query		string	false	"End of the range (RFC3339), defaults to now"
+//	@Param			granularity	query		string	false	"Time series bucket size"	Enums(hour, day, month)
+//	@Param			include_bots	query		bool	false	"Count clicks of crawlers and link-preview fetchers"
+//	@Param			domain		query		string	false	"Custom domain of the link"
+//	@Success		200			{object}	dto.LinkStatsResponseDto
+//	@Failure		400			{object}	response.Response	"Invalid query parameters"
+//	@Failure		401			{object}	response.Response	"Unauthorized"
+//	@Failure		404			{object}	response.Response	"Link not found"
+//	@Failure		500			{object}	response.Response	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/v1/links/{code}/stats [get]
+func (h *linkStats) GetStats(c *gin.Context) {
+	userId, ok := utils.GetUserIDFromContext(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
+		return
+	}
+
+	var q dto.LinkStatsQueryDto
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, response.InputFieldError(err))
+		return
+	}
+	q.Code = dto.LinkKey(c.Param("code"), q.Domain)
+	q.Prepare()
+
+	res, err := h.svc.GetStats(c.Request.Context(), userId, q)
+	if err != nil {
+		switch {
+		case errors.Is(err, e.ErrInvalidStatsRange):
+			c.JSON(http.StatusBadRequest, response.InvalidRequestError)
+		case errors.Is(err, e.ErrUrlNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		default:
+			log.Error().Err(err).Msg("Failed to get link stats")
+			c.JSON(http.StatusInternalServerError, response.InternalErrorResponse)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(res))
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/middleware"
+	"github.com/vincent-tien/bookmark-management/internal/service/mocks"
+)
+
+func TestLinkStats_GetStats(t *testing.T) {
+	t.Parallel()
+
+	const userId = "deb745af-1a62-4efa-99a0-f06b274bd993"
+
+	testCases := []struct {
+		name           string
+		setupRequest   func(ctx *gin.Context)
+		setupMockSvc   func(t *testing.T, ctx *gin.Context) *mocks.LinkStats
+		expectedStatus int
+		expectedResp   string
+	}{
+		{
+			name: "success case",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "?from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z&granularity=hour", userId)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkStats {
+				mockSvc := mocks.NewLinkStats(t)
+				mockSvc.On("GetStats", ctx.Request.Context(), userId, mock.MatchedBy(func(q dto.LinkStatsQueryDto) bool {
+					return q.Code == "abc" && q.Granularity == dto.GranularityHour &&
+						q.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+				})).Return(dto.LinkStatsResponseDto{Code: "abc", TotalClicks: 7}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `"total_clicks":7`,
+		},
+		{
+			name: "defaults are applied",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "", userId)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkStats {
+				mockSvc := mocks.NewLinkStats(t)
+				mockSvc.On("GetStats", ctx.Request.Context(), userId, mock.MatchedBy(func(q dto.LinkStatsQueryDto) bool {
+					return q.Granularity == dto.GranularityDay && q.To.Sub(q.From) == dto.DefaultStatsRange
+				})).Return(dto.LinkStatsResponseDto{Code: "abc"}, nil)
+				return mockSvc
+			},
+			expectedStatus: http.StatusOK,
+			expectedResp:   `"code":"abc"`,
+		},
+		{
+			name: "unauthorized - missing user id",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "", "")
+			},
+			setupMockSvc:   newUnusedLinkStatsSvc,
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   "Invalid Token",
+		},
+		{
+			name: "bad request - unknown granularity",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "?granularity=week", userId)
+			},
+			setupMockSvc:   newUnusedLinkStatsSvc,
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "bad request - invalid range",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "", userId)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkStats {
+				mockSvc := mocks.NewLinkStats(t)
+				mockSvc.On("GetStats", ctx.Request.Context(), userId, mock.Anything).Return(dto.LinkStatsResponseDto{}, e.ErrInvalidStatsRange)
+				return mockSvc
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedResp:   "Invalid request",
+		},
+		{
+			name: "not found",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "", userId)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkStats {
+				mockSvc := mocks.NewLinkStats(t)
+				mockSvc.On("GetStats", ctx.Request.Context(), userId, mock.Anything).Return(dto.LinkStatsResponseDto{}, e.ErrUrlNotFound)
+				return mockSvc
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedResp:   "URL not found",
+		},
+		{
+			name: "internal server error",
+			setupRequest: func(ctx *gin.Context) {
+				setupLinkStatsRequest(ctx, "abc", "", userId)
+			},
+			setupMockSvc: func(t *testing.T, ctx *gin.Context) *mocks.LinkStats {
+				mockSvc := mocks.NewLinkStats(t)
+				mockSvc.On("GetStats", ctx.Request.Context(), userId, mock.Anything).Return(dto.LinkStatsResponseDto{}, errors.New("database error"))
+				return mockSvc
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   "Something went wrong",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec, ctx := createTestContext()
+			tc.setupRequest(ctx)
+
+			mockSvc := tc.setupMockSvc(t, ctx)
+			handler := NewLinkStats(mockSvc)
+			handler.GetStats(ctx)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedResp)
+		})
+	}
+}
+
+// newUnusedLinkStatsSvc returns a mock service that fails the test if it is called
+func newUnusedLinkStatsSvc(t *testing.T, ctx *gin.Context) *mocks.LinkStats {
+	return mocks.NewLinkStats(t)
+}
+
+// setupLinkStatsRequest prepares a stats request for the code, optionally authenticated as userId
+func setupLinkStatsRequest(ctx *gin.Context, code, query, userId string) {
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/links/"+code+"/stats"+query, nil)
+	ctx.Params = gin.Params{gin.Param{Key: "code", Value: code}}
+	if userId != "" {
+		ctx.Set(middleware.UserIDKey, userId)
+	}
+}
@@ -13,8 +13,13 @@ const bearerPrefix = "Bearer "
 // UserIDKey is the Gin context key under which the authenticated user's ID (from JWT "sub" claim) is stored.
 const UserIDKey = "userId"
 
+// JwtAuth defines the interface for JWT authentication middlewares.
 type JwtAuth interface {
+	// JwtAuth rejects requests without a valid bearer token.
 	JwtAuth() gin.HandlerFunc
+	// OptionalJwtAuth lets anonymous requests through and attaches the user ID
+	// when a valid bearer token is present.
+	OptionalJwtAuth() gin.HandlerFunc
 }
 
 type jwtAuth struct {
@@ -44,30 +49,55 @@ func (j *jwtAuth) JwtAuth() gin.HandlerFunc {
 			return
 		}
 
-		if !strings.HasPrefix(authHeader, bearerPrefix) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header must be Bearer token"})
-			return
-		}
+		j.authenticate(c, authHeader)
+	}
+}
 
-		tokenString := strings.TrimSpace(strings.TrimPrefix(authHeader, bearerPrefix))
-		if tokenString == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization token is required"})
+// OptionalJwtAuth returns a Gin middleware function for routes that serve both
+// anonymous and signed-in callers.
+//
+// Requests without an Authorization header pass through without a user_id in the
+// Gin context. When the header is present it is validated exactly like JwtAuth,
+// so a malformed or expired token is still rejected with 401 Unauthorized
+// instead of silently downgrading the caller to anonymous.
+func (j *jwtAuth) OptionalJwtAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
 			return
 		}
 
-		claims, err := j.jwtValidator.ValidateToken(tokenString)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			return
-		}
+		j.authenticate(c, authHeader)
+	}
+}
 
-		userID, ok := claims["sub"].(string)
-		if !ok || userID == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token content"})
-			return
-		}
+// authenticate validates the bearer token of the Authorization header and stores
+// the user_id to the Gin context, aborting the request if the token is not valid.
+func (j *jwtAuth) authenticate(c *gin.Context, authHeader string) {
+	if !strings.HasPrefix(authHeader, bearerPrefix) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header must be Bearer token"})
+		return
+	}
 
-		c.Set(UserIDKey, userID)
-		c.Next()
+	tokenString := strings.TrimSpace(strings.TrimPrefix(authHeader, bearerPrefix))
+	if tokenString == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization token is required"})
+		return
 	}
+
+	claims, err := j.jwtValidator.ValidateToken(tokenString)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok || userID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token content"})
+		return
+	}
+
+	c.Set(UserIDKey, userID)
+	c.Next()
 }
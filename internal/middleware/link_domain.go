@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// LinkDomainKey is the Gin context key under which the verified custom domain serving the request is stored.
+const LinkDomainKey = "linkDomain"
+
+// HostResolver maps request hosts to verified custom domains.
+// It is implemented by service.NewDomain.
+type HostResolver interface {
+	// ResolveHost returns the verified custom domain serving the host, or an empty
+	// string if the host is served as the shared host.
+	ResolveHost(ctx context.Context, host string) (string, error)
+}
+
+// LinkDomain defines the interface for custom domain middlewares.
+type LinkDomain interface {
+	// Resolve stores the custom domain serving the request in the Gin context.
+	Resolve() gin.HandlerFunc
+}
+
+type linkDomain struct {
+	resolver HostResolver
+}
+
+// NewLinkDomain returns a new custom domain middleware that uses the given resolver.
+func NewLinkDomain(resolver HostResolver) LinkDomain {
+	return &linkDomain{resolver: resolver}
+}
+
+// Resolve returns a Gin middleware function that resolves the Host header of the
+// request to a verified custom domain and stores it to the Gin context, so the
+// same code can name different links on different domains.
+//
+// Requests to the hosts of this service, unknown or unverified hosts are served
+// as on the shared host. If the domain store is unavailable the request is
+// aborted with 503 Service Unavailable rather than served a link of another domain.
+func (l *linkDomain) Resolve() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		domain, err := l.resolver.ResolveHost(c.Request.Context(), c.Request.Host)
+		if err != nil {
+			log.Error().Err(err).Str("host", c.Request.Host).Msg("Failed to resolve custom domain")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Service unavailable"})
+			return
+		}
+
+		if domain != "" {
+			c.Set(LinkDomainKey, domain)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// HitCounter counts requests per key in fixed windows.
+// It is implemented by repository.NewRateLimiter.
+type HitCounter interface {
+	// Hit counts one request against the key and returns the number of requests
+	// in the current window together with the time until the window resets.
+	Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	// Take counts n requests at once unless they exceed the limit, and returns the number of
+	// requests the window reaches with them together with the time until the window resets.
+	Take(ctx context.Context, key string, n, limit int64, window time.Duration) (int64, time.Duration, error)
+}
+
+// RateLimitPolicy holds the number of requests allowed per window.
+// A non-positive limit disables limiting for that kind of caller.
+type RateLimitPolicy struct {
+	AnonymousLimit int64
+	UserLimit      int64
+	Window         time.Duration
+}
+
+// RateLimit defines the interface for rate limiting middlewares.
+type RateLimit interface {
+	// RateLimit rejects callers that exceeded their limit with 429 Too Many Requests.
+	RateLimit() gin.HandlerFunc
+	// Charge counts n further requests of the caller of a request that passed RateLimit,
+	// for requests doing the work of many. If they exceed the caller's remaining quota none
+	// of them are counted, the request is aborted with 429 Too Many Requests and false is returned.
+	Charge(c *gin.Context, n int64) bool
+}
+
+type rateLimit struct {
+	limiter HitCounter
+	name    string
+	policy  RateLimitPolicy
+}
+
+// NewRateLimit returns a new rate limiting middleware. The name separates the
+// counters of different routes. It must run after JwtAuth or OptionalJwtAuth:
+// signed-in callers are counted per user ID, anonymous callers per client IP.
+func NewRateLimit(limiter HitCounter, name string, policy RateLimitPolicy) RateLimit {
+	return &rateLimit{
+		limiter: limiter,
+		name:    name,
+		policy:  policy,
+	}
+}
+
+// RateLimit returns a Gin middleware function that counts the request against
+// the caller's fixed window and aborts it once the limit is exceeded.
+//
+// The limit and remaining requests are reported in X-RateLimit-Limit and
+// X-RateLimit-Remaining; rejected requests also get a Retry-After header.
+// If the counter store is unavailable the request is let through.
+func (r *rateLimit) RateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, key := r.caller(c)
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		count, resetIn, err := r.limiter.Hit(c, key, r.policy.Window)
+		if err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to check rate limit, letting request through")
+			c.Next()
+			return
+		}
+
+		if !r.report(c, limit, count, count, resetIn) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Charge counts n further requests against the caller's fixed window.
+// If the counter store is unavailable the requests are let through.
+func (r *rateLimit) Charge(c *gin.Context, n int64) bool {
+	limit, key := r.caller(c)
+	if limit <= 0 || n <= 0 {
+		return true
+	}
+
+	count, resetIn, err := r.limiter.Take(c, key, n, limit, r.policy.Window)
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Failed to check rate limit, letting request through")
+		return true
+	}
+
+	// Refused requests are not counted, so the previous count is still remaining
+	counted := count
+	if count > limit {
+		counted = count - n
+	}
+
+	return r.report(c, limit, count, counted, resetIn)
+}
+
+// caller returns the limit and the counter key of the caller of the request.
+func (r *rateLimit) caller(c *gin.Context) (int64, string) {
+	if userID := c.GetString(UserIDKey); userID != "" {
+		return r.policy.UserLimit, r.name + ":user:" + userID
+	}
+
+	return r.policy.AnonymousLimit, r.name + ":ip:" + c.ClientIP()
+}
+
+// report sets the rate limit headers and aborts the request if count exceeds the limit.
+// counted is the number of requests actually counted in the window.
+func (r *rateLimit) report(c *gin.Context, limit, count, counted int64, resetIn time.Duration) bool {
+	c.Header("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(max(limit-counted, 0), 10))
+	if count > limit {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(resetIn.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Domain represents a user's claim on a custom domain short links can be served from.
+// Several users may claim a domain, but only one claim per domain can be verified.
+//
+// It has the following fields:
+// - Name: the lower-case host name, e.g. go.example.com (type: varchar(253); primary key with OwnerId; unique if verified).
+// - OwnerId: the id of the user who registered the domain (type: uuid; primary key with Name; indexed).
+// - Token: the value the domain must publish in its verification TXT record (type: varchar(64); non-null).
+// - CreatedAt: the timestamp when the domain is registered (type: timestamp with time zone; non-null).
+// - VerifiedAt: the timestamp when ownership was proven, nil until then (type: timestamp with time zone).
+type Domain struct {
+	Name       string     `gorm:"type:varchar(253);primaryKey;uniqueIndex:idx_domains_verified_name,where:verified_at IS NOT NULL;column:name"`
+	OwnerId    string     `gorm:"type:uuid;primaryKey;index;column:owner_id"`
+	Token      string     `gorm:"type:varchar(64);column:token"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	VerifiedAt *time.Time `gorm:"column:verified_at"`
+}
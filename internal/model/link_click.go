@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// LinkClick represents a single recorded redirect of a short link.
+//
+// It has the following fields:
+// - ID: the unique identifier of the click (type: bigserial).
+// - Code: the short code that was followed, "code@domain" on a custom domain (type: varchar(300); indexed with ClickedAt).
+// - ClickedAt: the timestamp of the redirect (type: timestamp with time zone; non-null).
+// - Referrer: the full Referer header sent by the client (type: text).
+// - ReferrerHost: the host part of the referrer, "direct" when absent (type: varchar(255)).
+// - UserAgent: the raw User-Agent header (type: text).
+// - Device: the device class parsed from the user agent (type: varchar(20)).
+// - Browser: the browser family parsed from the user agent (type: varchar(50)).
+// - OS: the operating system parsed from the user agent (type: varchar(50)).
+// - IpAddress: the client IP with its host part zeroed for privacy (type: varchar(45)).
+// - IsBot: whether the user agent is a known crawler or link-preview fetcher (type: boolean).
+// - Variant: the rule or variant that chose the destination, "default" for the link's own target (type: varchar(32)).
+type LinkClick struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement;column:id"`
+	Code         string    `gorm:"type:varchar(300);index:idx_link_clicks_code_clicked_at,priority:1;column:code"`
+	ClickedAt    time.Time `gorm:"index:idx_link_clicks_code_clicked_at,priority:2;column:clicked_at"`
+	Referrer     string    `gorm:"type:text;column:referrer"`
+	ReferrerHost string    `gorm:"type:varchar(255);column:referrer_host"`
+	UserAgent    string    `gorm:"type:text;column:user_agent"`
+	Device       string    `gorm:"type:varchar(20);column:device"`
+	Browser      string    `gorm:"type:varchar(50);column:browser"`
+	OS           string    `gorm:"type:varchar(50);column:os"`
+	IpAddress    string    `gorm:"type:varchar(45);column:ip_address"`
+	IsBot        bool      `gorm:"not null;default:false;column:is_bot"`
+	Variant      string    `gorm:"type:varchar(32);not null;default:'default';column:variant"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
+)
+
+// ShortLink represents a shortened URL in the system.
+//
+// It has the following fields:
+// - Code: the short code used in the redirect path, "code@domain" for links on a custom domain (type: varchar(300); primary key).
+// - Target: the original URL the code redirects to (type: text; non-null).
+// - OwnerId: the id of the user who created the link, nil for anonymous links (type: uuid).
+// - CreatedAt: the timestamp when the link is created (type: timestamp with time zone; non-null).
+// - ExpiresAt: the timestamp after which the link stops resolving, nil for no expiry (type: timestamp with time zone).
+// - NotBefore: the timestamp before which the link is not yet available, nil to resolve at once (type: timestamp with time zone).
+// - Disabled: whether the link has been disabled and must not resolve (type: boolean; non-null).
+// - MaxClicks: the number of redirects after which the link stops resolving, nil for no limit (type: bigint).
+// - ClicksUsed: the number of redirects a click-limited link has used up (type: bigint; non-null).
+// - Interstitial: whether every visit shows the preview page before redirecting (type: boolean; non-null).
+// - PasswordHash: the bcrypt hash of the passphrase required to follow the link, empty for none (type: varchar(255); non-null).
+// - Routing: the rules and weighted variants sending visitors to other destinations, nil for none (type: text; JSON).
+// - Utm: the UTM parameters merged into the destination on redirect, nil for none (type: text; JSON).
+// - RedirectStatus: the HTTP status of the redirect, 301, 302, 307 or 308, 0 for the default 302 (type: smallint; non-null).
+// - NoIndex: whether redirects ask search engines not to index the link (type: boolean; non-null).
+// - ReferrerPolicy: the Referrer-Policy sent with redirects, empty for none (type: varchar(32); non-null).
+type ShortLink struct {
+	Code         string           `gorm:"type:varchar(300);primaryKey;column:code"`
+	Target       string           `gorm:"type:text;column:target"`
+	OwnerId      *string          `gorm:"type:uuid;index;column:owner_id"`
+	CreatedAt    time.Time        `gorm:"column:created_at"`
+	ExpiresAt    *time.Time       `gorm:"column:expires_at"`
+	NotBefore    *time.Time       `gorm:"column:not_before"`
+	Disabled     bool             `gorm:"column:disabled;default:false"`
+	MaxClicks    *int64           `gorm:"column:max_clicks"`
+	ClicksUsed   int64            `gorm:"column:clicks_used;default:0"`
+	Interstitial bool             `gorm:"column:interstitial;default:false"`
+	PasswordHash string           `gorm:"type:varchar(255);column:password_hash;default:''"`
+	Routing      *routing.Routing `gorm:"type:text;column:routing;serializer:json"`
+	Utm          *utm.Params      `gorm:"type:text;column:utm;serializer:json"`
+
+	RedirectStatus int    `gorm:"type:smallint;column:redirect_status;default:0"`
+	NoIndex        bool   `gorm:"column:no_index;default:false"`
+	ReferrerPolicy string `gorm:"type:varchar(32);column:referrer_policy;default:''"`
+}
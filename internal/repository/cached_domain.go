@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	domainCacheKeyPrefix = "domain:"
+
+	// defaultDomainCacheTTL bounds how long a verified domain stays in the cache.
+	defaultDomainCacheTTL = time.Hour
+	// unknownDomainCacheTTL bounds how long a host without a verified domain is remembered,
+	// so requests for arbitrary hosts do not reach the database every time.
+	unknownDomainCacheTTL = time.Minute
+)
+
+type cachedDomain struct {
+	c      *redis.Client
+	source Domain
+}
+
+// NewCachedDomainRepository wraps the source repository with a Redis read-through cache
+// of the verified domains, which are looked up on every request to a custom domain.
+// Hosts without a verified domain are cached as an empty value. Verifying or deleting
+// a domain evicts its entry.
+func NewCachedDomainRepository(c *redis.Client, source Domain) Domain {
+	return &cachedDomain{
+		c:      c,
+		source: source,
+	}
+}
+
+// CreateDomain stores a newly registered domain in the source repository.
+// Unverified domains are not cached, so there is nothing to evict.
+func (r *cachedDomain) CreateDomain(ctx context.Context, d *model.Domain) error {
+	return r.source.CreateDomain(ctx, d)
+}
+
+// GetVerifiedDomain retrieves the verified domain from the cache, falling back to
+// the source repository on a miss and populating the cache with the result.
+func (r *cachedDomain) GetVerifiedDomain(ctx context.Context, name string) (*model.Domain, error) {
+	val, err := r.c.Get(ctx, domainCacheKeyPrefix+name).Result()
+	if err == nil {
+		return decodeCachedDomain(val)
+	}
+	if !errors.Is(err, redis.Nil) {
+		log.Warn().Err(err).Str("domain", name).Msg("Failed to read domain cache, falling back to source")
+	}
+
+	d, err := r.source.GetVerifiedDomain(ctx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		r.setCache(ctx, name, nil, unknownDomainCacheTTL)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(d)
+	if err != nil {
+		log.Warn().Err(err).Str("domain", name).Msg("Failed to encode domain for cache")
+		return d, nil
+	}
+	r.setCache(ctx, name, encoded, defaultDomainCacheTTL)
+
+	return d, nil
+}
+
+// GetOwnedDomain retrieves the owner's claim on the domain from the source repository.
+func (r *cachedDomain) GetOwnedDomain(ctx context.Context, name, ownerId string) (*model.Domain, error) {
+	return r.source.GetOwnedDomain(ctx, name, ownerId)
+}
+
+// ListOwnedDomains returns the owner's domains from the source repository.
+func (r *cachedDomain) ListOwnedDomains(ctx context.Context, ownerId string) ([]model.Domain, error) {
+	return r.source.ListOwnedDomains(ctx, ownerId)
+}
+
+// MarkVerified verifies the domain in the source repository and evicts its cache entry.
+func (r *cachedDomain) MarkVerified(ctx context.Context, name, ownerId string, at time.Time) error {
+	if err := r.source.MarkVerified(ctx, name, ownerId, at); err != nil {
+		return err
+	}
+	r.invalidate(ctx, name)
+
+	return nil
+}
+
+// DeleteOwnedDomain deletes the domain from the source repository and evicts its cache entry.
+func (r *cachedDomain) DeleteOwnedDomain(ctx context.Context, name, ownerId string) ([]string, error) {
+	codes, err := r.source.DeleteOwnedDomain(ctx, name, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	r.invalidate(ctx, name)
+
+	return codes, nil
+}
+
+// setCache stores the encoded domain, an empty value for hosts without a verified domain.
+// A failure is logged only, since the next read falls back to the source.
+func (r *cachedDomain) setCache(ctx context.Context, name string, val []byte, ttl time.Duration) {
+	if err := r.c.Set(ctx, domainCacheKeyPrefix+name, val, ttl).Err(); err != nil {
+		log.Warn().Err(err).Str("domain", name).Msg("Failed to write domain cache")
+	}
+}
+
+// invalidate evicts the domain. A failure is logged only; the entry then expires on its own.
+func (r *cachedDomain) invalidate(ctx context.Context, name string) {
+	if err := r.c.Del(ctx, domainCacheKeyPrefix+name).Err(); err != nil {
+		log.Error().Err(err).Str("domain", name).Msg("Failed to invalidate domain cache")
+	}
+}
+
+// decodeCachedDomain decodes a cached value; an empty value stands for a host without a verified domain.
+func decodeCachedDomain(val string) (*model.Domain, error) {
+	if val == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	d := &model.Domain{}
+	if err := json.Unmarshal([]byte(val), d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
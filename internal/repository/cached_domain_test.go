@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+	"gorm.io/gorm"
+)
+
+func TestCachedDomain_GetVerifiedDomain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		domain      string
+		expectedErr error
+		expectValue bool
+		expectedTTL time.Duration
+	}{
+		{name: "verified domain", domain: "go.example.com", expectValue: true, expectedTTL: defaultDomainCacheTTL},
+		{name: "unverified domain", domain: "links.example.org", expectedErr: gorm.ErrRecordNotFound, expectedTTL: unknownDomainCacheTTL},
+		{name: "unknown domain", domain: "unknown.example.com", expectedErr: gorm.ErrRecordNotFound, expectedTTL: unknownDomainCacheTTL},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			redisMock := redisPkg.InitMockRedis(t)
+			db := setupDomainDB(t)
+			repo := NewCachedDomainRepository(redisMock, NewDomainRepository(db))
+
+			d, err := repo.GetVerifiedDomain(ctx, tc.domain)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectValue, redisMock.Get(ctx, "domain:"+tc.domain).Val() != "")
+			assert.InDelta(t, tc.expectedTTL, redisMock.TTL(ctx, "domain:"+tc.domain).Val(), float64(time.Second))
+
+			// The cached result is served without the source
+			require.NoError(t, db.Migrator().DropTable(&model.Domain{}))
+			cached, cachedErr := repo.GetVerifiedDomain(ctx, tc.domain)
+			assert.ErrorIs(t, cachedErr, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, d.Name, cached.Name)
+				assert.Equal(t, d.OwnerId, cached.OwnerId)
+			}
+		})
+	}
+}
+
+func TestCachedDomain_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	db := setupDomainDB(t)
+	require.NoError(t, db.AutoMigrate(&model.ShortLink{}, &model.LinkClick{}))
+	repo := NewCachedDomainRepository(redisMock, NewDomainRepository(db))
+
+	// Verifying replaces the cached miss
+	_, err := repo.GetVerifiedDomain(ctx, "links.example.org")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	require.NoError(t, repo.MarkVerified(ctx, "links.example.org", fixture.ShortLinkOwnerID, time.Now().UTC()))
+	d, err := repo.GetVerifiedDomain(ctx, "links.example.org")
+	require.NoError(t, err)
+	assert.Equal(t, "links.example.org", d.Name)
+
+	// Deleting evicts the cached domain
+	_, err = repo.GetVerifiedDomain(ctx, "go.example.com")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, redisMock.Exists(ctx, "domain:go.example.com").Val())
+	_, err = repo.DeleteOwnedDomain(ctx, "go.example.com", fixture.ShortLinkOwnerID)
+	require.NoError(t, err)
+	_, err = repo.GetVerifiedDomain(ctx, "go.example.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+)
+
+// defaultLinkCacheTTL bounds how long a link without expiry stays in the cache.
+const defaultLinkCacheTTL = 24 * time.Hour
+
+type cachedUrlStorage struct {
+	c      *redis.Client
+	source UrlStorage
+	ttl    time.Duration
+}
+
+// NewCachedUrlStorage wraps the source storage with a Redis read-through cache.
+// Links are cached under their code as JSON; plain URL values written by the
+// Redis-only storage are still understood so existing links keep resolving.
+func NewCachedUrlStorage(c *redis.Client, source UrlStorage) UrlStorage {
+	return &cachedUrlStorage{
+		c:      c,
+		source: source,
+		ttl:    defaultLinkCacheTTL,
+	}
+}
+
+// Store writes the link to the source storage and then to the cache.
+// A cache write failure is logged only, since the next read falls back to the source.
+func (s *cachedUrlStorage) Store(ctx context.Context, code string, r dto.LinkShortenRequestDto) error {
+	if err := s.source.Store(ctx, code, r); err != nil {
+		return err
+	}
+
+	s.setCache(ctx, newCachedLink(code, r))
+
+	return nil
+}
+
+// StoreMany writes the links to the source storage and then to the cache in a single pipeline.
+// A cache write failure is logged only, since the next read falls back to the source.
+func (s *cachedUrlStorage) StoreMany(ctx context.Context, codes []string, rs []dto.LinkShortenRequestDto) error {
+	if err := s.source.StoreMany(ctx, codes, rs); err != nil {
+		return err
+	}
+
+	_, err := s.c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, code := range codes {
+			if val, ttl, ok := s.cacheEntry(newCachedLink(code, rs[i])); ok {
+				pipe.Set(ctx, code, val, ttl)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Int("links", len(codes)).Msg("Failed to write link cache")
+	}
+
+	return nil
+}
+
+// GetUrl retrieves the target URL for the given code, reading the cache first.
+func (s *cachedUrlStorage) GetUrl(ctx context.Context, code string) (string, error) {
+	link, err := s.GetLink(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	return link.Target, nil
+}
+
+// GetLink retrieves the link for the given code from the cache, falling back to
+// the source storage on a miss and populating the cache with the result.
+func (s *cachedUrlStorage) GetLink(ctx context.Context, code string) (*model.ShortLink, error) {
+	val, err := s.c.Get(ctx, code).Result()
+	if err == nil {
+		return decodeCachedLink(code, val)
+	}
+	if !errors.Is(err, redis.Nil) {
+		log.Warn().Err(err).Str("code", code).Msg("Failed to read link cache, falling back to source")
+	}
+
+	link, err := s.source.GetLink(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setCache(ctx, link)
+	return link, nil
+}
+
+// CheckKeyExists checks the cache first and asks the source storage on a miss.
+func (s *cachedUrlStorage) CheckKeyExists(ctx context.Context, code string) (bool, error) {
+	count, err := s.c.Exists(ctx, code).Result()
+	if err == nil && count > 0 {
+		return true, nil
+	}
+
+	return s.source.CheckKeyExists(ctx, code)
+}
+
+// CheckKeysExist checks the cache first and asks the source storage about the misses.
+func (s *cachedUrlStorage) CheckKeysExist(ctx context.Context, codes []string) (map[string]bool, error) {
+	existing, err := existingKeys(ctx, s.c, codes)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to check link cache, falling back to source")
+		existing = make(map[string]bool)
+	}
+
+	var misses []string
+	for _, code := range codes {
+		if !existing[code] {
+			misses = append(misses, code)
+		}
+	}
+	if len(misses) == 0 {
+		return existing, nil
+	}
+
+	found, err := s.source.CheckKeysExist(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	for code := range found {
+		existing[code] = true
+	}
+
+	return existing, nil
+}
+
+// setCache stores the link in the cache for at most its remaining lifetime.
+func (s *cachedUrlStorage) setCache(ctx context.Context, link *model.ShortLink) {
+	val, ttl, ok := s.cacheEntry(link)
+	if !ok {
+		return
+	}
+
+	if err := s.c.Set(ctx, link.Code, val, ttl).Err(); err != nil {
+		log.Warn().Err(err).Str("code", link.Code).Msg("Failed to write link cache")
+	}
+}
+
+// cacheEntry encodes the link and limits its cache lifetime to its remaining lifetime.
+// Returns false for expired links and links that cannot be encoded.
+func (s *cachedUrlStorage) cacheEntry(link *model.ShortLink) ([]byte, time.Duration, bool) {
+	ttl := s.ttl
+	if link.ExpiresAt != nil {
+		remaining := time.Until(*link.ExpiresAt)
+		if remaining <= 0 {
+			return nil, 0, false
+		}
+		ttl = min(ttl, remaining)
+	}
+
+	val, err := json.Marshal(link)
+	if err != nil {
+		log.Warn().Err(err).Str("code", link.Code).Msg("Failed to encode link for cache")
+		return nil, 0, false
+	}
+
+	return val, ttl, true
+}
+
+// newCachedLink builds the cached record of a newly stored link.
+func newCachedLink(code string, r dto.LinkShortenRequestDto) *model.ShortLink {
+	link := &model.ShortLink{Code: code, Target: r.Url}
+	if r.OwnerId != "" {
+		link.OwnerId = &r.OwnerId
+	}
+	link.ExpiresAt = r.ExpiresAt(time.Now())
+	link.NotBefore = utcTime(r.NotBefore)
+	if r.MaxClicks > 0 {
+		link.MaxClicks = &r.MaxClicks
+	}
+	link.Interstitial = r.Interstitial
+	link.PasswordHash = r.PasswordHash
+	link.Routing = r.Routing.ToRouting()
+	link.Utm = r.Utm.ToParams()
+	link.RedirectStatus = r.RedirectStatus
+	link.NoIndex = r.NoIndex
+	link.ReferrerPolicy = r.ReferrerPolicy
+
+	return link
+}
+
+// decodeCachedLink decodes a cached value, treating non-JSON values as a plain target URL.
+func decodeCachedLink(code, val string) (*model.ShortLink, error) {
+	if !strings.HasPrefix(val, "{") {
+		return &model.ShortLink{Code: code, Target: val}, nil
+	}
+
+	link := &model.ShortLink{}
+	if err := json.Unmarshal([]byte(val), link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
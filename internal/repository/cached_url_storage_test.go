@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// setupBrokenShortLinkDB creates a test database whose short link table is missing
+func setupBrokenShortLinkDB(t *testing.T) *gorm.DB {
+	db := setupShortLinkDB(t)
+	require.NoError(t, db.Migrator().DropTable(&model.ShortLink{}))
+	return db
+}
+
+func TestCachedUrlStorage_Store(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		setupDb    func(t *testing.T) *gorm.DB
+		expectErr  bool
+		verifyFunc func(t *testing.T, ctx context.Context, r *redis.Client, db *gorm.DB)
+	}{
+		{
+			name:    "store writes source and cache",
+			setupDb: setupShortLinkDB,
+			verifyFunc: func(t *testing.T, ctx context.Context, r *redis.Client, db *gorm.DB) {
+				val, err := r.Get(ctx, "12345678").Result()
+				require.NoError(t, err)
+
+				link := &model.ShortLink{}
+				require.NoError(t, json.Unmarshal([]byte(val), link))
+				assert.Equal(t, "https://google.com", link.Target)
+				// The owner is kept so a cached link is never handed out to another user
+				require.NotNil(t, link.OwnerId)
+				assert.Equal(t, "deb745af-1a62-4efa-99a0-f06b274bd993", *link.OwnerId)
+				assert.Greater(t, r.TTL(ctx, "12345678").Val(), time.Duration(0))
+
+				require.NoError(t, db.Where("code = ?", "12345678").First(&model.ShortLink{}).Error)
+			},
+		},
+		{
+			name:      "source error skips cache",
+			setupDb:   setupBrokenShortLinkDB,
+			expectErr: true,
+			verifyFunc: func(t *testing.T, ctx context.Context, r *redis.Client, db *gorm.DB) {
+				assert.Zero(t, r.Exists(ctx, "12345678").Val())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			redisMock := redisPkg.InitMockRedis(t)
+			db := tc.setupDb(t)
+			testRepo := NewCachedUrlStorage(redisMock, NewShortLinkStorage(db))
+
+			err := testRepo.Store(ctx, "12345678", dto.LinkShortenRequestDto{
+				ExpInSeconds: 60,
+				Url:          "https://google.com",
+				OwnerId:      "deb745af-1a62-4efa-99a0-f06b274bd993",
+			})
+
+			assert.Equal(t, tc.expectErr, err != nil)
+			tc.verifyFunc(t, ctx, redisMock, db)
+		})
+	}
+}
+
+func TestCachedUrlStorage_GetUrl(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		setupCache  func(ctx context.Context, r *redis.Client)
+		code        string
+		expectedUrl string
+		expectErr   error
+		verifyFunc  func(t *testing.T, ctx context.Context, r *redis.Client)
+	}{
+		{
+			name: "cache hit with link record",
+			setupCache: func(ctx context.Context, r *redis.Client) {
+				val, _ := json.Marshal(&model.ShortLink{Code: "12345678", Target: "https://golang.org"})
+				r.Set(ctx, "12345678", val, 0)
+			},
+			code:        "12345678",
+			expectedUrl: "https://golang.org",
+		},
+		{
+			name: "cache hit with legacy plain url",
+			setupCache: func(ctx context.Context, r *redis.Client) {
+				r.Set(ctx, "12345678", "https://example.org", 0)
+			},
+			code:        "12345678",
+			expectedUrl: "https://example.org",
+		},
+		{
+			name:        "cache miss falls back to source and fills cache",
+			setupCache:  func(ctx context.Context, r *redis.Client) {},
+			code:        "active01",
+			expectedUrl: "https://google.com",
+			verifyFunc: func(t *testing.T, ctx context.Context, r *redis.Client) {
+				ttl := r.TTL(ctx, "active01").Val()
+				assert.Greater(t, ttl, time.Duration(0))
+				assert.LessOrEqual(t, ttl, time.Hour)
+			},
+		},
+		{
+			name:       "cache miss and link expired in source",
+			setupCache: func(ctx context.Context, r *redis.Client) {},
+			code:       "expired1",
+			expectErr:  gorm.ErrRecordNotFound,
+			verifyFunc: func(t *testing.T, ctx context.Context, r *redis.Client) {
+				assert.Zero(t, r.Exists(ctx, "expired1").Val())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			redisMock := redisPkg.InitMockRedis(t)
+			tc.setupCache(ctx, redisMock)
+			testRepo := NewCachedUrlStorage(redisMock, NewShortLinkStorage(setupShortLinkDB(t)))
+
+			url, err := testRepo.GetUrl(ctx, tc.code)
+
+			assert.Equal(t, tc.expectedUrl, url)
+			assert.ErrorIs(t, err, tc.expectErr)
+			if tc.verifyFunc != nil {
+				tc.verifyFunc(t, ctx, redisMock)
+			}
+		})
+	}
+}
+
+func TestCachedUrlStorage_CheckKeyExists(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupCache     func(ctx context.Context, r *redis.Client)
+		setupDb        func(t *testing.T) *gorm.DB
+		code           string
+		expectedExists bool
+		expectErr      bool
+	}{
+		{
+			name: "exists in cache",
+			setupCache: func(ctx context.Context, r *redis.Client) {
+				r.Set(ctx, "12345678", "https://google.com", 0)
+			},
+			setupDb:        setupBrokenShortLinkDB,
+			code:           "12345678",
+			expectedExists: true,
+		},
+		{
+			name:           "cache miss asks source",
+			setupCache:     func(ctx context.Context, r *redis.Client) {},
+			setupDb:        setupShortLinkDB,
+			code:           "expired1",
+			expectedExists: true,
+		},
+		{
+			name:       "source error",
+			setupCache: func(ctx context.Context, r *redis.Client) {},
+			setupDb:    setupBrokenShortLinkDB,
+			code:       "12345678",
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			redisMock := redisPkg.InitMockRedis(t)
+			tc.setupCache(ctx, redisMock)
+			testRepo := NewCachedUrlStorage(redisMock, NewShortLinkStorage(tc.setupDb(t)))
+
+			exists, err := testRepo.CheckKeyExists(ctx, tc.code)
+
+			assert.Equal(t, tc.expectedExists, exists)
+			assert.Equal(t, tc.expectErr, err != nil)
+		})
+	}
+}
+
+func TestCachedUrlStorage_StoreMany(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	db := setupShortLinkDB(t)
+	testRepo := NewCachedUrlStorage(redisMock, NewShortLinkStorage(db))
+
+	err := testRepo.StoreMany(ctx, []string{"bulk0001", "bulk0002"}, []dto.LinkShortenRequestDto{
+		{Url: "https://golang.org", ExpInSeconds: 60},
+		{Url: "https://go.dev", ExpInSeconds: -1},
+	})
+
+	require.NoError(t, err)
+	for code, target := range map[string]string{"bulk0001": "https://golang.org", "bulk0002": "https://go.dev"} {
+		link := &model.ShortLink{}
+		require.NoError(t, json.Unmarshal([]byte(redisMock.Get(ctx, code).Val()), link))
+		assert.Equal(t, target, link.Target)
+		assert.Greater(t, redisMock.TTL(ctx, code).Val(), time.Duration(0))
+		require.NoError(t, db.Where("code = ?", code).First(&model.ShortLink{}).Error)
+	}
+
+	// Source errors skip the cache
+	brokenRepo := NewCachedUrlStorage(redisMock, NewShortLinkStorage(setupBrokenShortLinkDB(t)))
+	err = brokenRepo.StoreMany(ctx, []string{"bulk0003"}, []dto.LinkShortenRequestDto{{Url: "https://golang.org"}})
+	assert.Error(t, err)
+	assert.Zero(t, redisMock.Exists(ctx, "bulk0003").Val())
+}
+
+func TestCachedUrlStorage_CheckKeysExist(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		setupDb          func(t *testing.T) *gorm.DB
+		codes            []string
+		expectedExisting map[string]bool
+		expectErr        bool
+	}{
+		{
+			name:             "cache hits skip the source",
+			setupDb:          setupBrokenShortLinkDB,
+			codes:            []string{"cached01"},
+			expectedExisting: map[string]bool{"cached01": true},
+		},
+		{
+			name:             "cache misses ask source",
+			setupDb:          setupShortLinkDB,
+			codes:            []string{"cached01", "expired1", "ACTIVE01", "unknown1"},
+			expectedExisting: map[string]bool{"cached01": true, "expired1": true, "ACTIVE01": true},
+		},
+		{
+			name:      "source error",
+			setupDb:   setupBrokenShortLinkDB,
+			codes:     []string{"cached01", "unknown1"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			redisMock := redisPkg.InitMockRedis(t)
+			redisMock.Set(ctx, "cached01", "https://google.com", 0)
+			testRepo := NewCachedUrlStorage(redisMock, NewShortLinkStorage(tc.setupDb(t)))
+
+			existing, err := testRepo.CheckKeysExist(ctx, tc.codes)
+
+			assert.Equal(t, tc.expectErr, err != nil)
+			if !tc.expectErr {
+				assert.Equal(t, tc.expectedExisting, existing)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	clickLimitKeyPrefix = "clicks_left:"
+
+	// Results of consumeClickScript
+	clickTaken          = 1
+	clickCounterMissing = -1
+)
+
+// consumeClickScript takes one redirect from the counter unless it is missing
+// or exhausted. Running it as a script makes the check and the decrement one
+// atomic step, so concurrent redirects can never exceed the limit.
+// It returns 1 when a redirect was taken, 0 when none are left and -1 when
+// the counter does not exist.
+var consumeClickScript = redis.NewScript(`
+local left = tonumber(redis.call('GET', KEYS[1]))
+if not left then
+	return -1
+end
+if left <= 0 then
+	return 0
+end
+redis.call('DECR', KEYS[1])
+return 1
+`)
+
+//go:generate mockery --name=ClickLimit --filename=click_limit.go
+
+// ClickLimit defines the interface for the redirect budgets of click-limited links.
+type ClickLimit interface {
+	// Reset sets the number of redirects left for the code.
+	// The counter lives until expiresAt, or forever when it is nil.
+	Reset(ctx context.Context, code string, clicks int64, expiresAt *time.Time) error
+	// Consume atomically takes one redirect from the code's counter.
+	// A missing counter is rebuilt from the link's limit and the redirects it used so far.
+	// Returns false when no redirects are left or the code has no click-limited link.
+	Consume(ctx context.Context, code string) (bool, error)
+	// SetExpiry moves the expiry of an existing counter along with its link.
+	SetExpiry(ctx context.Context, code string, expiresAt *time.Time) error
+	// Delete removes the counter of a deleted link, if any.
+	Delete(ctx context.Context, code string) error
+}
+
+type clickLimit struct {
+	c  *redis.Client
+	db *gorm.DB
+}
+
+// NewClickLimit creates a ClickLimit that keeps the counters in Redis. Every redirect taken
+// is also counted in the short_links table, so a counter lost to eviction or a Redis restart
+// is rebuilt instead of exhausting the link.
+func NewClickLimit(c *redis.Client, db *gorm.DB) ClickLimit {
+	return &clickLimit{c: c, db: db}
+}
+
+// Reset sets the number of redirects left for the code.
+func (l *clickLimit) Reset(ctx context.Context, code string, clicks int64, expiresAt *time.Time) error {
+	var ttl time.Duration
+	if expiresAt != nil {
+		ttl = time.Until(*expiresAt)
+	}
+
+	return l.c.Set(ctx, clickLimitKeyPrefix+code, clicks, ttl).Err()
+}
+
+// Consume atomically takes one redirect from the code's counter and records it in the database.
+// A failure to record it is logged only; the counter in Redis already took the redirect.
+func (l *clickLimit) Consume(ctx context.Context, code string) (bool, error) {
+	taken, err := consumeClickScript.Run(ctx, l.c, []string{clickLimitKeyPrefix + code}).Int()
+	if err != nil {
+		return false, err
+	}
+	if taken == clickCounterMissing {
+		if err := l.rebuild(ctx, code); err != nil {
+			return false, err
+		}
+		if taken, err = consumeClickScript.Run(ctx, l.c, []string{clickLimitKeyPrefix + code}).Int(); err != nil {
+			return false, err
+		}
+	}
+	if taken != clickTaken {
+		return false, nil
+	}
+
+	err = l.db.WithContext(ctx).Model(&model.ShortLink{}).
+		Where("code = ?", code).
+		UpdateColumn("clicks_used", gorm.Expr("clicks_used + 1")).Error
+	if err != nil {
+		log.Warn().Err(err).Str("code", code).Msg("Failed to record used click")
+	}
+
+	return true, nil
+}
+
+// rebuild restores a missing counter from the link's limit and the redirects it used.
+// Codes without an active click-limited link get no counter.
+func (l *clickLimit) rebuild(ctx context.Context, code string) error {
+	link := &model.ShortLink{}
+	err := l.db.WithContext(ctx).
+		Select("max_clicks", "clicks_used", "expires_at").
+		Where("code = ?", code).
+		First(link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if link.MaxClicks == nil {
+		return nil
+	}
+
+	var ttl time.Duration
+	if link.ExpiresAt != nil {
+		if ttl = time.Until(*link.ExpiresAt); ttl <= 0 {
+			return nil
+		}
+	}
+
+	// Another instance may have rebuilt the counter and taken redirects from it meanwhile
+	return l.c.SetNX(ctx, clickLimitKeyPrefix+code, max(*link.MaxClicks-link.ClicksUsed, 0), ttl).Err()
+}
+
+// SetExpiry moves the expiry of an existing counter along with its link.
+func (l *clickLimit) SetExpiry(ctx context.Context, code string, expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return l.c.Persist(ctx, clickLimitKeyPrefix+code).Err()
+	}
+
+	return l.c.ExpireAt(ctx, clickLimitKeyPrefix+code, *expiresAt).Err()
+}
+
+// Delete removes the counter of a deleted link, if any.
+func (l *clickLimit) Delete(ctx context.Context, code string) error {
+	return l.c.Del(ctx, clickLimitKeyPrefix+code).Err()
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+	"github.com/vincent-tien/bookmark-management/pkg/sqldb"
+	"gorm.io/gorm"
+)
+
+// setupClickLimitDB creates a test database with the given links
+func setupClickLimitDB(t *testing.T, links ...*model.ShortLink) *gorm.DB {
+	db := sqldb.InitMockDb(t)
+	require.NoError(t, db.AutoMigrate(&model.ShortLink{}))
+	for _, link := range links {
+		require.NoError(t, db.Create(link).Error)
+	}
+
+	return db
+}
+
+func TestClickLimit_Consume(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		clicks        int64
+		reset         bool
+		expectedTaken []bool
+	}{
+		{
+			name:          "one-time link",
+			clicks:        1,
+			reset:         true,
+			expectedTaken: []bool{true, false, false},
+		},
+		{
+			name:          "several clicks",
+			clicks:        2,
+			reset:         true,
+			expectedTaken: []bool{true, true, false},
+		},
+		{
+			name:          "missing counter",
+			expectedTaken: []bool{false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			testRepo := NewClickLimit(redisPkg.InitMockRedis(t), setupClickLimitDB(t))
+			if tc.reset {
+				require.NoError(t, testRepo.Reset(ctx, "abc", tc.clicks, nil))
+			}
+
+			for _, expected := range tc.expectedTaken {
+				taken, err := testRepo.Consume(ctx, "abc")
+
+				require.NoError(t, err)
+				assert.Equal(t, expected, taken)
+			}
+		})
+	}
+}
+
+func TestClickLimit_Consume_RebuildsCounter(t *testing.T) {
+	t.Parallel()
+
+	maxClicks := int64(3)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name          string
+		link          *model.ShortLink
+		expectedTaken []bool
+		expectedUsed  int64
+	}{
+		{
+			name:          "counter lost after one of three redirects",
+			link:          &model.ShortLink{Code: "abc", Target: "https://example.com", MaxClicks: &maxClicks, ClicksUsed: 1},
+			expectedTaken: []bool{true, true, false},
+			expectedUsed:  3,
+		},
+		{
+			name:          "exhausted link stays exhausted",
+			link:          &model.ShortLink{Code: "abc", Target: "https://example.com", MaxClicks: &maxClicks, ClicksUsed: 3},
+			expectedTaken: []bool{false},
+			expectedUsed:  3,
+		},
+		{
+			name:          "expired link",
+			link:          &model.ShortLink{Code: "abc", Target: "https://example.com", MaxClicks: &maxClicks, ExpiresAt: &past},
+			expectedTaken: []bool{false},
+		},
+		{
+			name:          "link without limit",
+			link:          &model.ShortLink{Code: "abc", Target: "https://example.com"},
+			expectedTaken: []bool{false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			db := setupClickLimitDB(t, tc.link)
+			testRepo := NewClickLimit(redisPkg.InitMockRedis(t), db)
+
+			for _, expected := range tc.expectedTaken {
+				taken, err := testRepo.Consume(ctx, "abc")
+
+				require.NoError(t, err)
+				assert.Equal(t, expected, taken)
+			}
+
+			link := &model.ShortLink{}
+			require.NoError(t, db.Where("code = ?", "abc").First(link).Error)
+			assert.Equal(t, tc.expectedUsed, link.ClicksUsed)
+		})
+	}
+}
+
+func TestClickLimit_Consume_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	testRepo := NewClickLimit(redisPkg.InitMockRedis(t), setupClickLimitDB(t))
+	require.NoError(t, testRepo.Reset(ctx, "abc", 10, nil))
+
+	var taken atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := testRepo.Consume(ctx, "abc")
+			assert.NoError(t, err)
+			if ok {
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(10), taken.Load())
+}
+
+func TestClickLimit_Expiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewClickLimit(redisMock, setupClickLimitDB(t))
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, testRepo.Reset(ctx, "abc", 1, &expiresAt))
+	ttl := redisMock.TTL(ctx, "clicks_left:abc").Val()
+	assert.Greater(t, ttl, 59*time.Minute)
+
+	extended := time.Now().Add(48 * time.Hour)
+	require.NoError(t, testRepo.SetExpiry(ctx, "abc", &extended))
+	assert.Greater(t, redisMock.TTL(ctx, "clicks_left:abc").Val(), 47*time.Hour)
+
+	require.NoError(t, testRepo.SetExpiry(ctx, "abc", nil))
+	assert.Equal(t, time.Duration(-1), redisMock.TTL(ctx, "clicks_left:abc").Val())
+
+	require.NoError(t, testRepo.Delete(ctx, "abc"))
+	assert.Zero(t, redisMock.Exists(ctx, "clicks_left:abc").Val())
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	codeReservationKeyPrefix = "code_reserved:"
+	codeSequenceKey          = "sequence:code"
+)
+
+//go:generate mockery --name=CodeReservation --filename=code_reservation.go
+
+// CodeReservation defines the interface for claiming short codes across instances.
+// A code is reserved before its link is stored, so two instances can never hand
+// out the same code even while neither link is written yet.
+type CodeReservation interface {
+	// Reserve atomically claims the code for ttl.
+	// Returns false if the code is already reserved.
+	Reserve(ctx context.Context, code string, ttl time.Duration) (bool, error)
+	// ReserveMany claims all codes for ttl in one round trip.
+	// The i-th result is false if the i-th code is already reserved, including earlier in the same call.
+	ReserveMany(ctx context.Context, codes []string, ttl time.Duration) ([]bool, error)
+	// Release drops the reservation of the code, if any.
+	Release(ctx context.Context, code string) error
+	// NextSequence returns the next value of the counter shared by all instances, starting at 1.
+	NextSequence(ctx context.Context) (int64, error)
+	// NextSequences claims n consecutive counter values and returns the first of them.
+	NextSequences(ctx context.Context, n int64) (int64, error)
+}
+
+type codeReservation struct {
+	c *redis.Client
+}
+
+// NewCodeReservation creates a CodeReservation that keeps reservations and the sequence in Redis.
+func NewCodeReservation(c *redis.Client) CodeReservation {
+	return &codeReservation{c: c}
+}
+
+// Reserve atomically claims the code for ttl using SET NX.
+func (r *codeReservation) Reserve(ctx context.Context, code string, ttl time.Duration) (bool, error) {
+	return r.c.SetNX(ctx, codeReservationKeyPrefix+code, 1, ttl).Result()
+}
+
+// Release deletes the reservation of the code.
+func (r *codeReservation) Release(ctx context.Context, code string) error {
+	return r.c.Del(ctx, codeReservationKeyPrefix+code).Err()
+}
+
+// NextSequence increments and returns the shared code counter.
+func (r *codeReservation) NextSequence(ctx context.Context) (int64, error) {
+	return r.c.Incr(ctx, codeSequenceKey).Result()
+}
+
+// ReserveMany claims the codes with SET NX in a single pipeline.
+func (r *codeReservation) ReserveMany(ctx context.Context, codes []string, ttl time.Duration) ([]bool, error) {
+	cmds := make([]*redis.BoolCmd, len(codes))
+	_, err := r.c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, code := range codes {
+			cmds[i] = pipe.SetNX(ctx, codeReservationKeyPrefix+code, 1, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := make([]bool, len(codes))
+	for i, cmd := range cmds {
+		reserved[i] = cmd.Val()
+	}
+
+	return reserved, nil
+}
+
+// NextSequences increments the shared code counter by n and returns the first claimed value.
+func (r *codeReservation) NextSequences(ctx context.Context, n int64) (int64, error) {
+	last, err := r.c.IncrBy(ctx, codeSequenceKey, n).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return last - n + 1, nil
+}
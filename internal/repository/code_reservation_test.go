@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+)
+
+func TestCodeReservation_Reserve(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewCodeReservation(redisMock)
+
+	ok, err := testRepo.Reserve(ctx, "AbC123", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = testRepo.Reserve(ctx, "AbC123", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ttl, err := redisMock.TTL(ctx, "code_reserved:AbC123").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+
+	ok, err = testRepo.Reserve(ctx, "xyz789", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestCodeReservation_Release(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewCodeReservation(redisMock)
+
+	_, err := testRepo.Reserve(ctx, "abc123", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, testRepo.Release(ctx, "abc123"))
+
+	ok, err := testRepo.Reserve(ctx, "abc123", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Releasing a code without reservation is not an error
+	assert.NoError(t, testRepo.Release(ctx, "xyz789"))
+}
+
+func TestCodeReservation_NextSequence(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewCodeReservation(redisMock)
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := testRepo.NextSequence(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, i, n)
+	}
+}
+
+func TestCodeReservation_ReserveMany(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewCodeReservation(redisMock)
+
+	ok, err := testRepo.Reserve(ctx, "taken1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	reserved, err := testRepo.ReserveMany(ctx, []string{"free01", "taken1", "free02", "free01"}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true, false}, reserved)
+
+	ttl, err := redisMock.TTL(ctx, "code_reserved:free02").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+}
+
+func TestCodeReservation_NextSequences(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewCodeReservation(redisMock)
+
+	first, err := testRepo.NextSequences(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first)
+
+	first, err = testRepo.NextSequences(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), first)
+
+	n, err := testRepo.NextSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+}
+
+func TestCodeReservation_Error(t *testing.T) {
+	t.Parallel()
+
+	redisMock := redisPkg.InitMockRedis(t)
+	require.NoError(t, redisMock.Close())
+	testRepo := NewCodeReservation(redisMock)
+
+	_, err := testRepo.Reserve(t.Context(), "abc123", time.Minute)
+	assert.Error(t, err)
+
+	_, err = testRepo.NextSequence(t.Context())
+	assert.Error(t, err)
+
+	_, err = testRepo.ReserveMany(t.Context(), []string{"abc123"}, time.Minute)
+	assert.Error(t, err)
+
+	_, err = testRepo.NextSequences(t.Context(), 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=Domain --filename=domain.go
+
+// Domain defines the interface for custom domain repository.
+// It provides methods to register, verify and remove the custom domains of users.
+// Several users may claim a domain until one of them verifies it.
+type Domain interface {
+	// CreateDomain stores a newly registered domain.
+	CreateDomain(ctx context.Context, d *model.Domain) error
+	// GetVerifiedDomain retrieves the verified domain with the given name, whoever owns it.
+	// Returns gorm.ErrRecordNotFound if no one verified the domain.
+	GetVerifiedDomain(ctx context.Context, name string) (*model.Domain, error)
+	// GetOwnedDomain retrieves the owner's claim on the domain with the given name.
+	// Returns gorm.ErrRecordNotFound if the owner did not register the domain.
+	GetOwnedDomain(ctx context.Context, name, ownerId string) (*model.Domain, error)
+	// ListOwnedDomains returns the owner's domains in registration order.
+	ListOwnedDomains(ctx context.Context, ownerId string) ([]model.Domain, error)
+	// MarkVerified records that ownership of an owned domain was proven at the given time
+	// and drops the claims of other users on the domain.
+	// Returns gorm.ErrRecordNotFound if the domain does not belong to the owner.
+	MarkVerified(ctx context.Context, name, ownerId string, at time.Time) error
+	// DeleteOwnedDomain deletes an owned domain together with the owner's links on it and their
+	// clicks, and returns the storage keys of the deleted links.
+	// Returns gorm.ErrRecordNotFound if the domain does not exist or belongs to someone else.
+	DeleteOwnedDomain(ctx context.Context, name, ownerId string) ([]string, error)
+}
+
+type domain struct {
+	db *gorm.DB
+}
+
+// NewDomainRepository creates a new Domain repository backed by the domains table.
+func NewDomainRepository(db *gorm.DB) Domain {
+	return &domain{db: db}
+}
+
+// CreateDomain stores a newly registered domain.
+func (r *domain) CreateDomain(ctx context.Context, d *model.Domain) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+// GetVerifiedDomain retrieves the verified domain with the given name.
+func (r *domain) GetVerifiedDomain(ctx context.Context, name string) (*model.Domain, error) {
+	d := &model.Domain{}
+	if err := r.db.WithContext(ctx).Where("name = ? AND verified_at IS NOT NULL", name).First(d).Error; err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// GetOwnedDomain retrieves the owner's claim on the domain with the given name.
+func (r *domain) GetOwnedDomain(ctx context.Context, name, ownerId string) (*model.Domain, error) {
+	d := &model.Domain{}
+	if err := r.db.WithContext(ctx).Where("name = ? AND owner_id = ?", name, ownerId).First(d).Error; err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// ListOwnedDomains returns the owner's domains in registration order.
+func (r *domain) ListOwnedDomains(ctx context.Context, ownerId string) ([]model.Domain, error) {
+	domains := make([]model.Domain, 0)
+	err := r.db.WithContext(ctx).
+		Where("owner_id = ?", ownerId).
+		Order("created_at ASC, name ASC").
+		Find(&domains).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+// MarkVerified records that ownership of an owned domain was proven and drops the other claims.
+// The unique index on verified names rejects a second verified claim.
+func (r *domain) MarkVerified(ctx context.Context, name, ownerId string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Domain{}).
+			Where("name = ? AND owner_id = ?", name, ownerId).
+			Update("verified_at", at)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("name = ? AND owner_id <> ?", name, ownerId).Delete(&model.Domain{}).Error
+	})
+}
+
+// DeleteOwnedDomain deletes an owned domain and its links in one transaction.
+func (r *domain) DeleteOwnedDomain(ctx context.Context, name, ownerId string) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("name = ? AND owner_id = ?", name, ownerId).Delete(&model.Domain{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Links on the domain are stored under "code@domain"
+		err := tx.Model(&model.ShortLink{}).
+			Where(`owner_id = ? AND code LIKE ? ESCAPE '\'`, ownerId, "%@"+escapeLike(name)).
+			Pluck("code", &codes).Error
+		if err != nil || len(codes) == 0 {
+			return err
+		}
+		if err := tx.Where("code IN ?", codes).Delete(&model.LinkClick{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("code IN ?", codes).Delete(&model.ShortLink{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
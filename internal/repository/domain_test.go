@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	"gorm.io/gorm"
+)
+
+// otherDomainOwnerID owns none of the fixture domains
+const otherDomainOwnerID = "00000000-0000-0000-0000-000000000000"
+
+// setupDomainDB creates a test database with domain fixture
+func setupDomainDB(t *testing.T) *gorm.DB {
+	return fixture.NewFixture(t, &fixture.DomainFixture{})
+}
+
+func TestDomain_CreateDomain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		domain    *model.Domain
+		expectErr bool
+	}{
+		{
+			name:   "new domain",
+			domain: &model.Domain{Name: "s.example.net", OwnerId: fixture.ShortLinkOwnerID, Token: "token"},
+		},
+		{
+			name:   "claim on a domain of another owner",
+			domain: &model.Domain{Name: "links.example.org", OwnerId: otherDomainOwnerID, Token: "token"},
+		},
+		{
+			name:      "registered domain",
+			domain:    &model.Domain{Name: "go.example.com", OwnerId: fixture.ShortLinkOwnerID, Token: "token"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewDomainRepository(setupDomainDB(t))
+
+			err := repo.CreateDomain(t.Context(), tc.domain)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			stored, err := repo.GetOwnedDomain(t.Context(), tc.domain.Name, tc.domain.OwnerId)
+			require.NoError(t, err)
+			assert.Equal(t, "token", stored.Token)
+			assert.Nil(t, stored.VerifiedAt)
+		})
+	}
+}
+
+func TestDomain_GetVerifiedDomain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		domain      string
+		expectedErr error
+	}{
+		{name: "verified domain", domain: "go.example.com"},
+		{name: "unverified domain", domain: "links.example.org", expectedErr: gorm.ErrRecordNotFound},
+		{name: "unknown domain", domain: "unknown.example.com", expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewDomainRepository(setupDomainDB(t))
+
+			d, err := repo.GetVerifiedDomain(t.Context(), tc.domain)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.domain, d.Name)
+			assert.NotNil(t, d.VerifiedAt)
+		})
+	}
+}
+
+func TestDomain_GetOwnedDomain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		domain         string
+		ownerId        string
+		expectedErr    error
+		expectVerified bool
+	}{
+		{name: "verified domain", domain: "go.example.com", ownerId: fixture.ShortLinkOwnerID, expectVerified: true},
+		{name: "unverified domain", domain: "links.example.org", ownerId: fixture.ShortLinkOwnerID},
+		{name: "domain of another owner", domain: "go.example.com", ownerId: otherDomainOwnerID, expectedErr: gorm.ErrRecordNotFound},
+		{name: "unknown domain", domain: "unknown.example.com", ownerId: fixture.ShortLinkOwnerID, expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewDomainRepository(setupDomainDB(t))
+
+			d, err := repo.GetOwnedDomain(t.Context(), tc.domain, tc.ownerId)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.domain, d.Name)
+			assert.Equal(t, tc.expectVerified, d.VerifiedAt != nil)
+		})
+	}
+}
+
+func TestDomain_ListOwnedDomains(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		ownerId       string
+		expectedNames []string
+	}{
+		{name: "owner with domains", ownerId: fixture.ShortLinkOwnerID, expectedNames: []string{"go.example.com", "links.example.org"}},
+		{name: "owner without domains", ownerId: otherDomainOwnerID, expectedNames: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewDomainRepository(setupDomainDB(t))
+
+			domains, err := repo.ListOwnedDomains(t.Context(), tc.ownerId)
+
+			require.NoError(t, err)
+			names := make([]string, 0, len(domains))
+			for _, d := range domains {
+				names = append(names, d.Name)
+			}
+			assert.Equal(t, tc.expectedNames, names)
+		})
+	}
+}
+
+func TestDomain_MarkVerified(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		domain      string
+		ownerId     string
+		expectedErr error
+	}{
+		{name: "owned domain", domain: "links.example.org", ownerId: fixture.ShortLinkOwnerID},
+		{name: "domain of another owner", domain: "links.example.org", ownerId: otherDomainOwnerID, expectedErr: gorm.ErrRecordNotFound},
+		{name: "unknown domain", domain: "unknown.example.com", ownerId: fixture.ShortLinkOwnerID, expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewDomainRepository(setupDomainDB(t))
+			at := time.Now().UTC().Truncate(time.Second)
+
+			err := repo.MarkVerified(t.Context(), tc.domain, tc.ownerId, at)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			d, err := repo.GetVerifiedDomain(t.Context(), tc.domain)
+			require.NoError(t, err)
+			require.NotNil(t, d.VerifiedAt)
+			assert.WithinDuration(t, at, *d.VerifiedAt, time.Second)
+		})
+	}
+}
+
+func TestDomain_MarkVerifiedTakesOver(t *testing.T) {
+	t.Parallel()
+
+	repo := NewDomainRepository(setupDomainDB(t))
+	require.NoError(t, repo.CreateDomain(t.Context(), &model.Domain{Name: "links.example.org", OwnerId: otherDomainOwnerID, Token: "token"}))
+	require.NoError(t, repo.CreateDomain(t.Context(), &model.Domain{Name: "go.example.com", OwnerId: otherDomainOwnerID, Token: "token"}))
+
+	// The first owner to verify drops the other claims
+	require.NoError(t, repo.MarkVerified(t.Context(), "links.example.org", otherDomainOwnerID, time.Now().UTC()))
+	_, err := repo.GetOwnedDomain(t.Context(), "links.example.org", fixture.ShortLinkOwnerID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	d, err := repo.GetVerifiedDomain(t.Context(), "links.example.org")
+	require.NoError(t, err)
+	assert.Equal(t, otherDomainOwnerID, d.OwnerId)
+
+	// A domain cannot be verified twice
+	assert.Error(t, repo.MarkVerified(t.Context(), "go.example.com", otherDomainOwnerID, time.Now().UTC()))
+	d, err = repo.GetVerifiedDomain(t.Context(), "go.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, fixture.ShortLinkOwnerID, d.OwnerId)
+}
+
+func TestDomain_DeleteOwnedDomain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		domain      string
+		ownerId     string
+		expectedErr error
+	}{
+		{name: "owned domain", domain: "go.example.com", ownerId: fixture.ShortLinkOwnerID},
+		{name: "domain of another owner", domain: "go.example.com", ownerId: otherDomainOwnerID, expectedErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := setupDomainDB(t)
+			require.NoError(t, db.AutoMigrate(&model.ShortLink{}, &model.LinkClick{}))
+			ownerId := fixture.ShortLinkOwnerID
+			links := []*model.ShortLink{
+				{Code: "abc@go.example.com", Target: "https://go.dev", OwnerId: &ownerId},
+				{Code: "abc", Target: "https://go.dev", OwnerId: &ownerId},
+				{Code: "abc@sub.go.example.com", Target: "https://go.dev", OwnerId: &ownerId},
+			}
+			require.NoError(t, db.Create(links).Error)
+			require.NoError(t, db.Create(&model.LinkClick{Code: "abc@go.example.com", ClickedAt: time.Now().UTC()}).Error)
+			repo := NewDomainRepository(db)
+
+			codes, err := repo.DeleteOwnedDomain(t.Context(), tc.domain, tc.ownerId)
+
+			var remaining []string
+			require.NoError(t, db.Model(&model.ShortLink{}).Order("code").Pluck("code", &remaining).Error)
+			var clicks int64
+			require.NoError(t, db.Model(&model.LinkClick{}).Count(&clicks).Error)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Len(t, remaining, 3)
+				assert.EqualValues(t, 1, clicks)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"abc@go.example.com"}, codes)
+			assert.Equal(t, []string{"abc", "abc@sub.go.example.com"}, remaining)
+			assert.Zero(t, clicks)
+			_, err = repo.GetOwnedDomain(t.Context(), tc.domain, tc.ownerId)
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		})
+	}
+}
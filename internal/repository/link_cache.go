@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:generate mockery --name=LinkCache --filename=link_cache.go
+
+// LinkCache defines the interface for evicting short links from the redirect cache.
+// It must be called whenever a stored link changes so redirects pick up the change.
+type LinkCache interface {
+	// Invalidate removes the cached link of the given code, if any.
+	Invalidate(ctx context.Context, code string) error
+}
+
+type linkCache struct {
+	c *redis.Client
+}
+
+// NewLinkCache creates a LinkCache for the keys written by NewCachedUrlStorage.
+func NewLinkCache(c *redis.Client) LinkCache {
+	return &linkCache{c: c}
+}
+
+// Invalidate removes the cached link of the given code, if any.
+func (l *linkCache) Invalidate(ctx context.Context, code string) error {
+	return l.c.Del(ctx, code).Err()
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+)
+
+func TestLinkCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	require.NoError(t, redisMock.Set(ctx, "abc", "https://google.com", time.Hour).Err())
+
+	testCache := NewLinkCache(redisMock)
+
+	require.NoError(t, testCache.Invalidate(ctx, "abc"))
+	assert.Zero(t, redisMock.Exists(ctx, "abc").Val())
+	// Invalidating a code that is not cached is not an error
+	assert.NoError(t, testCache.Invalidate(ctx, "unknown"))
+}
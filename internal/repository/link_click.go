@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+// Columns of link_clicks that clicks can be grouped by.
+const (
+	ClickFieldReferrerHost = "referrer_host"
+	ClickFieldDevice       = "device"
+	ClickFieldBrowser      = "browser"
+	ClickFieldOS           = "os"
+	ClickFieldVariant      = "variant"
+)
+
+// ClickFilter selects the clicks of one short code within [From, To).
+// Bot clicks are excluded unless IncludeBots is set.
+type ClickFilter struct {
+	Code        string
+	From        time.Time
+	To          time.Time
+	IncludeBots bool
+}
+
+// ClickCount is the number of clicks sharing the same value of a grouped column.
+type ClickCount struct {
+	Value  string
+	Clicks int64
+}
+
+// ClickBucket is the number of clicks in the time bucket starting at Start.
+type ClickBucket struct {
+	Start  time.Time
+	Clicks int64
+}
+
+// clickBucketFormats format the start of the bucket of a click in Postgres and in SQLite,
+// which backs the tests. Both yield RFC 3339 UTC times.
+var clickBucketFormats = map[string]struct{ postgres, sqlite string }{
+	dto.GranularityHour:  {postgres: "hour", sqlite: "%Y-%m-%dT%H:00:00Z"},
+	dto.GranularityDay:   {postgres: "day", sqlite: "%Y-%m-%dT00:00:00Z"},
+	dto.GranularityMonth: {postgres: "month", sqlite: "%Y-%m-01T00:00:00Z"},
+}
+
+//go:generate mockery --name=LinkClick --filename=link_click.go
+
+// LinkClick defines the interface for the click analytics repository.
+// It provides methods to store recorded redirects and aggregate them.
+type LinkClick interface {
+	// CreateClicks inserts a batch of recorded clicks.
+	CreateClicks(ctx context.Context, clicks []*model.LinkClick) error
+	// CountClicksOverTime counts the clicks matching the filter per UTC hour, day or month,
+	// oldest first. Buckets without clicks are left out. The granularity must be one of
+	// the dto.Granularity constants.
+	CountClicksOverTime(ctx context.Context, filter ClickFilter, granularity string) ([]ClickBucket, error)
+	// CountClicksBy counts the clicks matching the filter grouped by the given field,
+	// most clicked first. The field must be one of the ClickField constants.
+	CountClicksBy(ctx context.Context, filter ClickFilter, field string) ([]ClickCount, error)
+}
+
+type linkClick struct {
+	db *gorm.DB
+}
+
+// NewLinkClick creates a new LinkClick repository backed by the link_clicks table.
+func NewLinkClick(db *gorm.DB) LinkClick {
+	return &linkClick{db: db}
+}
+
+// CreateClicks inserts a batch of recorded clicks.
+func (l *linkClick) CreateClicks(ctx context.Context, clicks []*model.LinkClick) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	return l.db.WithContext(ctx).CreateInBatches(clicks, 100).Error
+}
+
+// CountClicksOverTime counts the clicks matching the filter per UTC hour, day or month, oldest first.
+func (l *linkClick) CountClicksOverTime(ctx context.Context, filter ClickFilter, granularity string) ([]ClickBucket, error) {
+	format, ok := clickBucketFormats[granularity]
+	if !ok {
+		return nil, fmt.Errorf("unsupported granularity %q", granularity)
+	}
+
+	bucket := `to_char(date_trunc('` + format.postgres + `', clicked_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+	if l.db.Name() == "sqlite" {
+		bucket = "strftime('" + format.sqlite + "', clicked_at)"
+	}
+
+	var rows []struct {
+		Bucket string
+		Clicks int64
+	}
+	err := l.filtered(ctx, filter).
+		Select(bucket + " AS bucket, COUNT(*) AS clicks").
+		Group("bucket").
+		Order("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]ClickBucket, len(rows))
+	for i, row := range rows {
+		start, err := time.Parse(time.RFC3339, row.Bucket)
+		if err != nil {
+			return nil, fmt.Errorf("parse click bucket: %w", err)
+		}
+		buckets[i] = ClickBucket{Start: start, Clicks: row.Clicks}
+	}
+
+	return buckets, nil
+}
+
+// CountClicksBy counts the clicks matching the filter grouped by the given field, most clicked first.
+func (l *linkClick) CountClicksBy(ctx context.Context, filter ClickFilter, field string) ([]ClickCount, error) {
+	switch field {
+	case ClickFieldReferrerHost, ClickFieldDevice, ClickFieldBrowser, ClickFieldOS, ClickFieldVariant:
+	default:
+		return nil, fmt.Errorf("unsupported click field %q", field)
+	}
+
+	var counts []ClickCount
+	err := l.filtered(ctx, filter).
+		Select(fmt.Sprintf("%s AS value, COUNT(*) AS clicks", field)).
+		Group(field).
+		Order("clicks DESC, value").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (l *linkClick) filtered(ctx context.Context, filter ClickFilter) *gorm.DB {
+	query := l.db.WithContext(ctx).Model(&model.LinkClick{}).
+		Where("code = ? AND clicked_at >= ? AND clicked_at < ?", filter.Code, filter.From.UTC(), filter.To.UTC())
+	if !filter.IncludeBots {
+		query = query.Where("is_bot = ?", false)
+	}
+
+	return query
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/pkg/sqldb"
+	"gorm.io/gorm"
+)
+
+var testClickBase = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// setupLinkClickDB creates a test database with a few clicks of two codes
+func setupLinkClickDB(t *testing.T) *gorm.DB {
+	db := sqldb.InitMockDb(t)
+	require.NoError(t, db.AutoMigrate(&model.LinkClick{}))
+
+	clicks := []*model.LinkClick{
+		{Code: "abc", ClickedAt: testClickBase.Add(2 * time.Hour), Browser: "Chrome", ReferrerHost: "direct"},
+		{Code: "abc", ClickedAt: testClickBase.Add(1 * time.Hour), Browser: "Firefox", ReferrerHost: "google.com"},
+		{Code: "abc", ClickedAt: testClickBase.Add(3 * time.Hour), Browser: "Chrome", ReferrerHost: "google.com", Variant: "b"},
+		{Code: "abc", ClickedAt: testClickBase.Add(48 * time.Hour), Browser: "Safari", ReferrerHost: "direct"},
+		{Code: "abc", ClickedAt: testClickBase.Add(4 * time.Hour), Browser: "Slackbot", ReferrerHost: "direct", IsBot: true},
+		{Code: "xyz", ClickedAt: testClickBase.Add(1 * time.Hour), Browser: "Chrome", ReferrerHost: "direct"},
+	}
+	require.NoError(t, NewLinkClick(db).CreateClicks(t.Context(), clicks))
+
+	return db
+}
+
+func TestLinkClick_CountClicksOverTime(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		filter      ClickFilter
+		granularity string
+		expected    []ClickBucket
+		expectedErr bool
+	}{
+		{
+			name:        "hourly buckets without bots",
+			filter:      ClickFilter{Code: "abc", From: testClickBase, To: testClickBase.Add(24 * time.Hour)},
+			granularity: "hour",
+			expected: []ClickBucket{
+				{Start: testClickBase.Add(1 * time.Hour), Clicks: 1},
+				{Start: testClickBase.Add(2 * time.Hour), Clicks: 1},
+				{Start: testClickBase.Add(3 * time.Hour), Clicks: 1},
+			},
+		},
+		{
+			name:        "hourly buckets with bots",
+			filter:      ClickFilter{Code: "abc", From: testClickBase, To: testClickBase.Add(24 * time.Hour), IncludeBots: true},
+			granularity: "hour",
+			expected: []ClickBucket{
+				{Start: testClickBase.Add(1 * time.Hour), Clicks: 1},
+				{Start: testClickBase.Add(2 * time.Hour), Clicks: 1},
+				{Start: testClickBase.Add(3 * time.Hour), Clicks: 1},
+				{Start: testClickBase.Add(4 * time.Hour), Clicks: 1},
+			},
+		},
+		{
+			name:        "daily buckets",
+			filter:      ClickFilter{Code: "abc", From: testClickBase, To: testClickBase.Add(72 * time.Hour)},
+			granularity: "day",
+			expected: []ClickBucket{
+				{Start: testClickBase, Clicks: 3},
+				{Start: testClickBase.Add(48 * time.Hour), Clicks: 1},
+			},
+		},
+		{
+			name:        "monthly buckets",
+			filter:      ClickFilter{Code: "abc", From: testClickBase, To: testClickBase.AddDate(0, 1, 0)},
+			granularity: "month",
+			expected:    []ClickBucket{{Start: testClickBase, Clicks: 4}},
+		},
+		{
+			name:        "unsupported granularity",
+			filter:      ClickFilter{Code: "abc", From: testClickBase, To: testClickBase.Add(24 * time.Hour)},
+			granularity: "week",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRepo := NewLinkClick(setupLinkClickDB(t))
+
+			buckets, err := testRepo.CountClicksOverTime(t.Context(), tc.filter, tc.granularity)
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buckets)
+		})
+	}
+}
+
+func TestLinkClick_CountClicksBy(t *testing.T) {
+	t.Parallel()
+
+	filter := ClickFilter{
+		Code: "abc",
+		From: testClickBase,
+		To:   testClickBase.Add(24 * time.Hour),
+	}
+
+	testCases := []struct {
+		name      string
+		field     string
+		expected  []ClickCount
+		expectErr bool
+	}{
+		{
+			name:     "group by browser",
+			field:    ClickFieldBrowser,
+			expected: []ClickCount{{Value: "Chrome", Clicks: 2}, {Value: "Firefox", Clicks: 1}},
+		},
+		{
+			name:     "group by referrer host",
+			field:    ClickFieldReferrerHost,
+			expected: []ClickCount{{Value: "google.com", Clicks: 2}, {Value: "direct", Clicks: 1}},
+		},
+		{
+			name:     "group by variant, the link's own target by default",
+			field:    ClickFieldVariant,
+			expected: []ClickCount{{Value: "default", Clicks: 2}, {Value: "b", Clicks: 1}},
+		},
+		{
+			name:      "unsupported field",
+			field:     "user_agent; DROP TABLE link_clicks",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRepo := NewLinkClick(setupLinkClickDB(t))
+
+			counts, err := testRepo.CountClicksBy(t.Context(), filter, tc.field)
+
+			assert.Equal(t, tc.expectErr, err != nil)
+			assert.Equal(t, tc.expected, counts)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ClickLimit is an autogenerated mock type for the ClickLimit type
+type ClickLimit struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: ctx, code
+func (_m *ClickLimit) Consume(ctx context.Context, code string) (bool, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, code
+func (_m *ClickLimit) Delete(ctx context.Context, code string) error {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: ctx, code, clicks, expiresAt
+func (_m *ClickLimit) Reset(ctx context.Context, code string, clicks int64, expiresAt *time.Time) error {
+	ret := _m.Called(ctx, code, clicks, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *time.Time) error); ok {
+		r0 = rf(ctx, code, clicks, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetExpiry provides a mock function with given fields: ctx, code, expiresAt
+func (_m *ClickLimit) SetExpiry(ctx context.Context, code string, expiresAt *time.Time) error {
+	ret := _m.Called(ctx, code, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetExpiry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) error); ok {
+		r0 = rf(ctx, code, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewClickLimit creates a new instance of ClickLimit. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClickLimit(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClickLimit {
+	mock := &ClickLimit{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
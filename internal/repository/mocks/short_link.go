@@ -0,0 +1,134 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/vincent-tien/bookmark-management/internal/model"
+
+	repository "github.com/vincent-tien/bookmark-management/internal/repository"
+)
+
+// ShortLink is an autogenerated mock type for the ShortLink type
+type ShortLink struct {
+	mock.Mock
+}
+
+// DeleteOwnedLink provides a mock function with given fields: ctx, code, ownerId
+func (_m *ShortLink) DeleteOwnedLink(ctx context.Context, code string, ownerId string) error {
+	ret := _m.Called(ctx, code, ownerId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOwnedLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, code, ownerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOwnedLink provides a mock function with given fields: ctx, code, ownerId
+func (_m *ShortLink) GetOwnedLink(ctx context.Context, code string, ownerId string) (*model.ShortLink, error) {
+	ret := _m.Called(ctx, code, ownerId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOwnedLink")
+	}
+
+	var r0 *model.ShortLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.ShortLink, error)); ok {
+		return rf(ctx, code, ownerId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.ShortLink); ok {
+		r0 = rf(ctx, code, ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ShortLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, code, ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOwnedLinks provides a mock function with given fields: ctx, filter
+func (_m *ShortLink) ListOwnedLinks(ctx context.Context, filter repository.LinkListFilter) ([]model.ShortLink, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOwnedLinks")
+	}
+
+	var r0 []model.ShortLink
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.LinkListFilter) ([]model.ShortLink, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.LinkListFilter) []model.ShortLink); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ShortLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.LinkListFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, repository.LinkListFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateOwnedLink provides a mock function with given fields: ctx, link
+func (_m *ShortLink) UpdateOwnedLink(ctx context.Context, link *model.ShortLink) error {
+	ret := _m.Called(ctx, link)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOwnedLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ShortLink) error); ok {
+		r0 = rf(ctx, link)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewShortLink creates a new instance of ShortLink. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShortLink(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ShortLink {
+	mock := &ShortLink{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
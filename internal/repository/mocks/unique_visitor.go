@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	repository "github.com/vincent-tien/bookmark-management/internal/repository"
+
+	time "time"
+)
+
+// UniqueVisitor is an autogenerated mock type for the UniqueVisitor type
+type UniqueVisitor struct {
+	mock.Mock
+}
+
+// AddVisits provides a mock function with given fields: ctx, visits
+func (_m *UniqueVisitor) AddVisits(ctx context.Context, visits []repository.Visit) error {
+	ret := _m.Called(ctx, visits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddVisits")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []repository.Visit) error); ok {
+		r0 = rf(ctx, visits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountAllTimeVisitors provides a mock function with given fields: ctx, code
+func (_m *UniqueVisitor) CountAllTimeVisitors(ctx context.Context, code string) (int64, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountAllTimeVisitors")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountVisitors provides a mock function with given fields: ctx, code, from, to
+func (_m *UniqueVisitor) CountVisitors(ctx context.Context, code string, from time.Time, to time.Time) (int64, error) {
+	ret := _m.Called(ctx, code, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountVisitors")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (int64, error)); ok {
+		return rf(ctx, code, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) int64); ok {
+		r0 = rf(ctx, code, from, to)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, code, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, code
+func (_m *UniqueVisitor) Delete(ctx context.Context, code string) error {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUniqueVisitor creates a new instance of UniqueVisitor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUniqueVisitor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UniqueVisitor {
+	mock := &UniqueVisitor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
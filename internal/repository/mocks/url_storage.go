@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+
+	model "github.com/vincent-tien/bookmark-management/internal/model"
 )
 
 // UrlStorage is an autogenerated mock type for the UrlStorage type
@@ -42,6 +44,66 @@ func (_m *UrlStorage) CheckKeyExists(ctx context.Context, code string) (bool, er
 	return r0, r1
 }
 
+// CheckKeysExist provides a mock function with given fields: ctx, codes
+func (_m *UrlStorage) CheckKeysExist(ctx context.Context, codes []string) (map[string]bool, error) {
+	ret := _m.Called(ctx, codes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckKeysExist")
+	}
+
+	var r0 map[string]bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]bool, error)); ok {
+		return rf(ctx, codes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]bool); ok {
+		r0 = rf(ctx, codes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, codes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLink provides a mock function with given fields: ctx, code
+func (_m *UrlStorage) GetLink(ctx context.Context, code string) (*model.ShortLink, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLink")
+	}
+
+	var r0 *model.ShortLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.ShortLink, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ShortLink); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ShortLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUrl provides a mock function with given fields: ctx, code
 func (_m *UrlStorage) GetUrl(ctx context.Context, code string) (string, error) {
 	ret := _m.Called(ctx, code)
@@ -88,6 +150,24 @@ func (_m *UrlStorage) Store(ctx context.Context, code string, r dto.LinkShortenR
 	return r0
 }
 
+// StoreMany provides a mock function with given fields: ctx, codes, rs
+func (_m *UrlStorage) StoreMany(ctx context.Context, codes []string, rs []dto.LinkShortenRequestDto) error {
+	ret := _m.Called(ctx, codes, rs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []dto.LinkShortenRequestDto) error); ok {
+		r0 = rf(ctx, codes, rs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUrlStorage creates a new instance of UrlStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUrlStorage(t interface {
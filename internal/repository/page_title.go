@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:generate mockery --name=PageTitle --filename=page_title.go
+
+// PageTitle defines the interface for caching the fetched titles of destination pages.
+// Titles are keyed by URL, so links sharing a destination share the entry.
+type PageTitle interface {
+	// Get returns the cached title of the URL and whether an entry exists.
+	// An existing entry may hold an empty title for pages that have none or could not be fetched.
+	Get(ctx context.Context, url string) (string, bool, error)
+	// Set caches the title of the URL for the given duration.
+	Set(ctx context.Context, url, title string, ttl time.Duration) error
+}
+
+type pageTitle struct {
+	c *redis.Client
+}
+
+// NewPageTitle creates a new Redis backed PageTitle cache.
+func NewPageTitle(c *redis.Client) PageTitle {
+	return &pageTitle{c: c}
+}
+
+// Get returns the cached title of the URL and whether an entry exists.
+func (p *pageTitle) Get(ctx context.Context, url string) (string, bool, error) {
+	title, err := p.c.Get(ctx, pageTitleKey(url)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return title, true, nil
+}
+
+// Set caches the title of the URL for the given duration.
+func (p *pageTitle) Set(ctx context.Context, url, title string, ttl time.Duration) error {
+	return p.c.Set(ctx, pageTitleKey(url), title, ttl).Err()
+}
+
+// pageTitleKey hashes the URL so arbitrarily long destinations map to short keys.
+func pageTitleKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return "title:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+)
+
+func TestPageTitle(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewPageTitle(redisMock)
+
+	_, found, err := testRepo.Get(ctx, "https://example.com")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, testRepo.Set(ctx, "https://example.com", "Example Domain", time.Hour))
+	// Pages without title are cached as well
+	require.NoError(t, testRepo.Set(ctx, "https://example.org", "", time.Hour))
+
+	title, found, err := testRepo.Get(ctx, "https://example.com")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Example Domain", title)
+
+	title, found, err = testRepo.Get(ctx, "https://example.org")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, title)
+}
+
+func TestPageTitle_Error(t *testing.T) {
+	t.Parallel()
+
+	redisMock := redisPkg.InitMockRedis(t)
+	require.NoError(t, redisMock.Close())
+
+	_, _, err := NewPageTitle(redisMock).Get(t.Context(), "https://example.com")
+
+	assert.Error(t, err)
+}
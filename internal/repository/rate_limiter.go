@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitScript increments the counter of the current window and starts the
+// window on the first hit, so INCR and PEXPIRE cannot be separated by a crash.
+// It returns the new count and the milliseconds left in the window.
+var rateLimitScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+// takeScript counts ARGV[2] requests at once unless that takes the counter over the limit
+// in ARGV[3], in which case nothing is counted. It returns the count the window reaches
+// with the requests and the milliseconds left in the window.
+var takeScript = redis.NewScript(`
+local n = tonumber(ARGV[2])
+local count = tonumber(redis.call('GET', KEYS[1]) or '0') + n
+if count <= tonumber(ARGV[3]) then
+	redis.call('INCRBY', KEYS[1], n)
+	if count == n then
+		redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	end
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+//go:generate mockery --name=RateLimiter --filename=rate_limiter.go
+
+// RateLimiter defines the interface for fixed-window request counters.
+type RateLimiter interface {
+	// Hit counts one request against the key and returns the number of requests
+	// in the current window together with the time until the window resets.
+	Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+	// Take counts n requests at once unless they exceed the limit of the window, in which case
+	// none of them are counted. It returns the number of requests the window reaches with the n
+	// requests, above the limit if they were refused, together with the time until the window resets.
+	Take(ctx context.Context, key string, n, limit int64, window time.Duration) (int64, time.Duration, error)
+	// Reset clears the counter of the key, starting a new window with the next hit.
+	Reset(ctx context.Context, key string) error
+}
+
+type rateLimiter struct {
+	c *redis.Client
+}
+
+// NewRateLimiter creates a RateLimiter that keeps its counters in Redis,
+// so limits are shared by all instances of the service.
+func NewRateLimiter(c *redis.Client) RateLimiter {
+	return &rateLimiter{c: c}
+}
+
+// Hit counts one request against the key in the current fixed window.
+func (r *rateLimiter) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	res, err := rateLimitScript.Run(ctx, r.c, []string{"rl:" + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// Take counts n requests against the key if they fit into the limit of the current window.
+func (r *rateLimiter) Take(ctx context.Context, key string, n, limit int64, window time.Duration) (int64, time.Duration, error) {
+	res, err := takeScript.Run(ctx, r.c, []string{"rl:" + key}, window.Milliseconds(), n, limit).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// Reset clears the counter of the key, starting a new window with the next hit.
+func (r *rateLimiter) Reset(ctx context.Context, key string) error {
+	return r.c.Del(ctx, "rl:"+key).Err()
+}
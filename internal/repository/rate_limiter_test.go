@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+)
+
+func TestRateLimiter_Hit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewRateLimiter(redisMock)
+
+	for i := int64(1); i <= 3; i++ {
+		count, resetIn, err := testRepo.Hit(ctx, "shorten:ip:203.0.113.1", time.Minute)
+
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+		assert.Greater(t, resetIn, time.Duration(0))
+		assert.LessOrEqual(t, resetIn, time.Minute)
+	}
+
+	// Keys are counted independently
+	count, _, err := testRepo.Hit(ctx, "shorten:ip:203.0.113.2", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRateLimiter_Hit_Error(t *testing.T) {
+	t.Parallel()
+
+	redisMock := redisPkg.InitMockRedis(t)
+	require.NoError(t, redisMock.Close())
+
+	_, _, err := NewRateLimiter(redisMock).Hit(t.Context(), "shorten:ip:203.0.113.1", time.Minute)
+
+	assert.Error(t, err)
+}
+
+func TestRateLimiter_Take(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewRateLimiter(redisMock)
+
+	_, _, err := testRepo.Hit(ctx, "shorten:user:u1", time.Minute)
+	require.NoError(t, err)
+
+	count, resetIn, err := testRepo.Take(ctx, "shorten:user:u1", 3, 5, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.Greater(t, resetIn, time.Duration(0))
+
+	// Requests over the limit are refused as a whole and not counted
+	count, _, err = testRepo.Take(ctx, "shorten:user:u1", 2, 5, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), count)
+
+	count, _, err = testRepo.Hit(ctx, "shorten:user:u1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	// A refused take on a fresh key reports the full window
+	count, resetIn, err = testRepo.Take(ctx, "shorten:user:u2", 10, 5, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+	assert.Equal(t, time.Minute, resetIn)
+}
+
+func TestRateLimiter_Reset(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewRateLimiter(redisMock)
+
+	for i := 0; i < 2; i++ {
+		_, _, err := testRepo.Hit(ctx, "link_password:abc", time.Minute)
+		require.NoError(t, err)
+	}
+
+	// The next hit starts a new window
+	require.NoError(t, testRepo.Reset(ctx, "link_password:abc"))
+	count, _, err := testRepo.Hit(ctx, "link_password:abc", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Resetting a key without hits is not an error
+	assert.NoError(t, testRepo.Reset(ctx, "link_password:unknown"))
+}
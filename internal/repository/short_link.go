@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+// LinkListFilter selects a page of an owner's links.
+// Search matches the code or the target URL case-insensitively.
+type LinkListFilter struct {
+	OwnerId string
+	Search  string
+	Limit   int
+	Offset  int
+}
+
+//go:generate mockery --name=ShortLink --filename=short_link.go
+
+// ShortLink defines the interface for managing short links on behalf of their owners.
+// Unlike UrlStorage it also returns disabled and expired links.
+type ShortLink interface {
+	// GetOwnedLink retrieves the link with the given code if it belongs to the owner.
+	// Returns gorm.ErrRecordNotFound if the code does not exist or belongs to someone else.
+	GetOwnedLink(ctx context.Context, code, ownerId string) (*model.ShortLink, error)
+	// ListOwnedLinks returns a page of the owner's links, newest first, and the total number of matches.
+	ListOwnedLinks(ctx context.Context, filter LinkListFilter) ([]model.ShortLink, int64, error)
+	// UpdateOwnedLink saves the target, expiry, activation time, disabled and interstitial flags, the routing, the UTM parameters
+	// and the redirect options of an owned link.
+	// Returns gorm.ErrRecordNotFound if the link does not belong to the owner.
+	UpdateOwnedLink(ctx context.Context, link *model.ShortLink) error
+	// DeleteOwnedLink deletes an owned link together with its recorded clicks.
+	// Returns gorm.ErrRecordNotFound if the code does not exist or belongs to someone else.
+	DeleteOwnedLink(ctx context.Context, code, ownerId string) error
+}
+
+type shortLink struct {
+	db *gorm.DB
+}
+
+// NewShortLinkRepository creates a new ShortLink repository backed by the short_links table.
+func NewShortLinkRepository(db *gorm.DB) ShortLink {
+	return &shortLink{db: db}
+}
+
+// NewShortLinkStorage creates a new UrlStorage backed by the short_links table.
+// It is the source of truth for short links and is usually wrapped by NewCachedUrlStorage.
+func NewShortLinkStorage(db *gorm.DB) UrlStorage {
+	return &shortLink{db: db}
+}
+
+// Store inserts a new short link row for the given code.
+// A non-positive ExpInSeconds without NotAfter stores the link without expiry, a non-positive
+// MaxClicks without click limit and an empty OwnerId stores an anonymous link.
+func (s *shortLink) Store(ctx context.Context, code string, r dto.LinkShortenRequestDto) error {
+	return s.db.WithContext(ctx).Create(newShortLink(code, r)).Error
+}
+
+// StoreMany inserts the rows of all links in a single statement, so either all or none are stored.
+func (s *shortLink) StoreMany(ctx context.Context, codes []string, rs []dto.LinkShortenRequestDto) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	links := make([]*model.ShortLink, len(codes))
+	for i, code := range codes {
+		links[i] = newShortLink(code, rs[i])
+	}
+
+	return s.db.WithContext(ctx).Create(links).Error
+}
+
+// newShortLink builds the row of a new short link.
+func newShortLink(code string, r dto.LinkShortenRequestDto) *model.ShortLink {
+	link := &model.ShortLink{
+		Code:   code,
+		Target: r.Url,
+	}
+	if r.OwnerId != "" {
+		link.OwnerId = &r.OwnerId
+	}
+	if r.MaxClicks > 0 {
+		link.MaxClicks = &r.MaxClicks
+	}
+	link.Interstitial = r.Interstitial
+	link.PasswordHash = r.PasswordHash
+	link.Routing = r.Routing.ToRouting()
+	link.Utm = r.Utm.ToParams()
+	link.RedirectStatus = r.RedirectStatus
+	link.NoIndex = r.NoIndex
+	link.ReferrerPolicy = r.ReferrerPolicy
+	link.ExpiresAt = r.ExpiresAt(time.Now())
+	link.NotBefore = utcTime(r.NotBefore)
+
+	return link
+}
+
+// GetUrl retrieves the target URL of the active short link with the given code.
+// Returns gorm.ErrRecordNotFound if the code does not exist, is disabled or has expired.
+func (s *shortLink) GetUrl(ctx context.Context, code string) (string, error) {
+	link, err := s.GetLink(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	return link.Target, nil
+}
+
+// GetLink retrieves the active short link with the given code.
+// Returns gorm.ErrRecordNotFound if the code does not exist, is disabled or has expired.
+// Links waiting for their activation time are returned, callers check NotBefore.
+func (s *shortLink) GetLink(ctx context.Context, code string) (*model.ShortLink, error) {
+	link := &model.ShortLink{}
+	err := s.db.WithContext(ctx).
+		Where("code = ? AND disabled = ?", code, false).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+		First(link).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// CheckKeyExists checks if a code is already taken, including disabled and expired links.
+// Codes are compared case-insensitively so an alias cannot shadow an existing code.
+func (s *shortLink) CheckKeyExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&model.ShortLink{}).Where("LOWER(code) = LOWER(?)", code).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CheckKeysExist checks many codes with a single query, including disabled and expired links.
+// Codes are compared case-insensitively like in CheckKeyExists.
+func (s *shortLink) CheckKeysExist(ctx context.Context, codes []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(codes) == 0 {
+		return existing, nil
+	}
+
+	lowered := make([]string, len(codes))
+	for i, code := range codes {
+		lowered[i] = strings.ToLower(code)
+	}
+
+	var found []string
+	err := s.db.WithContext(ctx).Model(&model.ShortLink{}).Where("LOWER(code) IN ?", lowered).Pluck("LOWER(code)", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(found))
+	for _, code := range found {
+		taken[code] = true
+	}
+	for i, code := range codes {
+		if taken[lowered[i]] {
+			existing[code] = true
+		}
+	}
+
+	return existing, nil
+}
+
+// GetOwnedLink retrieves the link with the given code if it belongs to the owner.
+func (s *shortLink) GetOwnedLink(ctx context.Context, code, ownerId string) (*model.ShortLink, error) {
+	link := &model.ShortLink{}
+	err := s.db.WithContext(ctx).Where("code = ? AND owner_id = ?", code, ownerId).First(link).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// ListOwnedLinks returns a page of the owner's links, newest first, and the total number of matches.
+func (s *shortLink) ListOwnedLinks(ctx context.Context, filter LinkListFilter) ([]model.ShortLink, int64, error) {
+	var total int64
+	if err := s.ownedLinks(ctx, filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	links := make([]model.ShortLink, 0, filter.Limit)
+	err := s.ownedLinks(ctx, filter).
+		Order("created_at DESC, code").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&links).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return links, total, nil
+}
+
+func (s *shortLink) ownedLinks(ctx context.Context, filter LinkListFilter) *gorm.DB {
+	query := s.db.WithContext(ctx).Model(&model.ShortLink{}).Where("owner_id = ?", filter.OwnerId)
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(strings.ToLower(filter.Search)) + "%"
+		query = query.Where(`(LOWER(code) LIKE ? ESCAPE '\' OR LOWER(target) LIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+
+	return query
+}
+
+// UpdateOwnedLink saves the target, expiry, activation time, disabled and interstitial flags, the routing, the UTM parameters
+// and the redirect options of an owned link.
+func (s *shortLink) UpdateOwnedLink(ctx context.Context, link *model.ShortLink) error {
+	if link.OwnerId == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	// Updating from the struct applies the JSON serializer of the routing and UTM parameters
+	res := s.db.WithContext(ctx).Model(&model.ShortLink{}).
+		Where("code = ? AND owner_id = ?", link.Code, *link.OwnerId).
+		Select("target", "expires_at", "not_before", "disabled", "interstitial", "routing", "utm",
+			"redirect_status", "no_index", "referrer_policy").
+		Updates(&model.ShortLink{
+			Target:       link.Target,
+			ExpiresAt:    link.ExpiresAt,
+			NotBefore:    link.NotBefore,
+			Disabled:     link.Disabled,
+			Interstitial: link.Interstitial,
+			Routing:      link.Routing,
+			Utm:          link.Utm,
+
+			RedirectStatus: link.RedirectStatus,
+			NoIndex:        link.NoIndex,
+			ReferrerPolicy: link.ReferrerPolicy,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteOwnedLink deletes an owned link together with its recorded clicks,
+// so that a code freed this way does not inherit old statistics.
+func (s *shortLink) DeleteOwnedLink(ctx context.Context, code, ownerId string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("code = ? AND owner_id = ?", code, ownerId).Delete(&model.ShortLink{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Where("code = ?", code).Delete(&model.LinkClick{}).Error
+	})
+}
+
+// escapeLike escapes the LIKE wildcards of a user supplied search term.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// utcTime returns a copy of the time in UTC, nil for nil.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+	return &utc
+}
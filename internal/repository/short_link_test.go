@@ -0,0 +1,511 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
+	"gorm.io/gorm"
+)
+
+// setupShortLinkDB creates a test database with short link fixture
+func setupShortLinkDB(t *testing.T) *gorm.DB {
+	return fixture.NewFixture(t, &fixture.ShortLinkFixture{})
+}
+
+func TestShortLink_Store(t *testing.T) {
+	t.Parallel()
+
+	launch := time.Date(2099, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		code            string
+		request         dto.LinkShortenRequestDto
+		expectErrString string
+		verifyFunc      func(t *testing.T, db *gorm.DB)
+	}{
+		{
+			name:    "store link with expiry",
+			code:    "newcode1",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org", ExpInSeconds: 60},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode1").First(link).Error)
+				assert.Equal(t, "https://golang.org", link.Target)
+				require.NotNil(t, link.ExpiresAt)
+				assert.WithinDuration(t, time.Now().Add(time.Minute), *link.ExpiresAt, 5*time.Second)
+			},
+		},
+		{
+			name:    "store link without expiry",
+			code:    "newcode2",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org"},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode2").First(link).Error)
+				assert.Nil(t, link.ExpiresAt)
+			},
+		},
+		{
+			name: "store link with routing",
+			code: "newcode4",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org", Routing: &dto.LinkRoutingDto{
+				Variants: []dto.LinkVariantDto{{Name: "b", Url: "https://go.dev", Weight: 1}},
+			}},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode4").First(link).Error)
+				require.NotNil(t, link.Routing)
+				assert.Equal(t, []routing.Variant{{Name: "b", Url: "https://go.dev", Weight: 1}}, link.Routing.Variants)
+			},
+		},
+		{
+			name:    "store link without routing",
+			code:    "newcode5",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org", Routing: &dto.LinkRoutingDto{}},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode5").First(link).Error)
+				assert.Nil(t, link.Routing)
+			},
+		},
+		{
+			name: "store link with utm parameters",
+			code: "newcode6",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org", Utm: &dto.LinkUtmDto{
+				Source: "newsletter", Medium: "email", Campaign: "{code}",
+			}},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode6").First(link).Error)
+				assert.Equal(t, &utm.Params{Source: "newsletter", Medium: "email", Campaign: "{code}"}, link.Utm)
+			},
+		},
+		{
+			name: "store scheduled link, exp counts from its activation time",
+			code: "newcode7",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org", ExpInSeconds: 60,
+				NotBefore: &launch},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode7").First(link).Error)
+				require.NotNil(t, link.NotBefore)
+				assert.True(t, launch.Equal(*link.NotBefore))
+				require.NotNil(t, link.ExpiresAt)
+				assert.True(t, launch.Add(time.Minute).Equal(*link.ExpiresAt))
+			},
+		},
+		{
+			name:    "store link ending at the earlier of exp and not_after",
+			code:    "newcode8",
+			request: dto.LinkShortenRequestDto{Url: "https://golang.org", ExpInSeconds: 3600, NotAfter: &launch},
+			verifyFunc: func(t *testing.T, db *gorm.DB) {
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "newcode8").First(link).Error)
+				assert.Nil(t, link.NotBefore)
+				require.NotNil(t, link.ExpiresAt)
+				assert.WithinDuration(t, time.Now().Add(time.Hour), *link.ExpiresAt, 5*time.Second)
+			},
+		},
+		{
+			name:            "duplicated code",
+			code:            "active01",
+			request:         dto.LinkShortenRequestDto{Url: "https://golang.org"},
+			expectErrString: "UNIQUE constraint failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := setupShortLinkDB(t)
+			testRepo := NewShortLinkStorage(db)
+
+			err := testRepo.Store(t.Context(), tc.code, tc.request)
+
+			if tc.expectErrString != "" {
+				assert.ErrorContains(t, err, tc.expectErrString)
+				return
+			}
+			require.NoError(t, err)
+			tc.verifyFunc(t, db)
+		})
+	}
+}
+
+func TestShortLink_GetUrl(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		code        string
+		expectedUrl string
+		expectErr   error
+	}{
+		{
+			name:        "active link",
+			code:        "active01",
+			expectedUrl: "https://google.com",
+		},
+		{
+			name:        "link without expiry",
+			code:        "forever1",
+			expectedUrl: "https://example.com",
+		},
+		{
+			name:      "expired link",
+			code:      "expired1",
+			expectErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:      "disabled link",
+			code:      "disabled",
+			expectErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:      "unknown code",
+			code:      "unknown1",
+			expectErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRepo := NewShortLinkStorage(setupShortLinkDB(t))
+
+			url, err := testRepo.GetUrl(t.Context(), tc.code)
+
+			assert.Equal(t, tc.expectedUrl, url)
+			assert.ErrorIs(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestShortLink_CheckKeyExists(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupDb        func(t *testing.T) *gorm.DB
+		code           string
+		expectedExists bool
+		expectErr      bool
+	}{
+		{
+			name:           "active code exists",
+			setupDb:        setupShortLinkDB,
+			code:           "active01",
+			expectedExists: true,
+		},
+		{
+			name:           "expired code is still taken",
+			setupDb:        setupShortLinkDB,
+			code:           "expired1",
+			expectedExists: true,
+		},
+		{
+			name:           "code differing only in case is taken",
+			setupDb:        setupShortLinkDB,
+			code:           "ACTIVE01",
+			expectedExists: true,
+		},
+		{
+			name:           "unknown code",
+			setupDb:        setupShortLinkDB,
+			code:           "unknown1",
+			expectedExists: false,
+		},
+		{
+			name: "database error",
+			setupDb: func(t *testing.T) *gorm.DB {
+				db := setupShortLinkDB(t)
+				require.NoError(t, db.Migrator().DropTable(&model.ShortLink{}))
+				return db
+			},
+			code:      "active01",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRepo := NewShortLinkStorage(tc.setupDb(t))
+
+			exists, err := testRepo.CheckKeyExists(context.Background(), tc.code)
+
+			assert.Equal(t, tc.expectedExists, exists)
+			assert.Equal(t, tc.expectErr, err != nil)
+		})
+	}
+}
+
+func TestShortLink_CheckKeysExist(t *testing.T) {
+	t.Parallel()
+
+	testRepo := NewShortLinkStorage(setupShortLinkDB(t))
+
+	existing, err := testRepo.CheckKeysExist(t.Context(), []string{"expired1", "ACTIVE01", "unknown1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"expired1": true, "ACTIVE01": true}, existing)
+}
+
+func TestShortLink_CaseInsensitiveCodeIndex(t *testing.T) {
+	t.Parallel()
+
+	db := setupShortLinkDB(t)
+	// The unique index of migration 20261018100000_short_links_code_ci_index.sql
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX idx_short_links_code_lower ON short_links (LOWER(code))").Error)
+	testRepo := NewShortLinkStorage(db)
+
+	// Codes the existence checks find free can be stored
+	existing, err := testRepo.CheckKeysExist(t.Context(), []string{"Newcode1", "NEWCODE2"})
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+	require.NoError(t, testRepo.StoreMany(t.Context(), []string{"Newcode1", "NEWCODE2"}, []dto.LinkShortenRequestDto{
+		{Url: "https://golang.org"},
+		{Url: "https://go.dev"},
+	}))
+
+	// Codes the index rejects are reported as taken
+	for _, code := range []string{"newcode1", "NewCode2", "Active01"} {
+		exists, err := testRepo.CheckKeyExists(t.Context(), code)
+		require.NoError(t, err)
+		assert.True(t, exists, code)
+		assert.Error(t, testRepo.Store(t.Context(), code, dto.LinkShortenRequestDto{Url: "https://golang.org"}), code)
+	}
+	existing, err = testRepo.CheckKeysExist(t.Context(), []string{"newcode1", "NewCode2", "Active01"})
+	require.NoError(t, err)
+	assert.Len(t, existing, 3)
+}
+
+func TestShortLink_StoreMany(t *testing.T) {
+	t.Parallel()
+
+	db := setupShortLinkDB(t)
+	testRepo := NewShortLinkStorage(db)
+
+	err := testRepo.StoreMany(t.Context(), []string{"bulk0001", "bulk0002"}, []dto.LinkShortenRequestDto{
+		{Url: "https://golang.org", ExpInSeconds: 60, OwnerId: fixture.ShortLinkOwnerID},
+		{Url: "https://go.dev", ExpInSeconds: -1, Interstitial: true},
+	})
+
+	require.NoError(t, err)
+	first, err := NewShortLinkRepository(db).GetOwnedLink(t.Context(), "bulk0001", fixture.ShortLinkOwnerID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://golang.org", first.Target)
+	assert.NotNil(t, first.ExpiresAt)
+	second, err := testRepo.GetLink(t.Context(), "bulk0002")
+	require.NoError(t, err)
+	assert.Nil(t, second.ExpiresAt)
+	assert.True(t, second.Interstitial)
+
+	// A duplicate code rolls back the whole batch
+	err = testRepo.StoreMany(t.Context(), []string{"bulk0003", "bulk0001"}, []dto.LinkShortenRequestDto{
+		{Url: "https://golang.org"},
+		{Url: "https://go.dev"},
+	})
+	assert.Error(t, err)
+	exists, err := testRepo.CheckKeyExists(t.Context(), "bulk0003")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestShortLink_Store_Owner(t *testing.T) {
+	t.Parallel()
+
+	db := setupShortLinkDB(t)
+	testRepo := NewShortLinkStorage(db)
+
+	err := testRepo.Store(t.Context(), "newcode3", dto.LinkShortenRequestDto{Url: "https://golang.org", OwnerId: fixture.ShortLinkOwnerID})
+
+	require.NoError(t, err)
+	link, err := NewShortLinkRepository(db).GetOwnedLink(t.Context(), "newcode3", fixture.ShortLinkOwnerID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://golang.org", link.Target)
+}
+
+func TestShortLink_ListOwnedLinks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		filter        LinkListFilter
+		expectedCodes []string
+		expectedTotal int64
+	}{
+		{
+			name:          "newest first",
+			filter:        LinkListFilter{OwnerId: fixture.ShortLinkOwnerID, Limit: 10},
+			expectedCodes: []string{"owned002", "owned001"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "second page",
+			filter:        LinkListFilter{OwnerId: fixture.ShortLinkOwnerID, Limit: 1, Offset: 1},
+			expectedCodes: []string{"owned001"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "search in target ignoring case",
+			filter:        LinkListFilter{OwnerId: fixture.ShortLinkOwnerID, Search: "GOLANG", Limit: 10},
+			expectedCodes: []string{"owned001"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "wildcards are matched literally",
+			filter:        LinkListFilter{OwnerId: fixture.ShortLinkOwnerID, Search: "%", Limit: 10},
+			expectedCodes: []string{"owned002"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "other owner",
+			filter:        LinkListFilter{OwnerId: "00000000-0000-0000-0000-000000000000", Limit: 10},
+			expectedCodes: []string{},
+			expectedTotal: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testRepo := NewShortLinkRepository(setupShortLinkDB(t))
+
+			links, total, err := testRepo.ListOwnedLinks(t.Context(), tc.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTotal, total)
+			codes := make([]string, 0, len(links))
+			for _, link := range links {
+				codes = append(codes, link.Code)
+			}
+			assert.Equal(t, tc.expectedCodes, codes)
+		})
+	}
+}
+
+func TestShortLink_UpdateOwnedLink(t *testing.T) {
+	t.Parallel()
+
+	owner := fixture.ShortLinkOwnerID
+	otherOwner := "00000000-0000-0000-0000-000000000000"
+
+	testCases := []struct {
+		name        string
+		link        *model.ShortLink
+		expectedErr error
+	}{
+		{
+			name: "update owned link",
+			link: &model.ShortLink{Code: "owned002", Target: "https://go.dev", OwnerId: &owner, Disabled: true, Interstitial: true,
+				Routing:        &routing.Routing{Rules: []routing.Rule{{Name: "mobile", Url: "https://m.go.dev", Device: "mobile"}}},
+				Utm:            &utm.Params{Source: "newsletter", Content: "{variant}"},
+				RedirectStatus: http.StatusPermanentRedirect, NoIndex: true, ReferrerPolicy: "no-referrer"},
+		},
+		{
+			name:        "link of another owner",
+			link:        &model.ShortLink{Code: "owned002", Target: "https://go.dev", OwnerId: &otherOwner},
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:        "anonymous link",
+			link:        &model.ShortLink{Code: "active01", Target: "https://go.dev"},
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := setupShortLinkDB(t)
+			testRepo := NewShortLinkRepository(db)
+
+			err := testRepo.UpdateOwnedLink(t.Context(), tc.link)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			link, err := testRepo.GetOwnedLink(t.Context(), tc.link.Code, owner)
+			require.NoError(t, err)
+			assert.Equal(t, "https://go.dev", link.Target)
+			assert.Nil(t, link.ExpiresAt)
+			assert.True(t, link.Disabled)
+			assert.True(t, link.Interstitial)
+			assert.Equal(t, tc.link.Routing, link.Routing)
+			assert.Equal(t, tc.link.Utm, link.Utm)
+			assert.Equal(t, http.StatusPermanentRedirect, link.RedirectStatus)
+			assert.True(t, link.NoIndex)
+			assert.Equal(t, "no-referrer", link.ReferrerPolicy)
+		})
+	}
+}
+
+func TestShortLink_DeleteOwnedLink(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		code        string
+		ownerId     string
+		expectedErr error
+	}{
+		{
+			name:    "delete owned link and its clicks",
+			code:    "owned001",
+			ownerId: fixture.ShortLinkOwnerID,
+		},
+		{
+			name:        "link of another owner",
+			code:        "owned001",
+			ownerId:     "00000000-0000-0000-0000-000000000000",
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:        "unknown code",
+			code:        "unknown1",
+			ownerId:     fixture.ShortLinkOwnerID,
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := setupShortLinkDB(t)
+			testRepo := NewShortLinkRepository(db)
+
+			err := testRepo.DeleteOwnedLink(t.Context(), tc.code, tc.ownerId)
+
+			var clicks int64
+			require.NoError(t, db.Model(&model.LinkClick{}).Where("code = ?", "owned001").Count(&clicks).Error)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Equal(t, int64(1), clicks)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), clicks)
+			_, err = testRepo.GetOwnedLink(t.Context(), tc.code, tc.ownerId)
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		})
+	}
+}
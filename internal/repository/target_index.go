@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// removeTargetScript deletes the index entry KEYS[1] unless it names another code than ARGV[1]
+// by now, and the back reference KEYS[2] unless it points to another entry by now.
+// Both keys are passed in KEYS as EVAL requires; Remove reads the entry key beforehand.
+var removeTargetScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+end
+if redis.call('GET', KEYS[2]) == KEYS[1] then
+	redis.call('DEL', KEYS[2])
+end
+return 0
+`)
+
+//go:generate mockery --name=TargetIndex --filename=target_index.go
+
+// TargetIndex defines the interface for the reverse index from shortened targets to their codes.
+// Entries are keyed by a fingerprint of the target and the options it was shortened with.
+type TargetIndex interface {
+	// Get returns the code indexed under the fingerprint and whether an entry exists.
+	Get(ctx context.Context, fingerprint string) (string, bool, error)
+	// Set indexes the code under the fingerprint for the given duration, or forever when ttl is 0.
+	Set(ctx context.Context, fingerprint, code string, ttl time.Duration) error
+	// Remove deletes the entry the code is indexed under, so a deleted link is not handed out again.
+	Remove(ctx context.Context, code string) error
+}
+
+type targetIndex struct {
+	c *redis.Client
+}
+
+// NewTargetIndex creates a new Redis backed TargetIndex.
+func NewTargetIndex(c *redis.Client) TargetIndex {
+	return &targetIndex{c: c}
+}
+
+// Get returns the code indexed under the fingerprint and whether an entry exists.
+func (i *targetIndex) Get(ctx context.Context, fingerprint string) (string, bool, error) {
+	code, err := i.c.Get(ctx, targetIndexKey(fingerprint)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return code, true, nil
+}
+
+// Set indexes the code under the fingerprint for the given duration.
+// The code keeps a back reference to its entry for Remove.
+func (i *targetIndex) Set(ctx context.Context, fingerprint, code string, ttl time.Duration) error {
+	key := targetIndexKey(fingerprint)
+	_, err := i.c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, code, ttl)
+		pipe.Set(ctx, targetOfKey(code), key, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// Remove deletes the entry the code was indexed under and the back reference.
+// Codes without a back reference are not indexed, which is not an error.
+func (i *targetIndex) Remove(ctx context.Context, code string) error {
+	backRef := targetOfKey(code)
+	key, err := i.c.Get(ctx, backRef).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return removeTargetScript.Run(ctx, i.c, []string{key, backRef}, code).Err()
+}
+
+// targetOfKey is the key of the back reference from a code to its index entry.
+func targetOfKey(code string) string {
+	return "target_of:" + code
+}
+
+// targetIndexKey hashes the fingerprint so arbitrarily long targets map to short keys.
+func targetIndexKey(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return "target:" + hex.EncodeToString(sum[:])
+}
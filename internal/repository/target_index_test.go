@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+)
+
+func TestTargetIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewTargetIndex(redisMock)
+
+	_, found, err := testRepo.Get(ctx, "owner\nhttps://example.com/")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, testRepo.Set(ctx, "owner\nhttps://example.com/", "abc123", time.Hour))
+	require.NoError(t, testRepo.Set(ctx, "other\nhttps://example.com/", "xyz789", 0))
+
+	code, found, err := testRepo.Get(ctx, "owner\nhttps://example.com/")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "abc123", code)
+
+	code, found, err = testRepo.Get(ctx, "other\nhttps://example.com/")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "xyz789", code)
+
+	ttl, err := redisMock.TTL(ctx, targetIndexKey("owner\nhttps://example.com/")).Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, 59*time.Minute)
+}
+
+func TestTargetIndex_Remove(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewTargetIndex(redisMock)
+
+	require.NoError(t, testRepo.Set(ctx, "owner\nhttps://example.com/", "abc123", time.Hour))
+	require.NoError(t, testRepo.Set(ctx, "owner\nhttps://go.dev/", "old", time.Hour))
+	// The fingerprint was indexed again under a newer code
+	require.NoError(t, testRepo.Set(ctx, "owner\nhttps://go.dev/", "new", time.Hour))
+
+	require.NoError(t, testRepo.Remove(ctx, "abc123"))
+	require.NoError(t, testRepo.Remove(ctx, "old"))
+
+	_, found, err := testRepo.Get(ctx, "owner\nhttps://example.com/")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Zero(t, redisMock.Exists(ctx, "target_of:abc123").Val())
+
+	// Removing an older code keeps the entry of the newer one
+	code, found, err := testRepo.Get(ctx, "owner\nhttps://go.dev/")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "new", code)
+
+	// Codes that were never indexed are not an error
+	assert.NoError(t, testRepo.Remove(ctx, "unknown"))
+}
+
+func TestTargetIndex_Error(t *testing.T) {
+	t.Parallel()
+
+	redisMock := redisPkg.InitMockRedis(t)
+	require.NoError(t, redisMock.Close())
+
+	_, _, err := NewTargetIndex(redisMock).Get(t.Context(), "owner\nhttps://example.com/")
+
+	assert.Error(t, err)
+}
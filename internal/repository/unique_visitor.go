@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+)
+
+const (
+	visitorKeyPrefix  = "uv"
+	visitorDayLayout  = "20060102"
+	visitorDayTTL     = 400 * 24 * time.Hour
+	visitorMergeTTL   = time.Minute
+	visitorMergeIdLen = 12
+)
+
+// Visit is one visitor seen on a short code at a given time.
+// VisitorId must already be an opaque hash; raw IPs or user agents must never be passed.
+type Visit struct {
+	Code      string
+	VisitorId string
+	At        time.Time
+}
+
+//go:generate mockery --name=UniqueVisitor --filename=unique_visitor.go
+
+// UniqueVisitor defines the interface for approximate unique visitor counting.
+// It is backed by Redis HyperLogLogs: one per code and UTC day plus one all-time per code.
+// A set per code lists its daily HyperLogLogs so they can be removed without scanning.
+type UniqueVisitor interface {
+	// AddVisits adds the visits to the daily and all-time HyperLogLogs in one round trip.
+	AddVisits(ctx context.Context, visits []Visit) error
+	// CountVisitors returns the approximate number of unique visitors of the code
+	// over the UTC days touched by [from, to), merging the daily HyperLogLogs.
+	CountVisitors(ctx context.Context, code string, from, to time.Time) (int64, error)
+	// CountAllTimeVisitors returns the approximate number of unique visitors of the code since creation.
+	CountAllTimeVisitors(ctx context.Context, code string) (int64, error)
+	// Delete removes the daily and all-time HyperLogLogs of the code.
+	Delete(ctx context.Context, code string) error
+}
+
+type uniqueVisitor struct {
+	c *redis.Client
+}
+
+// NewUniqueVisitor creates a new UniqueVisitor backed by the given redis client.
+func NewUniqueVisitor(c *redis.Client) UniqueVisitor {
+	return &uniqueVisitor{c: c}
+}
+
+// AddVisits adds the visits to the daily and all-time HyperLogLogs in one round trip.
+func (u *uniqueVisitor) AddVisits(ctx context.Context, visits []Visit) error {
+	if len(visits) == 0 {
+		return nil
+	}
+
+	_, err := u.c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, v := range visits {
+			dayKey := visitorDayKey(v.Code, v.At)
+			pipe.PFAdd(ctx, dayKey, v.VisitorId)
+			pipe.Expire(ctx, dayKey, visitorDayTTL)
+			pipe.SAdd(ctx, visitorDaysKey(v.Code), dayKey)
+			pipe.Expire(ctx, visitorDaysKey(v.Code), visitorDayTTL)
+			pipe.PFAdd(ctx, visitorAllTimeKey(v.Code), v.VisitorId)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// CountVisitors merges the daily HyperLogLogs of [from, to) into a temporary key and counts it.
+func (u *uniqueVisitor) CountVisitors(ctx context.Context, code string, from, to time.Time) (int64, error) {
+	var keys []string
+	for day := truncateDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		keys = append(keys, visitorDayKey(code, day))
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	mergeId, err := utils.GenerateRandomString(visitorMergeIdLen)
+	if err != nil {
+		return 0, err
+	}
+	mergeKey := fmt.Sprintf("%s:%s:merge:%s", visitorKeyPrefix, code, mergeId)
+
+	var count *redis.IntCmd
+	_, err = u.c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.PFMerge(ctx, mergeKey, keys...)
+		pipe.Expire(ctx, mergeKey, visitorMergeTTL)
+		count = pipe.PFCount(ctx, mergeKey)
+		pipe.Del(ctx, mergeKey)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+// CountAllTimeVisitors returns the approximate number of unique visitors of the code since creation.
+func (u *uniqueVisitor) CountAllTimeVisitors(ctx context.Context, code string) (int64, error) {
+	return u.c.PFCount(ctx, visitorAllTimeKey(code)).Result()
+}
+
+// Delete removes the daily HyperLogLogs listed in the set of the code, the set itself
+// and the all-time HyperLogLog.
+func (u *uniqueVisitor) Delete(ctx context.Context, code string) error {
+	daysKey := visitorDaysKey(code)
+	days, err := u.c.SMembers(ctx, daysKey).Result()
+	if err != nil {
+		return err
+	}
+
+	return u.c.Del(ctx, append(days, daysKey, visitorAllTimeKey(code))...).Err()
+}
+
+func visitorDayKey(code string, day time.Time) string {
+	return fmt.Sprintf("%s:%s:%s", visitorKeyPrefix, code, day.UTC().Format(visitorDayLayout))
+}
+
+func visitorAllTimeKey(code string) string {
+	return fmt.Sprintf("%s:%s:all", visitorKeyPrefix, code)
+}
+
+func visitorDaysKey(code string) string {
+	return fmt.Sprintf("%s:%s:days", visitorKeyPrefix, code)
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
+)
+
+func TestUniqueVisitor_CountVisitors(t *testing.T) {
+	t.Parallel()
+
+	day1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	visits := []Visit{
+		{Code: "abc", VisitorId: "alice", At: day1},
+		{Code: "abc", VisitorId: "alice", At: day1.Add(time.Hour)},
+		{Code: "abc", VisitorId: "bob", At: day1},
+		{Code: "abc", VisitorId: "alice", At: day2},
+		{Code: "abc", VisitorId: "carol", At: day3},
+		{Code: "xyz", VisitorId: "dave", At: day1},
+	}
+
+	testCases := []struct {
+		name     string
+		code     string
+		from     time.Time
+		to       time.Time
+		expected int64
+	}{
+		{
+			name:     "repeat visits count once",
+			code:     "abc",
+			from:     day1,
+			to:       day1.Add(time.Minute),
+			expected: 2,
+		},
+		{
+			name:     "visitors are merged across days",
+			code:     "abc",
+			from:     day1,
+			to:       day2,
+			expected: 2,
+		},
+		{
+			name:     "whole range",
+			code:     "abc",
+			from:     day1,
+			to:       day3.Add(time.Minute),
+			expected: 3,
+		},
+		{
+			name:     "days without visits",
+			code:     "abc",
+			from:     day3.AddDate(0, 0, 1),
+			to:       day3.AddDate(0, 0, 5),
+			expected: 0,
+		},
+		{
+			name:     "empty range",
+			code:     "abc",
+			from:     day2,
+			to:       day1,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+			redisMock := redisPkg.InitMockRedis(t)
+			testRepo := NewUniqueVisitor(redisMock)
+			require.NoError(t, testRepo.AddVisits(ctx, visits))
+
+			count, err := testRepo.CountVisitors(ctx, tc.code, tc.from, tc.to)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, count)
+			// The temporary merge key is removed again
+			assert.Empty(t, redisMock.Keys(ctx, "uv:*:merge:*").Val())
+		})
+	}
+}
+
+func TestUniqueVisitor_CountAllTimeVisitors(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewUniqueVisitor(redisMock)
+
+	day1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, testRepo.AddVisits(ctx, []Visit{
+		{Code: "abc", VisitorId: "alice", At: day1},
+		{Code: "abc", VisitorId: "alice", At: day1.AddDate(0, 1, 0)},
+		{Code: "abc", VisitorId: "bob", At: day1.AddDate(1, 0, 0)},
+	}))
+
+	count, err := testRepo.CountAllTimeVisitors(ctx, "abc")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Greater(t, redisMock.TTL(ctx, "uv:abc:20260101").Val(), time.Duration(0))
+}
+
+func TestUniqueVisitor_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewUniqueVisitor(redisMock)
+
+	day1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, testRepo.AddVisits(ctx, []Visit{
+		{Code: "abc", VisitorId: "alice", At: day1},
+		{Code: "abc", VisitorId: "bob", At: day1.AddDate(0, 0, 1)},
+		{Code: "abcd", VisitorId: "alice", At: day1},
+		{Code: "a*", VisitorId: "alice", At: day1},
+	}))
+
+	require.NoError(t, testRepo.Delete(ctx, "abc"))
+	require.NoError(t, testRepo.Delete(ctx, "a*"))
+
+	assert.Empty(t, redisMock.Keys(ctx, "uv:abc:*").Val())
+	assert.Empty(t, redisMock.Keys(ctx, `uv:a\*:*`).Val())
+	// Codes sharing a prefix keep their visitors
+	count, err := testRepo.CountAllTimeVisitors(ctx, "abcd")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Codes without visitors are not an error
+	assert.NoError(t, testRepo.Delete(ctx, "unknown"))
+}
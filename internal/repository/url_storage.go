@@ -6,6 +6,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
 )
 
 //go:generate mockery --name=UrlStorage --filename=url_storage.go
@@ -16,12 +17,21 @@ type UrlStorage interface {
 	// Store stores a URL mapping with the given code and expiration time.
 	// Returns an error if the storage operation fails.
 	Store(ctx context.Context, code string, r dto.LinkShortenRequestDto) error
+	// StoreMany stores the URL mapping of every request under the code at the same index.
+	// Returns an error if the storage operation fails.
+	StoreMany(ctx context.Context, codes []string, rs []dto.LinkShortenRequestDto) error
 	// GetUrl retrieves the original URL associated with the given code.
 	// Returns the URL string and an error if the code is not found or retrieval fails.
 	GetUrl(ctx context.Context, code string) (string, error)
+	// GetLink retrieves the active short link record associated with the given code.
+	// Returns the link and an error if the code is not found or retrieval fails.
+	GetLink(ctx context.Context, code string) (*model.ShortLink, error)
 	// CheckKeyExists checks if a code already exists in storage.
 	// Returns true if the code exists, false otherwise, and an error if the check fails.
 	CheckKeyExists(ctx context.Context, code string) (bool, error)
+	// CheckKeysExist checks many codes at once the way CheckKeyExists does.
+	// Returns the set of requested codes that already exist.
+	CheckKeysExist(ctx context.Context, codes []string) (map[string]bool, error)
 }
 
 type urlStorage struct {
@@ -40,12 +50,35 @@ func (s *urlStorage) Store(ctx context.Context, code string, r dto.LinkShortenRe
 	return s.c.Set(ctx, code, r.Url, time.Second*time.Duration(r.ExpInSeconds)).Err()
 }
 
+// StoreMany stores all URL mappings in a single pipeline.
+func (s *urlStorage) StoreMany(ctx context.Context, codes []string, rs []dto.LinkShortenRequestDto) error {
+	_, err := s.c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, code := range codes {
+			pipe.Set(ctx, code, rs[i].Url, time.Second*time.Duration(rs[i].ExpInSeconds))
+		}
+		return nil
+	})
+
+	return err
+}
+
 // GetUrl retrieves the original URL associated with the given code.
 // Returns the URL string and an error if the code is not found or retrieval fails.
 func (s *urlStorage) GetUrl(ctx context.Context, code string) (string, error) {
 	return s.c.Get(ctx, code).Result()
 }
 
+// GetLink retrieves the short link record associated with the given code.
+// Plain Redis keys only hold the target URL, so the record carries no metadata.
+func (s *urlStorage) GetLink(ctx context.Context, code string) (*model.ShortLink, error) {
+	url, err := s.GetUrl(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ShortLink{Code: code, Target: url}, nil
+}
+
 // CheckKeyExists checks if a code already exists in storage.
 // Returns true if the code exists, false otherwise, and an error if the check fails.
 func (s *urlStorage) CheckKeyExists(ctx context.Context, code string) (bool, error) {
@@ -56,3 +89,31 @@ func (s *urlStorage) CheckKeyExists(ctx context.Context, code string) (bool, err
 
 	return count > 0, nil
 }
+
+// CheckKeysExist checks all codes in a single pipeline.
+func (s *urlStorage) CheckKeysExist(ctx context.Context, codes []string) (map[string]bool, error) {
+	return existingKeys(ctx, s.c, codes)
+}
+
+// existingKeys returns the set of codes that exist as Redis keys, using a single pipeline.
+func existingKeys(ctx context.Context, c *redis.Client, codes []string) (map[string]bool, error) {
+	cmds := make([]*redis.IntCmd, len(codes))
+	_, err := c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, code := range codes {
+			cmds[i] = pipe.Exists(ctx, code)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	for i, cmd := range cmds {
+		if cmd.Val() > 0 {
+			existing[codes[i]] = true
+		}
+	}
+
+	return existing, nil
+}
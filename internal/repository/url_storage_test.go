@@ -6,6 +6,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
 	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
 )
@@ -204,3 +205,29 @@ func TestUrlStorage_CheckKeyExists(t *testing.T) {
 		})
 	}
 }
+
+func TestUrlStorage_StoreMany(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisMock := redisPkg.InitMockRedis(t)
+	testRepo := NewUrlStorage(redisMock)
+
+	err := testRepo.StoreMany(ctx, []string{"bulk0001", "bulk0002"}, []dto.LinkShortenRequestDto{
+		{Url: "https://golang.org", ExpInSeconds: 60},
+		{Url: "https://go.dev", ExpInSeconds: 120},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://golang.org", redisMock.Get(ctx, "bulk0001").Val())
+	assert.Equal(t, "https://go.dev", redisMock.Get(ctx, "bulk0002").Val())
+
+	existing, err := testRepo.CheckKeysExist(ctx, []string{"bulk0001", "BULK0002", "bulk0002"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"bulk0001": true, "bulk0002": true}, existing)
+
+	require.NoError(t, redisMock.Close())
+	assert.Error(t, testRepo.StoreMany(ctx, []string{"bulk0003"}, []dto.LinkShortenRequestDto{{Url: "https://go.dev"}}))
+	_, err = testRepo.CheckKeysExist(ctx, []string{"bulk0001"})
+	assert.Error(t, err)
+}
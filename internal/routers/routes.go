@@ -4,7 +4,16 @@ package routers
 type Routes struct {
 	HealthCheck  string // Health check endpoint path
 	LinkShorten  string // Link shorten endpoint path
+	LinkBulk     string // LinkBulk is the endpoint path shortening many links at once
 	LinkRedirect string // Link redirect endpoint path
+	LinkPreview  string // LinkPreview is the endpoint path showing where a short link goes
+	LinkStats    string // LinkStats is the short link click statistics endpoint path
+	LinkQr       string // LinkQr is the short link QR code endpoint path
+	MyLinks      string // MyLinks is the endpoint path listing the caller's short links
+	MyLink       string // MyLink is the endpoint path managing one of the caller's short links
+	Domains      string // Domains is the endpoint path registering and listing the caller's custom domains
+	Domain       string // Domain is the endpoint path managing one of the caller's custom domains
+	DomainVerify string // DomainVerify is the endpoint path verifying one of the caller's custom domains
 	UserRegister string // Link Users register endpoint path
 	AuthLogin    string // AuthLogin is the authentication login endpoint path
 	GetProfile   string // GetProfile is the user profile retrieval endpoint path
@@ -13,7 +22,16 @@ type Routes struct {
 var Endpoints = Routes{
 	HealthCheck:  "/health-check",
 	LinkShorten:  "/links/shorten",
+	LinkBulk:     "/links/shorten/bulk",
 	LinkRedirect: "/links/redirect/*code",
+	LinkPreview:  "/links/preview/:code",
+	LinkStats:    "/links/:code/stats",
+	LinkQr:       "/links/:code/qr",
+	MyLinks:      "/links",
+	MyLink:       "/links/:code",
+	Domains:      "/domains",
+	Domain:       "/domains/:domain",
+	DomainVerify: "/domains/:domain/verify",
 	UserRegister: "/users/register",
 	AuthLogin:    "/users/login",
 	GetProfile:   "/self/info",
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/useragent"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+)
+
+const (
+	clickBufferSize    = 4096
+	clickBatchSize     = 200
+	clickFlushInterval = 2 * time.Second
+	clickFlushTimeout  = 5 * time.Second
+
+	directReferrer = "direct"
+)
+
+// ClickEvent holds the raw request details of a redirect.
+// The IP address is anonymized before anything is persisted.
+type ClickEvent struct {
+	Code      string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	IP        string
+	// Variant names the routing rule or variant the visitor was sent to
+	Variant string
+}
+
+//go:generate mockery --name=ClickRecorder --filename=click_recorder.go
+
+// ClickRecorder defines the interface for recording redirects for analytics.
+// Recording must never block or fail the redirect itself.
+// Besides the click log it feeds the unique visitor counters.
+type ClickRecorder interface {
+	// Record queues a click for asynchronous processing.
+	// Clicks are dropped when the queue is full.
+	Record(event ClickEvent)
+	// Close flushes queued clicks and stops the background worker.
+	Close()
+}
+
+type clickRecorder struct {
+	repo     repository.LinkClick
+	visitors repository.UniqueVisitor
+	salt     string
+	events   chan ClickEvent
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewClickRecorder creates a ClickRecorder and starts its background worker.
+// The worker parses user agents, anonymizes IPs and writes clicks in batches.
+// Unique visitors are identified by a salted hash of IP and user agent.
+func NewClickRecorder(repo repository.LinkClick, visitors repository.UniqueVisitor, salt string) ClickRecorder {
+	r := &clickRecorder{
+		repo:     repo,
+		visitors: visitors,
+		salt:     salt,
+		events:   make(chan ClickEvent, clickBufferSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record queues a click for asynchronous processing.
+func (r *clickRecorder) Record(event ClickEvent) {
+	select {
+	case r.events <- event:
+	default:
+		log.Warn().Str("code", event.Code).Msg("Click queue is full, dropping click")
+	}
+}
+
+// Close flushes queued clicks and stops the background worker.
+func (r *clickRecorder) Close() {
+	r.once.Do(func() { close(r.stop) })
+	<-r.done
+}
+
+func (r *clickRecorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(clickFlushInterval)
+	defer ticker.Stop()
+
+	b := &clickBatch{
+		clicks: make([]*model.LinkClick, 0, clickBatchSize),
+		visits: make([]repository.Visit, 0, clickBatchSize),
+	}
+	for {
+		select {
+		case event := <-r.events:
+			r.add(b, event)
+			if len(b.clicks) >= clickBatchSize {
+				r.flush(b)
+			}
+		case <-ticker.C:
+			r.flush(b)
+		case <-r.stop:
+			for {
+				select {
+				case event := <-r.events:
+					r.add(b, event)
+				default:
+					r.flush(b)
+					return
+				}
+			}
+		}
+	}
+}
+
+// clickBatch holds the pending writes of the worker.
+type clickBatch struct {
+	clicks []*model.LinkClick
+	visits []repository.Visit
+}
+
+// add appends the click to the batch. Bots are logged as clicks but never
+// counted as unique visitors.
+func (r *clickRecorder) add(b *clickBatch, event ClickEvent) {
+	click := newLinkClick(event)
+	b.clicks = append(b.clicks, click)
+	if click.IsBot {
+		return
+	}
+
+	b.visits = append(b.visits, repository.Visit{
+		Code:      event.Code,
+		VisitorId: r.visitorId(event),
+		At:        event.ClickedAt,
+	})
+}
+
+// flush writes the pending clicks and visits and empties the batch for reuse.
+func (r *clickRecorder) flush(b *clickBatch) {
+	if len(b.clicks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clickFlushTimeout)
+	defer cancel()
+
+	if err := r.repo.CreateClicks(ctx, b.clicks); err != nil {
+		log.Error().Err(err).Int("clicks", len(b.clicks)).Msg("Failed to store clicks")
+	}
+	if len(b.visits) > 0 {
+		if err := r.visitors.AddVisits(ctx, b.visits); err != nil {
+			log.Error().Err(err).Int("visits", len(b.visits)).Msg("Failed to count unique visitors")
+		}
+	}
+
+	b.clicks = b.clicks[:0]
+	b.visits = b.visits[:0]
+}
+
+// visitorId derives an opaque visitor identifier from the salted IP and user agent.
+func (r *clickRecorder) visitorId(event ClickEvent) string {
+	sum := sha256.Sum256([]byte(r.salt + "|" + event.IP + "|" + event.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// newLinkClick converts a raw event into the stored click record.
+func newLinkClick(event ClickEvent) *model.LinkClick {
+	info := useragent.Parse(event.UserAgent)
+
+	return &model.LinkClick{
+		Code:         event.Code,
+		ClickedAt:    event.ClickedAt.UTC(),
+		Referrer:     event.Referrer,
+		ReferrerHost: referrerHost(event.Referrer),
+		UserAgent:    event.UserAgent,
+		Device:       info.Device,
+		Browser:      info.Browser,
+		OS:           info.OS,
+		IpAddress:    utils.AnonymizeIP(event.IP),
+		IsBot:        info.Bot,
+		Variant:      event.Variant,
+	}
+}
+
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return directReferrer
+	}
+
+	u, err := url.Parse(referrer)
+	if err != nil || u.Hostname() == "" {
+		return directReferrer
+	}
+
+	return u.Hostname()
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+)
+
+func TestClickRecorder_Record(t *testing.T) {
+	t.Parallel()
+
+	clickedAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		event         ClickEvent
+		expectedClick *model.LinkClick
+		isBot         bool
+	}{
+		{
+			name: "click is parsed and anonymized",
+			event: ClickEvent{
+				Code:      "abc",
+				ClickedAt: clickedAt,
+				Referrer:  "https://www.google.com/search?q=test",
+				UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+				IP:        "203.0.113.42",
+			},
+			expectedClick: &model.LinkClick{
+				Code:         "abc",
+				ClickedAt:    clickedAt,
+				Referrer:     "https://www.google.com/search?q=test",
+				ReferrerHost: "www.google.com",
+				UserAgent:    "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+				Device:       "desktop",
+				Browser:      "Firefox",
+				OS:           "Linux",
+				IpAddress:    "203.0.113.0",
+			},
+		},
+		{
+			name: "missing referrer is direct traffic",
+			event: ClickEvent{
+				Code:      "abc",
+				ClickedAt: clickedAt,
+				IP:        "2001:db8:1234:5678::1",
+			},
+			expectedClick: &model.LinkClick{
+				Code:         "abc",
+				ClickedAt:    clickedAt,
+				ReferrerHost: "direct",
+				Device:       "unknown",
+				Browser:      "Other",
+				OS:           "Other",
+				IpAddress:    "2001:db8:1234::",
+			},
+		},
+		{
+			name: "link preview bot is flagged and not counted as visitor",
+			event: ClickEvent{
+				Code:      "abc",
+				ClickedAt: clickedAt,
+				UserAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+				IP:        "203.0.113.42",
+			},
+			expectedClick: &model.LinkClick{
+				Code:         "abc",
+				ClickedAt:    clickedAt,
+				ReferrerHost: "direct",
+				UserAgent:    "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+				Device:       "bot",
+				Browser:      "Slackbot",
+				OS:           "Other",
+				IpAddress:    "203.0.113.0",
+				IsBot:        true,
+			},
+			isBot: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := mocks.NewLinkClick(t)
+			mockRepo.On("CreateClicks", mock.Anything, []*model.LinkClick{tc.expectedClick}).Return(nil).Once()
+			mockVisitors := mocks.NewUniqueVisitor(t)
+			if !tc.isBot {
+				mockVisitors.On("AddVisits", mock.Anything, mock.MatchedBy(func(visits []repository.Visit) bool {
+					return len(visits) == 1 && visits[0].Code == tc.event.Code &&
+						len(visits[0].VisitorId) == 64 && !strings.Contains(visits[0].VisitorId, tc.event.IP)
+				})).Return(nil).Once()
+			}
+
+			recorder := NewClickRecorder(mockRepo, mockVisitors, "test-salt")
+			recorder.Record(tc.event)
+			// Close flushes the queued click
+			recorder.Close()
+		})
+	}
+}
+
+func TestClickRecorder_CloseWithoutClicks(t *testing.T) {
+	t.Parallel()
+
+	// No clicks means no write
+	recorder := NewClickRecorder(mocks.NewLinkClick(t), mocks.NewUniqueVisitor(t), "test-salt")
+	recorder.Close()
+	recorder.Close()
+
+	assert.NotPanics(t, func() { recorder.Record(ClickEvent{Code: "abc"}) })
+}
+
+func TestClickRecorder_VisitorId(t *testing.T) {
+	t.Parallel()
+
+	recorder := &clickRecorder{salt: "test-salt"}
+	event := ClickEvent{IP: "203.0.113.42", UserAgent: "Firefox"}
+
+	// Same visitor hashes the same, any change to IP, user agent or salt does not
+	assert.Equal(t, recorder.visitorId(event), recorder.visitorId(event))
+	assert.NotEqual(t, recorder.visitorId(event), recorder.visitorId(ClickEvent{IP: "203.0.113.43", UserAgent: "Firefox"}))
+	assert.NotEqual(t, recorder.visitorId(event), recorder.visitorId(ClickEvent{IP: "203.0.113.42", UserAgent: "Chrome"}))
+	assert.NotEqual(t, recorder.visitorId(event), (&clickRecorder{salt: "other"}).visitorId(event))
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/shortcode"
+)
+
+// Code generation strategies selectable in CodeGeneratorConfig.
+const (
+	// CodeStrategyRandom draws random codes and reserves them atomically.
+	CodeStrategyRandom = "random"
+	// CodeStrategyCounter encodes a shared Redis counter in the alphabet, lowercase base36 by default.
+	CodeStrategyCounter = "counter"
+	// CodeStrategyHashids obfuscates the shared counter so codes do not reveal their order.
+	CodeStrategyHashids = "hashids"
+)
+
+const (
+	defaultCodeLength    = 8
+	defaultCodeMaxLength = 16
+	// Generated codes are kept short; the varchar(300) code column also holds the custom domain of a link.
+	maxCodeLength = 32
+
+	// A reserved code is held this long, enough for its link to be stored.
+	codeReservationTTL = 10 * time.Minute
+
+	// Random codes grow by one character once more than collisionGrowthPercent
+	// of the last collisionWindow attempts hit a taken code.
+	collisionWindow        = 50
+	collisionGrowthPercent = 10
+)
+
+//go:generate mockery --name=CodeGenerator --filename=code_generator.go
+
+// CodeGenerator defines the interface for handing out short codes.
+// Every code it returns is reserved across instances, so no two links get the same code.
+type CodeGenerator interface {
+	// Generate returns a new code that no link uses yet.
+	// Returns ErrKeyAlreadyExists if no free code was found.
+	Generate(ctx context.Context) (string, error)
+	// GenerateMany returns n distinct new codes, reserving them in batches instead of one by one.
+	// Returns ErrKeyAlreadyExists if not enough free codes were found.
+	GenerateMany(ctx context.Context, n int) ([]string, error)
+	// Reserve claims a caller-chosen code such as an alias. Aliases are unique regardless of case.
+	// Returns false if a link or a concurrent request already uses the code.
+	Reserve(ctx context.Context, alias string) (bool, error)
+}
+
+// CodeGeneratorConfig selects and tunes the code generation strategy.
+// Zero values fall back to the random strategy with 8 to 16 base62 characters.
+// Codes are unique regardless of case, so counter and hashids codes need an alphabet
+// without case variants.
+type CodeGeneratorConfig struct {
+	// Strategy is one of CodeStrategyRandom, CodeStrategyCounter and CodeStrategyHashids
+	Strategy string
+	// Length of random codes, and the minimum length of counter and hashids codes
+	Length int
+	// MaxLength caps the growth of random codes
+	MaxLength int
+	// Alphabet codes are written in. When empty, random codes use shortcode.DefaultAlphabet
+	// and counter and hashids codes use shortcode.LowerAlphabet
+	Alphabet string
+	// Salt keys the hashids obfuscation
+	Salt string
+}
+
+// NewCodeGenerator creates the CodeGenerator selected by the config.
+// Returns an error for unknown strategies, invalid alphabets or lengths.
+func NewCodeGenerator(cfg CodeGeneratorConfig, repo repository.UrlStorage, reservations repository.CodeReservation) (CodeGenerator, error) {
+	sequential := cfg.Strategy == CodeStrategyCounter || cfg.Strategy == CodeStrategyHashids
+	if cfg.Alphabet == "" {
+		cfg.Alphabet = shortcode.DefaultAlphabet
+		if sequential {
+			cfg.Alphabet = shortcode.LowerAlphabet
+		}
+	}
+	if cfg.Length == 0 {
+		cfg.Length = defaultCodeLength
+	}
+	if cfg.MaxLength == 0 {
+		cfg.MaxLength = max(defaultCodeMaxLength, cfg.Length)
+	}
+	if err := shortcode.ValidateAlphabet(cfg.Alphabet); err != nil {
+		return nil, err
+	}
+	// Counter values that only differ in case would all collide after the first
+	if sequential && !shortcode.IsCaseless(cfg.Alphabet) {
+		return nil, fmt.Errorf("%w: %s codes need an alphabet without case variants", shortcode.ErrInvalidAlphabet, cfg.Strategy)
+	}
+	if cfg.Length < 1 || cfg.MaxLength < cfg.Length || cfg.MaxLength > maxCodeLength {
+		return nil, fmt.Errorf("invalid code length %d to %d", cfg.Length, cfg.MaxLength)
+	}
+
+	codes := codeRegistry{repo: repo, reservations: reservations}
+	switch cfg.Strategy {
+	case "", CodeStrategyRandom:
+		return &randomCodes{
+			codeRegistry: codes,
+			alphabet:     cfg.Alphabet,
+			length:       cfg.Length,
+			maxLength:    cfg.MaxLength,
+		}, nil
+	case CodeStrategyCounter:
+		return &sequenceCodes{
+			codeRegistry: codes,
+			minLength:    cfg.Length,
+			encode: func(n uint64, minLength int) string {
+				return shortcode.Encode(n, cfg.Alphabet, minLength)
+			},
+		}, nil
+	case CodeStrategyHashids:
+		return &sequenceCodes{
+			codeRegistry: codes,
+			minLength:    cfg.Length,
+			encode:       shortcode.NewObfuscator(cfg.Alphabet, cfg.Salt).Encode,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown code strategy %q", cfg.Strategy)
+}
+
+// codeRegistry reserves codes shared by all strategies.
+type codeRegistry struct {
+	repo         repository.UrlStorage
+	reservations repository.CodeReservation
+}
+
+// Reserve claims the code case-insensitively and checks that no link uses it in any case,
+// as the unique index on LOWER(code) would reject it. The reservation comes first, so
+// concurrent requests cannot both pass the existence check.
+func (r codeRegistry) Reserve(ctx context.Context, code string) (bool, error) {
+	reserved, err := r.reservations.Reserve(ctx, strings.ToLower(code), codeReservationTTL)
+	if err != nil || !reserved {
+		return false, err
+	}
+
+	taken, err := r.repo.CheckKeyExists(ctx, code)
+	if err != nil {
+		return false, err
+	}
+
+	return !taken, nil
+}
+
+// reserveMany claims codes in batches the way Reserve does and returns the ones no link uses.
+// Codes that are reserved but turn out to be taken are left to expire, and of two codes
+// differing in case only the first can be reserved.
+func (r codeRegistry) reserveMany(ctx context.Context, codes []string) ([]string, error) {
+	keys := make([]string, len(codes))
+	for i, code := range codes {
+		keys[i] = strings.ToLower(code)
+	}
+
+	reserved, err := r.reservations.ReserveMany(ctx, keys, codeReservationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]string, 0, len(codes))
+	for i, code := range codes {
+		if reserved[i] {
+			claimed = append(claimed, code)
+		}
+	}
+	if len(claimed) == 0 {
+		return claimed, nil
+	}
+
+	taken, err := r.repo.CheckKeysExist(ctx, claimed)
+	if err != nil {
+		return nil, err
+	}
+
+	free := claimed[:0]
+	for _, code := range claimed {
+		if !taken[code] {
+			free = append(free, code)
+		}
+	}
+
+	return free, nil
+}
+
+// randomCodes draws random codes and grows them when the code space fills up.
+type randomCodes struct {
+	codeRegistry
+	alphabet  string
+	maxLength int
+
+	mu         sync.Mutex
+	length     int
+	attempts   int
+	collisions int
+}
+
+// Generate draws random codes until one can be reserved.
+func (g *randomCodes) Generate(ctx context.Context) (string, error) {
+	for i := 0; i < defaultThreshold; i++ {
+		code, err := shortcode.Random(g.alphabet, g.currentLength())
+		if err != nil {
+			return "", err
+		}
+
+		ok, err := g.Reserve(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		g.record(!ok)
+		if ok {
+			return code, nil
+		}
+	}
+
+	// Every attempt collided: do not wait for the window to fill up
+	g.grow()
+	return "", e.ErrKeyAlreadyExists
+}
+
+// GenerateMany draws the missing codes in rounds until all n could be reserved.
+func (g *randomCodes) GenerateMany(ctx context.Context, n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < defaultThreshold && len(codes) < n; i++ {
+		length := g.currentLength()
+		candidates := make([]string, n-len(codes))
+		for j := range candidates {
+			code, err := shortcode.Random(g.alphabet, length)
+			if err != nil {
+				return nil, err
+			}
+			candidates[j] = code
+		}
+
+		free, err := g.reserveMany(ctx, candidates)
+		if err != nil {
+			return nil, err
+		}
+		g.recordMany(len(candidates), len(candidates)-len(free))
+		codes = append(codes, free...)
+	}
+
+	if len(codes) < n {
+		g.grow()
+		return nil, e.ErrKeyAlreadyExists
+	}
+
+	return codes, nil
+}
+
+func (g *randomCodes) currentLength() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.length
+}
+
+// record counts an attempt and grows the codes once the collision rate of the window is too high.
+func (g *randomCodes) record(collided bool) {
+	if collided {
+		g.recordMany(1, 1)
+	} else {
+		g.recordMany(1, 0)
+	}
+}
+
+// recordMany counts a batch of attempts the way record counts a single one.
+func (g *randomCodes) recordMany(attempts, collisions int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.attempts += attempts
+	g.collisions += collisions
+	if g.attempts < collisionWindow {
+		return
+	}
+	if g.collisions*100 > g.attempts*collisionGrowthPercent {
+		g.growLocked()
+	}
+	g.attempts, g.collisions = 0, 0
+}
+
+func (g *randomCodes) grow() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.growLocked()
+	g.attempts, g.collisions = 0, 0
+}
+
+func (g *randomCodes) growLocked() {
+	if g.length >= g.maxLength {
+		log.Warn().Int("length", g.length).Msg("Short code collision rate is high at maximum code length")
+		return
+	}
+
+	g.length++
+	log.Info().Int("length", g.length).Msg("Short code collision rate is high, growing code length")
+}
+
+// sequenceCodes encodes numbers of the shared counter. Counter values are never
+// handed out twice, so only aliases and codes of other strategies can collide.
+type sequenceCodes struct {
+	codeRegistry
+	minLength int
+	encode    func(n uint64, minLength int) string
+}
+
+// Generate encodes the next counter values until one can be reserved.
+func (g *sequenceCodes) Generate(ctx context.Context) (string, error) {
+	for i := 0; i < defaultThreshold; i++ {
+		n, err := g.reservations.NextSequence(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		code := g.encode(uint64(n), g.minLength)
+		ok, err := g.Reserve(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return code, nil
+		}
+	}
+
+	return "", e.ErrKeyAlreadyExists
+}
+
+// GenerateMany encodes blocks of counter values until n codes could be reserved.
+func (g *sequenceCodes) GenerateMany(ctx context.Context, n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < defaultThreshold && len(codes) < n; i++ {
+		missing := n - len(codes)
+		first, err := g.reservations.NextSequences(ctx, int64(missing))
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := make([]string, missing)
+		for j := range candidates {
+			candidates[j] = g.encode(uint64(first)+uint64(j), g.minLength)
+		}
+
+		free, err := g.reserveMany(ctx, candidates)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, free...)
+	}
+
+	if len(codes) < n {
+		return nil, e.ErrKeyAlreadyExists
+	}
+
+	return codes, nil
+}
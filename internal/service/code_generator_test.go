@@ -0,0 +1,490 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/shortcode"
+)
+
+// newUnusedUrlStorage returns a mock that fails the test if it is called
+func newUnusedUrlStorage(t *testing.T) *mocks.UrlStorage {
+	return mocks.NewUrlStorage(t)
+}
+
+func TestNewCodeGenerator(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		cfg       CodeGeneratorConfig
+		expectErr error
+		isErr     bool
+	}{
+		{name: "defaults", cfg: CodeGeneratorConfig{}},
+		{name: "counter", cfg: CodeGeneratorConfig{Strategy: CodeStrategyCounter, Length: 4}},
+		{name: "hashids", cfg: CodeGeneratorConfig{Strategy: CodeStrategyHashids, Salt: "pepper"}},
+		{name: "unknown strategy", cfg: CodeGeneratorConfig{Strategy: "uuid"}, isErr: true},
+		{name: "invalid alphabet", cfg: CodeGeneratorConfig{Alphabet: "abc+/"}, expectErr: shortcode.ErrInvalidAlphabet, isErr: true},
+		{name: "mixed case random alphabet", cfg: CodeGeneratorConfig{Alphabet: shortcode.DefaultAlphabet}},
+		{
+			name:      "mixed case counter alphabet",
+			cfg:       CodeGeneratorConfig{Strategy: CodeStrategyCounter, Alphabet: shortcode.DefaultAlphabet},
+			expectErr: shortcode.ErrInvalidAlphabet,
+			isErr:     true,
+		},
+		{
+			name:      "mixed case hashids alphabet",
+			cfg:       CodeGeneratorConfig{Strategy: CodeStrategyHashids, Alphabet: "0123456789abcdefA"},
+			expectErr: shortcode.ErrInvalidAlphabet,
+			isErr:     true,
+		},
+		{name: "uppercase counter alphabet", cfg: CodeGeneratorConfig{Strategy: CodeStrategyCounter, Alphabet: "0123456789ABCDEF"}},
+		{name: "maximum below length", cfg: CodeGeneratorConfig{Length: 10, MaxLength: 8}, isErr: true},
+		{name: "longer than a code column", cfg: CodeGeneratorConfig{Length: 8, MaxLength: 40}, isErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			codes, err := NewCodeGenerator(tc.cfg, mocks.NewUrlStorage(t), mocks.NewCodeReservation(t))
+
+			assert.Equal(t, tc.isErr, err != nil)
+			assert.Equal(t, tc.isErr, codes == nil)
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestCodeGenerator_Random(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		setupStorage      func(t *testing.T) *mocks.UrlStorage
+		setupReservations func(t *testing.T) *mocks.CodeReservation
+		expectedError     error
+		expectedLength    int
+	}{
+		{
+			name: "first code is free",
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil).Once()
+				return storage
+			},
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				// Generated codes are reserved regardless of case, like aliases
+				reservations.On("Reserve", mock.Anything, mock.MatchedBy(func(key string) bool { return key == strings.ToLower(key) }), codeReservationTTL).
+					Return(true, nil).Once()
+				return reservations
+			},
+			expectedLength: 6,
+		},
+		{
+			name: "code reserved by another instance is skipped",
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil).Once()
+				return storage
+			},
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(false, nil).Once()
+				reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(true, nil).Once()
+				return reservations
+			},
+			expectedLength: 6,
+		},
+		{
+			name: "code of an existing link is skipped",
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(true, nil).Once()
+				storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil).Once()
+				return storage
+			},
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(true, nil).Twice()
+				return reservations
+			},
+			expectedLength: 6,
+		},
+		{
+			name: "storage error is returned",
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, assert.AnError).Once()
+				return storage
+			},
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(true, nil).Once()
+				return reservations
+			},
+			expectedError: assert.AnError,
+		},
+		{
+			name:         "reservation error is returned",
+			setupStorage: newUnusedUrlStorage,
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(false, assert.AnError).Once()
+				return reservations
+			},
+			expectedError: assert.AnError,
+		},
+		{
+			name:         "every attempt collides",
+			setupStorage: newUnusedUrlStorage,
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(false, nil).Times(defaultThreshold)
+				return reservations
+			},
+			expectedError: e.ErrKeyAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			codes, err := NewCodeGenerator(CodeGeneratorConfig{Length: 6}, tc.setupStorage(t), tc.setupReservations(t))
+			require.NoError(t, err)
+
+			code, err := codes.Generate(t.Context())
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Len(t, code, tc.expectedLength)
+		})
+	}
+}
+
+func TestCodeGenerator_RandomGrowsOnCollisions(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil)
+	reservations := mocks.NewCodeReservation(t)
+	// Every other code is already reserved: a 50% collision rate
+	collide := false
+	reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(func(context.Context, string, time.Duration) (bool, error) {
+		collide = !collide
+		return !collide, nil
+	}).Maybe()
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{Length: 6, MaxLength: 7}, storage, reservations)
+	require.NoError(t, err)
+
+	var code string
+	for i := 0; i < collisionWindow; i++ {
+		code, err = codes.Generate(t.Context())
+		require.NoError(t, err)
+	}
+	assert.Len(t, code, 7)
+
+	// The maximum length is never exceeded
+	for i := 0; i < collisionWindow; i++ {
+		code, err = codes.Generate(t.Context())
+		require.NoError(t, err)
+	}
+	assert.Len(t, code, 7)
+}
+
+func TestCodeGenerator_RandomGrowsWhenExhausted(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil).Once()
+	reservations := mocks.NewCodeReservation(t)
+	reservations.On("Reserve", mock.Anything, mock.MatchedBy(func(code string) bool { return len(code) == 6 }), codeReservationTTL).
+		Return(false, nil).Times(defaultThreshold)
+	reservations.On("Reserve", mock.Anything, mock.MatchedBy(func(code string) bool { return len(code) == 7 }), codeReservationTTL).
+		Return(true, nil).Once()
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{Length: 6}, storage, reservations)
+	require.NoError(t, err)
+
+	_, err = codes.Generate(t.Context())
+	assert.ErrorIs(t, err, e.ErrKeyAlreadyExists)
+
+	code, err := codes.Generate(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, code, 7)
+}
+
+func TestCodeGenerator_RandomMany(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	reservations := mocks.NewCodeReservation(t)
+	// The second code of the first round is reserved elsewhere and the third is used by a link;
+	// the second round draws replacements for both
+	reservations.On("ReserveMany", mock.Anything, mock.MatchedBy(func(codes []string) bool { return len(codes) == 3 }), codeReservationTTL).
+		Return([]bool{true, false, true}, nil).Once()
+	storage.On("CheckKeysExist", mock.Anything, mock.MatchedBy(func(codes []string) bool { return len(codes) == 2 })).
+		Return(func(_ context.Context, codes []string) (map[string]bool, error) {
+			return map[string]bool{codes[1]: true}, nil
+		}).Once()
+	reservations.On("ReserveMany", mock.Anything, mock.MatchedBy(func(codes []string) bool { return len(codes) == 2 }), codeReservationTTL).
+		Return([]bool{true, true}, nil).Once()
+	storage.On("CheckKeysExist", mock.Anything, mock.Anything).Return(map[string]bool{}, nil).Once()
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{Length: 6}, storage, reservations)
+	require.NoError(t, err)
+
+	res, err := codes.GenerateMany(t.Context(), 3)
+
+	require.NoError(t, err)
+	assert.Len(t, res, 3)
+	for _, code := range res {
+		assert.Len(t, code, 6)
+	}
+}
+
+func TestCodeRegistry_ReserveManyCaseVariants(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	reservations := mocks.NewCodeReservation(t)
+	// Both codes map to the same reservation, so only the first one is claimed
+	reservations.On("ReserveMany", mock.Anything, []string{"ab12cd", "ab12cd", "ef34gh"}, codeReservationTTL).
+		Return([]bool{true, false, true}, nil).Once()
+	storage.On("CheckKeysExist", mock.Anything, []string{"aB12cd", "EF34gh"}).Return(map[string]bool{"EF34gh": true}, nil).Once()
+
+	codes := codeRegistry{repo: storage, reservations: reservations}
+	free, err := codes.reserveMany(t.Context(), []string{"aB12cd", "Ab12CD", "EF34gh"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aB12cd"}, free)
+}
+
+func TestCodeGenerator_RandomManyErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		setupMocks   func(storage *mocks.UrlStorage, reservations *mocks.CodeReservation)
+		expectErr    error
+		expectLength int
+	}{
+		{
+			name: "reservation error",
+			setupMocks: func(storage *mocks.UrlStorage, reservations *mocks.CodeReservation) {
+				reservations.On("ReserveMany", mock.Anything, mock.Anything, codeReservationTTL).Return(nil, assert.AnError).Once()
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "storage error",
+			setupMocks: func(storage *mocks.UrlStorage, reservations *mocks.CodeReservation) {
+				reservations.On("ReserveMany", mock.Anything, mock.Anything, codeReservationTTL).Return([]bool{true, true}, nil).Once()
+				storage.On("CheckKeysExist", mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
+			},
+			expectErr: assert.AnError,
+		},
+		{
+			name: "every round collides",
+			setupMocks: func(storage *mocks.UrlStorage, reservations *mocks.CodeReservation) {
+				reservations.On("ReserveMany", mock.Anything, mock.Anything, codeReservationTTL).
+					Return([]bool{false, false}, nil).Times(defaultThreshold)
+			},
+			expectErr: e.ErrKeyAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := mocks.NewUrlStorage(t)
+			reservations := mocks.NewCodeReservation(t)
+			tc.setupMocks(storage, reservations)
+			codes, err := NewCodeGenerator(CodeGeneratorConfig{Length: 6}, storage, reservations)
+			require.NoError(t, err)
+
+			_, err = codes.GenerateMany(t.Context(), 2)
+
+			assert.ErrorIs(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestCodeGenerator_Counter(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	// An alias already took the code of the second counter value
+	storage.On("CheckKeyExists", mock.Anything, "aaab").Return(false, nil).Once()
+	storage.On("CheckKeyExists", mock.Anything, "aaac").Return(true, nil).Once()
+	storage.On("CheckKeyExists", mock.Anything, "aaad").Return(false, nil).Once()
+	reservations := mocks.NewCodeReservation(t)
+	reservations.On("NextSequence", mock.Anything).Return(int64(1), nil).Once()
+	reservations.On("NextSequence", mock.Anything).Return(int64(2), nil).Once()
+	reservations.On("NextSequence", mock.Anything).Return(int64(3), nil).Once()
+	reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(true, nil).Times(3)
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{Strategy: CodeStrategyCounter, Length: 4}, storage, reservations)
+	require.NoError(t, err)
+
+	code, err := codes.Generate(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "aaab", code)
+
+	code, err = codes.Generate(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "aaad", code)
+}
+
+func TestCodeGenerator_CounterMany(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	reservations := mocks.NewCodeReservation(t)
+	// An alias already took the code of the second counter value, so a second block of one is claimed
+	reservations.On("NextSequences", mock.Anything, int64(3)).Return(int64(1), nil).Once()
+	reservations.On("ReserveMany", mock.Anything, []string{"aaab", "aaac", "aaad"}, codeReservationTTL).
+		Return([]bool{true, true, true}, nil).Once()
+	storage.On("CheckKeysExist", mock.Anything, []string{"aaab", "aaac", "aaad"}).Return(map[string]bool{"aaac": true}, nil).Once()
+	reservations.On("NextSequences", mock.Anything, int64(1)).Return(int64(4), nil).Once()
+	reservations.On("ReserveMany", mock.Anything, []string{"aaae"}, codeReservationTTL).Return([]bool{true}, nil).Once()
+	storage.On("CheckKeysExist", mock.Anything, []string{"aaae"}).Return(map[string]bool{}, nil).Once()
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{Strategy: CodeStrategyCounter, Length: 4}, storage, reservations)
+	require.NoError(t, err)
+
+	res, err := codes.GenerateMany(t.Context(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aaab", "aaad", "aaae"}, res)
+}
+
+func TestCodeGenerator_CounterError(t *testing.T) {
+	t.Parallel()
+
+	reservations := mocks.NewCodeReservation(t)
+	reservations.On("NextSequence", mock.Anything).Return(int64(0), assert.AnError).Once()
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{Strategy: CodeStrategyCounter}, mocks.NewUrlStorage(t), reservations)
+	require.NoError(t, err)
+
+	_, err = codes.Generate(t.Context())
+	assert.ErrorIs(t, err, assert.AnError)
+
+	reservations.On("NextSequences", mock.Anything, int64(2)).Return(int64(0), assert.AnError).Once()
+	_, err = codes.GenerateMany(t.Context(), 2)
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestCodeGenerator_Hashids(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewUrlStorage(t)
+	storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil)
+	reservations := mocks.NewCodeReservation(t)
+	reservations.On("NextSequence", mock.Anything).Return(int64(1), nil).Once()
+	reservations.On("NextSequence", mock.Anything).Return(int64(2), nil).Once()
+	reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(true, nil)
+
+	cfg := CodeGeneratorConfig{Strategy: CodeStrategyHashids, Length: 6, Salt: "pepper"}
+	codes, err := NewCodeGenerator(cfg, storage, reservations)
+	require.NoError(t, err)
+
+	first, err := codes.Generate(t.Context())
+	require.NoError(t, err)
+	second, err := codes.Generate(t.Context())
+	require.NoError(t, err)
+
+	assert.Len(t, first, 6)
+	assert.Len(t, second, 6)
+	assert.Equal(t, shortcode.NewObfuscator(shortcode.LowerAlphabet, "pepper").Encode(1, 6), first)
+	// Consecutive codes do not look consecutive
+	assert.NotEqual(t, first[:5], second[:5])
+}
+
+func TestCodeGenerator_Reserve(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		setupStorage      func(t *testing.T) *mocks.UrlStorage
+		setupReservations func(t *testing.T) *mocks.CodeReservation
+		expected          bool
+		expectedError     error
+	}{
+		{
+			name: "free alias",
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, "My-Launch").Return(false, nil).Once()
+				return storage
+			},
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				// Aliases are reserved regardless of case
+				reservations.On("Reserve", mock.Anything, "my-launch", codeReservationTTL).Return(true, nil).Once()
+				return reservations
+			},
+			expected: true,
+		},
+		{
+			name:         "alias requested concurrently",
+			setupStorage: newUnusedUrlStorage,
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, "my-launch", codeReservationTTL).Return(false, nil).Once()
+				return reservations
+			},
+		},
+		{
+			name: "alias of an existing link",
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, "My-Launch").Return(true, nil).Once()
+				return storage
+			},
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, "my-launch", codeReservationTTL).Return(true, nil).Once()
+				return reservations
+			},
+		},
+		{
+			name:         "reservation error",
+			setupStorage: newUnusedUrlStorage,
+			setupReservations: func(t *testing.T) *mocks.CodeReservation {
+				reservations := mocks.NewCodeReservation(t)
+				reservations.On("Reserve", mock.Anything, "my-launch", codeReservationTTL).Return(false, assert.AnError).Once()
+				return reservations
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			codes, err := NewCodeGenerator(CodeGeneratorConfig{}, tc.setupStorage(t), tc.setupReservations(t))
+			require.NoError(t, err)
+
+			reserved, err := codes.Reserve(t.Context(), "My-Launch")
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Equal(t, tc.expected, reserved)
+		})
+	}
+}
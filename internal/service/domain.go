@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/dnsverify"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// domainTokenLength is the length of the token a domain publishes to prove its ownership.
+const domainTokenLength = 32
+
+//go:generate mockery --name=Domain --filename=domain.go
+
+// Domain defines the interface for custom domain services.
+// Users register a domain, publish its token in a DNS TXT record and verify it;
+// verified domains then serve the user's links under their own host.
+type Domain interface {
+	// Register registers a domain for the user and returns the TXT record proving its ownership.
+	// Unverified claims of other users do not block the domain; the first user to verify it
+	// takes it over. Returns ErrDomainReserved for the hosts of this service and ErrDomainTaken
+	// if the domain is verified or the user already registered it.
+	Register(ctx context.Context, userId string, r dto.DomainRegisterRequestDto) (dto.DomainDto, error)
+	// ListDomains returns the user's domains in registration order.
+	ListDomains(ctx context.Context, userId string) (dto.DomainListResponseDto, error)
+	// Verify looks up the TXT record of the user's domain and marks the domain verified if it
+	// carries the token, dropping the claims of other users. Returns ErrDomainNotFound if the
+	// domain is not the user's, ErrDomainTaken if another user verified it first and
+	// ErrDomainNotVerified if the record is missing or carries another value.
+	Verify(ctx context.Context, userId, name string) (dto.DomainDto, error)
+	// DeleteDomain removes the user's domain together with the user's links on it.
+	// Returns ErrDomainNotFound if the domain is not the user's.
+	DeleteDomain(ctx context.Context, userId, name string) error
+	// CheckUsable returns nil if the user may create links on the domain, ErrDomainNotFound
+	// if the domain is not the user's and ErrDomainNotVerified if it is not verified yet.
+	CheckUsable(ctx context.Context, userId, name string) error
+	// ResolveHost returns the verified custom domain serving the host of a request.
+	// It returns an empty string for the hosts of this service and for unknown or
+	// unverified hosts, whose requests are served as on the shared host.
+	ResolveHost(ctx context.Context, host string) (string, error)
+}
+
+type domainService struct {
+	repo      repository.Domain
+	links     LinkKeys
+	resolver  dnsverify.Resolver
+	selfHosts []string
+}
+
+// NewDomain creates and returns a new custom domain service instance.
+// selfHosts are the hosts of this service, which cannot be registered and
+// never need a domain lookup; ports are ignored. The links of deleted domains are
+// removed from the Redis stores in links.
+func NewDomain(repo repository.Domain, links LinkKeys, resolver dnsverify.Resolver, selfHosts []string) Domain {
+	hosts := make([]string, 0, len(selfHosts))
+	for _, h := range selfHosts {
+		if h = normalizeHost(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+
+	return &domainService{
+		repo:      repo,
+		links:     links,
+		resolver:  resolver,
+		selfHosts: hosts,
+	}
+}
+
+// Register registers a domain for the user.
+func (s *domainService) Register(ctx context.Context, userId string, r dto.DomainRegisterRequestDto) (dto.DomainDto, error) {
+	name := normalizeHost(r.Name)
+	if s.isSelfHost(name) {
+		return dto.DomainDto{}, e.ErrDomainReserved
+	}
+
+	if err := s.checkUnverified(ctx, name); err != nil {
+		return dto.DomainDto{}, err
+	}
+	if _, err := s.repo.GetOwnedDomain(ctx, name, userId); err == nil {
+		return dto.DomainDto{}, e.ErrDomainTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.DomainDto{}, err
+	}
+
+	token, err := utils.GenerateRandomString(domainTokenLength)
+	if err != nil {
+		return dto.DomainDto{}, err
+	}
+	d := &model.Domain{
+		Name:      name,
+		OwnerId:   userId,
+		Token:     token,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.CreateDomain(ctx, d); err != nil {
+		return dto.DomainDto{}, err
+	}
+
+	return toDomainDto(d), nil
+}
+
+// ListDomains returns the user's domains in registration order.
+func (s *domainService) ListDomains(ctx context.Context, userId string) (dto.DomainListResponseDto, error) {
+	domains, err := s.repo.ListOwnedDomains(ctx, userId)
+	if err != nil {
+		return dto.DomainListResponseDto{}, err
+	}
+
+	items := make([]dto.DomainDto, 0, len(domains))
+	for i := range domains {
+		items = append(items, toDomainDto(&domains[i]))
+	}
+
+	return dto.DomainListResponseDto{Items: items}, nil
+}
+
+// Verify marks the user's domain verified once its TXT record carries the token.
+func (s *domainService) Verify(ctx context.Context, userId, name string) (dto.DomainDto, error) {
+	d, err := s.ownedDomain(ctx, userId, name)
+	if err != nil {
+		return dto.DomainDto{}, err
+	}
+	if d.VerifiedAt != nil {
+		return toDomainDto(d), nil
+	}
+	if err := s.checkUnverified(ctx, d.Name); err != nil {
+		return dto.DomainDto{}, err
+	}
+
+	ok, err := dnsverify.HasToken(ctx, s.resolver, d.Name, d.Token)
+	if err != nil {
+		return dto.DomainDto{}, err
+	}
+	if !ok {
+		return dto.DomainDto{}, e.ErrDomainNotVerified
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.MarkVerified(ctx, d.Name, userId, now); err != nil {
+		return dto.DomainDto{}, mapDomainNotFound(err)
+	}
+	d.VerifiedAt = &now
+
+	return toDomainDto(d), nil
+}
+
+// DeleteDomain removes the user's domain and its links.
+func (s *domainService) DeleteDomain(ctx context.Context, userId, name string) error {
+	codes, err := s.repo.DeleteOwnedDomain(ctx, normalizeHost(name), userId)
+	if err != nil {
+		return mapDomainNotFound(err)
+	}
+	s.links.forget(ctx, codes...)
+
+	return nil
+}
+
+// CheckUsable returns nil if the user owns the verified domain.
+func (s *domainService) CheckUsable(ctx context.Context, userId, name string) error {
+	d, err := s.ownedDomain(ctx, userId, name)
+	if err != nil {
+		return err
+	}
+	if d.VerifiedAt == nil {
+		return e.ErrDomainNotVerified
+	}
+
+	return nil
+}
+
+// ResolveHost returns the verified custom domain serving the host.
+func (s *domainService) ResolveHost(ctx context.Context, host string) (string, error) {
+	name := normalizeHost(host)
+	if name == "" || s.isSelfHost(name) {
+		return "", nil
+	}
+
+	d, err := s.repo.GetVerifiedDomain(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return d.Name, nil
+}
+
+// ownedDomain retrieves the user's claim on the domain, ErrDomainNotFound if there is none.
+func (s *domainService) ownedDomain(ctx context.Context, userId, name string) (*model.Domain, error) {
+	d, err := s.repo.GetOwnedDomain(ctx, normalizeHost(name), userId)
+	if err != nil {
+		return nil, mapDomainNotFound(err)
+	}
+
+	return d, nil
+}
+
+// checkUnverified returns ErrDomainTaken if a user already verified the domain.
+func (s *domainService) checkUnverified(ctx context.Context, name string) error {
+	_, err := s.repo.GetVerifiedDomain(ctx, name)
+	if err == nil {
+		return e.ErrDomainTaken
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *domainService) isSelfHost(name string) bool {
+	return slices.Contains(s.selfHosts, name)
+}
+
+// ShortUrl returns the public URL of the link stored under the key: the base URL followed
+// by the code on the shared host, the root of the custom domain otherwise.
+func ShortUrl(baseUrl, key string) string {
+	code, domain := dto.SplitLinkKey(key)
+	if domain == "" {
+		return baseUrl + code
+	}
+
+	return "https://" + domain + "/" + code
+}
+
+// normalizeHost lower-cases the host and strips its port and trailing dot.
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return dto.NormalizeDomain(host)
+}
+
+func mapDomainNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.ErrDomainNotFound
+	}
+
+	return err
+}
+
+func toDomainDto(d *model.Domain) dto.DomainDto {
+	res := dto.DomainDto{
+		Name:           d.Name,
+		Verified:       d.VerifiedAt != nil,
+		CreatedAt:      d.CreatedAt.UTC().Format(time.RFC3339),
+		TxtRecordName:  dnsverify.RecordName(d.Name),
+		TxtRecordValue: dnsverify.RecordValue(d.Token),
+	}
+	if d.VerifiedAt != nil {
+		verifiedAt := d.VerifiedAt.UTC().Format(time.RFC3339)
+		res.VerifiedAt = &verifiedAt
+	}
+
+	return res
+}
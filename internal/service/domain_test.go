@@ -0,0 +1,342 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	dnsMocks "github.com/vincent-tien/bookmark-management/pkg/dnsverify/mocks"
+	"gorm.io/gorm"
+)
+
+// testSelfHosts are the hosts of the service in domain tests.
+var testSelfHosts = []string{"localhost:8080", "sho.rt"}
+
+func newTestDomain(verified bool) *model.Domain {
+	d := &model.Domain{Name: "go.example.com", OwnerId: testOwnerID, Token: "abc123", CreatedAt: time.Now().UTC()}
+	if verified {
+		verifiedAt := time.Now().UTC()
+		d.VerifiedAt = &verifiedAt
+	}
+
+	return d
+}
+
+func TestDomain_Register(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		request       dto.DomainRegisterRequestDto
+		setupRepo     func(t *testing.T) *mocks.Domain
+		expectedError error
+	}{
+		{
+			name:    "new domain",
+			request: dto.DomainRegisterRequestDto{Name: "Go.Example.com."},
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(nil, gorm.ErrRecordNotFound)
+				// Unverified claims of other users do not block the domain
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(nil, gorm.ErrRecordNotFound)
+				repo.On("CreateDomain", mock.Anything, mock.MatchedBy(func(d *model.Domain) bool {
+					return d.Name == "go.example.com" && d.OwnerId == testOwnerID && len(d.Token) == domainTokenLength
+				})).Return(nil)
+				return repo
+			},
+		},
+		{
+			name:    "domain verified by another user",
+			request: dto.DomainRegisterRequestDto{Name: "go.example.com"},
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				d := newTestDomain(true)
+				d.OwnerId = "00000000-0000-0000-0000-000000000000"
+				repo := mocks.NewDomain(t)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(d, nil)
+				return repo
+			},
+			expectedError: e.ErrDomainTaken,
+		},
+		{
+			name:    "domain registered by the user",
+			request: dto.DomainRegisterRequestDto{Name: "go.example.com"},
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(nil, gorm.ErrRecordNotFound)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(newTestDomain(false), nil)
+				return repo
+			},
+			expectedError: e.ErrDomainTaken,
+		},
+		{
+			name:    "host of the service",
+			request: dto.DomainRegisterRequestDto{Name: "SHO.RT"},
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				return mocks.NewDomain(t)
+			},
+			expectedError: e.ErrDomainReserved,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewDomain(tc.setupRepo(t), newLinkKeys(t, mocks.NewLinkCache(t), mocks.NewClickLimit(t)), dnsMocks.NewResolver(t), testSelfHosts)
+
+			res, err := svc.Register(t.Context(), testOwnerID, tc.request)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "go.example.com", res.Name)
+			assert.False(t, res.Verified)
+			assert.Equal(t, "_bookmark-verify.go.example.com", res.TxtRecordName)
+			assert.Len(t, res.TxtRecordValue, len("bookmark-verify=")+domainTokenLength)
+		})
+	}
+}
+
+func TestDomain_Verify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		setupRepo     func(t *testing.T) *mocks.Domain
+		setupResolver func(t *testing.T) *dnsMocks.Resolver
+		expectedError error
+	}{
+		{
+			name: "token published",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(newTestDomain(false), nil)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(nil, gorm.ErrRecordNotFound)
+				repo.On("MarkVerified", mock.Anything, "go.example.com", testOwnerID, mock.Anything).Return(nil)
+				return repo
+			},
+			setupResolver: func(t *testing.T) *dnsMocks.Resolver {
+				resolver := dnsMocks.NewResolver(t)
+				resolver.On("LookupTXT", mock.Anything, "_bookmark-verify.go.example.com").
+					Return([]string{"bookmark-verify=abc123"}, nil)
+				return resolver
+			},
+		},
+		{
+			name: "token missing",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(newTestDomain(false), nil)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(nil, gorm.ErrRecordNotFound)
+				return repo
+			},
+			setupResolver: func(t *testing.T) *dnsMocks.Resolver {
+				resolver := dnsMocks.NewResolver(t)
+				resolver.On("LookupTXT", mock.Anything, "_bookmark-verify.go.example.com").
+					Return([]string{"bookmark-verify=other"}, nil)
+				return resolver
+			},
+			expectedError: e.ErrDomainNotVerified,
+		},
+		{
+			name: "already verified",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(newTestDomain(true), nil)
+				return repo
+			},
+			setupResolver: func(t *testing.T) *dnsMocks.Resolver {
+				return dnsMocks.NewResolver(t)
+			},
+		},
+		{
+			name: "domain of another user",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(nil, gorm.ErrRecordNotFound)
+				return repo
+			},
+			setupResolver: func(t *testing.T) *dnsMocks.Resolver {
+				return dnsMocks.NewResolver(t)
+			},
+			expectedError: e.ErrDomainNotFound,
+		},
+		{
+			name: "verified by another user first",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				d := newTestDomain(true)
+				d.OwnerId = "00000000-0000-0000-0000-000000000000"
+				repo := mocks.NewDomain(t)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(newTestDomain(false), nil)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(d, nil)
+				return repo
+			},
+			setupResolver: func(t *testing.T) *dnsMocks.Resolver {
+				return dnsMocks.NewResolver(t)
+			},
+			expectedError: e.ErrDomainTaken,
+		},
+		{
+			name: "lookup fails",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(newTestDomain(false), nil)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(nil, gorm.ErrRecordNotFound)
+				return repo
+			},
+			setupResolver: func(t *testing.T) *dnsMocks.Resolver {
+				resolver := dnsMocks.NewResolver(t)
+				resolver.On("LookupTXT", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+				return resolver
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewDomain(tc.setupRepo(t), newLinkKeys(t, mocks.NewLinkCache(t), mocks.NewClickLimit(t)), tc.setupResolver(t), testSelfHosts)
+
+			res, err := svc.Verify(t.Context(), testOwnerID, "go.example.com")
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, res.Verified)
+			assert.NotNil(t, res.VerifiedAt)
+		})
+	}
+}
+
+func TestDomain_DeleteDomain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		codes         []string
+		repoErr       error
+		expectedError error
+	}{
+		{name: "domain with links", codes: []string{"abc@go.example.com", "def@go.example.com"}},
+		{name: "domain without links"},
+		{name: "unknown domain", repoErr: gorm.ErrRecordNotFound, expectedError: e.ErrDomainNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mocks.NewDomain(t)
+			repo.On("DeleteOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(tc.codes, tc.repoErr)
+			svc := NewDomain(repo, newForgettingLinkKeys(t, tc.codes...), dnsMocks.NewResolver(t), testSelfHosts)
+
+			assert.ErrorIs(t, svc.DeleteDomain(t.Context(), testOwnerID, "Go.Example.com"), tc.expectedError)
+		})
+	}
+}
+
+func TestDomain_CheckUsable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		domain        *model.Domain
+		repoErr       error
+		expectedError error
+	}{
+		{name: "verified domain", domain: newTestDomain(true)},
+		{name: "unverified domain", domain: newTestDomain(false), expectedError: e.ErrDomainNotVerified},
+		{name: "unknown domain", repoErr: gorm.ErrRecordNotFound, expectedError: e.ErrDomainNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mocks.NewDomain(t)
+			repo.On("GetOwnedDomain", mock.Anything, "go.example.com", testOwnerID).Return(tc.domain, tc.repoErr)
+			svc := NewDomain(repo, newLinkKeys(t, mocks.NewLinkCache(t), mocks.NewClickLimit(t)), dnsMocks.NewResolver(t), testSelfHosts)
+
+			assert.ErrorIs(t, svc.CheckUsable(t.Context(), testOwnerID, "go.example.com"), tc.expectedError)
+		})
+	}
+}
+
+func TestDomain_ResolveHost(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		host      string
+		setupRepo func(t *testing.T) *mocks.Domain
+		expected  string
+	}{
+		{
+			name: "verified domain with port",
+			host: "GO.example.com:443",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(newTestDomain(true), nil)
+				return repo
+			},
+			expected: "go.example.com",
+		},
+		{
+			name: "unverified domain",
+			host: "go.example.com",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetVerifiedDomain", mock.Anything, "go.example.com").Return(nil, gorm.ErrRecordNotFound)
+				return repo
+			},
+		},
+		{
+			name: "unknown host",
+			host: "other.example.com",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				repo := mocks.NewDomain(t)
+				repo.On("GetVerifiedDomain", mock.Anything, "other.example.com").Return(nil, gorm.ErrRecordNotFound)
+				return repo
+			},
+		},
+		{
+			name: "host of the service is not looked up",
+			host: "localhost:8080",
+			setupRepo: func(t *testing.T) *mocks.Domain {
+				return mocks.NewDomain(t)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewDomain(tc.setupRepo(t), newLinkKeys(t, mocks.NewLinkCache(t), mocks.NewClickLimit(t)), dnsMocks.NewResolver(t), testSelfHosts)
+
+			domain, err := svc.ResolveHost(t.Context(), tc.host)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, domain)
+		})
+	}
+}
+
+func TestShortUrl(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://sho.rt/abc", ShortUrl("https://sho.rt/", "abc"))
+	assert.Equal(t, "https://go.example.com/abc", ShortUrl("https://sho.rt/", "abc@go.example.com"))
+}
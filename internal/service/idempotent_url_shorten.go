@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+)
+
+type idempotentUrlShorten struct {
+	svc       UrlShorten
+	repo      repository.UrlStorage
+	index     repository.TargetIndex
+	anonymous bool
+}
+
+// NewIdempotentUrlShorten wraps the URL shortening service so that shortening the same
+// target with the same options again returns the existing active code instead of a new one.
+// Links of signed-in users are deduplicated per owner; anonymous links only when anonymous
+// is set, and then across all anonymous callers. Aliases, click-limited, password-protected
+// routed links, links with UTM parameters and links on custom domains always get their own code.
+func NewIdempotentUrlShorten(svc UrlShorten, repo repository.UrlStorage, index repository.TargetIndex, anonymous bool) UrlShorten {
+	return &idempotentUrlShorten{
+		svc:       svc,
+		repo:      repo,
+		index:     index,
+		anonymous: anonymous,
+	}
+}
+
+// Shorten returns the code of an earlier identical request while its link is active,
+// and otherwise shortens the URL and indexes the new code.
+// A link is reused during the first half of its lifetime only, so callers always get
+// at least half of the requested lifetime.
+func (s *idempotentUrlShorten) Shorten(ctx context.Context, r dto.LinkShortenRequestDto) (string, error) {
+	fingerprint, ok := s.fingerprint(r)
+	if !ok {
+		return s.svc.Shorten(ctx, r)
+	}
+
+	if code, ok := s.lookup(ctx, fingerprint, r); ok {
+		return code, nil
+	}
+
+	code, err := s.svc.Shorten(ctx, r)
+	if err != nil {
+		return "", err
+	}
+
+	var ttl time.Duration
+	if r.ExpInSeconds > 0 {
+		ttl = time.Second * time.Duration(r.ExpInSeconds) / 2
+	}
+	if err := s.index.Set(ctx, fingerprint, code, ttl); err != nil {
+		log.Warn().Err(err).Str("code", code).Msg("Failed to index shortened target")
+	}
+
+	return code, nil
+}
+
+// ShortenMany shortens bulk requests without deduplication; looking every request up
+// in the index would cost the round trip per request that bulk shortening avoids.
+func (s *idempotentUrlShorten) ShortenMany(ctx context.Context, rs []dto.LinkShortenRequestDto) ([]ShortenResult, error) {
+	return s.svc.ShortenMany(ctx, rs)
+}
+
+// GetUrl retrieves the destination of the link the visitor follows.
+func (s *idempotentUrlShorten) GetUrl(ctx context.Context, r dto.LinkRedirectRequestDto) (Destination, error) {
+	return s.svc.GetUrl(ctx, r)
+}
+
+// lookup returns the indexed code if its link is still active, still belongs to the caller
+// and still matches the request.
+// Index failures are logged only; the request then gets a new code.
+func (s *idempotentUrlShorten) lookup(ctx context.Context, fingerprint string, r dto.LinkShortenRequestDto) (string, bool) {
+	code, found, err := s.index.Get(ctx, fingerprint)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read target index")
+		return "", false
+	}
+	if !found {
+		return "", false
+	}
+
+	// The link may have been deleted, disabled or pointed elsewhere since it was indexed,
+	// and its code freed and reused by someone else
+	link, err := s.repo.GetLink(ctx, code)
+	if err != nil {
+		return "", false
+	}
+	var owner string
+	if link.OwnerId != nil {
+		owner = *link.OwnerId
+	}
+	if owner != r.OwnerId {
+		return "", false
+	}
+	target, err := canonicalUrl(link.Target)
+	if err != nil {
+		return "", false
+	}
+	requested, _ := canonicalUrl(r.Url)
+	if target != requested || link.Interstitial != r.Interstitial || link.PasswordHash != "" || link.MaxClicks != nil ||
+		!link.Routing.IsEmpty() || !link.Utm.IsEmpty() || link.RedirectStatus != r.RedirectStatus ||
+		link.NoIndex != r.NoIndex || link.ReferrerPolicy != r.ReferrerPolicy || link.NotBefore != nil {
+		return "", false
+	}
+
+	return code, true
+}
+
+// fingerprint identifies the caller, the canonical target and the options of the request.
+// It returns false for requests that must not be deduplicated.
+func (s *idempotentUrlShorten) fingerprint(r dto.LinkShortenRequestDto) (string, bool) {
+	if r.Alias != "" || r.MaxClicks > 0 || r.Password != "" || r.Routing.ToRouting() != nil ||
+		r.Utm.ToParams() != nil || r.Domain != "" || r.NotBefore != nil || r.NotAfter != nil {
+		return "", false
+	}
+
+	scope := "anonymous"
+	if r.OwnerId != "" {
+		scope = "user:" + r.OwnerId
+	} else if !s.anonymous {
+		return "", false
+	}
+
+	target, err := canonicalUrl(r.Url)
+	if err != nil {
+		return "", false
+	}
+
+	return strings.Join([]string{
+		scope,
+		target,
+		strconv.Itoa(r.ExpInSeconds),
+		strconv.FormatBool(r.Interstitial),
+		strconv.Itoa(r.RedirectStatus),
+		strconv.FormatBool(r.NoIndex),
+		r.ReferrerPolicy,
+	}, "\n"), true
+}
+
+// canonicalUrl normalizes the parts of a URL that do not change the resource it points to:
+// the case of scheme and host, default ports, an empty path and the order of query parameters.
+func canonicalUrl(rawUrl string) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if u.Scheme == "http" && port == "80" || u.Scheme == "https" && port == "443" {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	if u.Path == "" && u.Host != "" {
+		u.Path = "/"
+	}
+	// Encode sorts the parameters by key and keeps the order of repeated keys
+	u.RawQuery = u.Query().Encode()
+
+	return u.String(), nil
+}
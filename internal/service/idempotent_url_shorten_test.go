@@ -0,0 +1,371 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	"gorm.io/gorm"
+)
+
+const userFingerprint = "user:" + testOwnerID + "\nhttps://example.com/docs?a=1&b=2\n3600\nfalse\n0\nfalse\n"
+
+// expectNewCode makes the storage accept one newly generated code
+func expectNewCode(storage *mocks.UrlStorage) {
+	storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil).Once()
+	storage.On("Store", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+}
+
+func TestIdempotentUrlShorten_Shorten(t *testing.T) {
+	t.Parallel()
+
+	maxClicks := int64(1)
+	owner, otherOwner := testOwnerID, "00000000-0000-0000-0000-000000000000"
+
+	testCases := []struct {
+		name         string
+		anonymous    bool
+		request      dto.LinkShortenRequestDto
+		setupStorage func(t *testing.T) *mocks.UrlStorage
+		setupIndex   func(t *testing.T) *mocks.TargetIndex
+		expectedCode string
+		expectNew    bool
+	}{
+		{
+			name:    "same target of the same user returns the existing code",
+			request: dto.LinkShortenRequestDto{Url: "HTTPS://Example.com:443/docs?b=2&a=1", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "abc123").
+					Return(&model.ShortLink{Code: "abc123", Target: "https://example.com/docs?a=1&b=2", OwnerId: &owner}, nil).Once()
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, userFingerprint).Return("abc123", true, nil).Once()
+				return index
+			},
+			expectedCode: "abc123",
+		},
+		{
+			name:    "first request is indexed for half of the link lifetime",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com/docs?a=1&b=2", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, userFingerprint).Return("", false, nil).Once()
+				index.On("Set", mock.Anything, userFingerprint, mock.Anything, 30*time.Minute).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:    "deleted link gets a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com/docs?a=1&b=2", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "abc123").Return(nil, gorm.ErrRecordNotFound).Once()
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, userFingerprint).Return("abc123", true, nil).Once()
+				index.On("Set", mock.Anything, userFingerprint, mock.Anything, 30*time.Minute).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:    "code reused by another owner gets a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com/docs?a=1&b=2", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "abc123").
+					Return(&model.ShortLink{Code: "abc123", Target: "https://example.com/docs?a=1&b=2", OwnerId: &otherOwner}, nil).Once()
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, userFingerprint).Return("abc123", true, nil).Once()
+				index.On("Set", mock.Anything, userFingerprint, mock.Anything, 30*time.Minute).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:      "owned link is not handed out to anonymous callers",
+			anonymous: true,
+			request:   dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "anon12").
+					Return(&model.ShortLink{Code: "anon12", Target: "https://example.com", OwnerId: &owner}, nil).Once()
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, mock.Anything).Return("anon12", true, nil).Once()
+				index.On("Set", mock.Anything, mock.Anything, mock.Anything, 30*time.Minute).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:    "link pointed elsewhere gets a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com/docs?a=1&b=2", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "abc123").
+					Return(&model.ShortLink{Code: "abc123", Target: "https://example.com/other", OwnerId: &owner}, nil).Once()
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, userFingerprint).Return("abc123", true, nil).Once()
+				index.On("Set", mock.Anything, userFingerprint, mock.Anything, 30*time.Minute).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:    "link with other redirect options gets a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com/docs?a=1&b=2", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "abc123").
+					Return(&model.ShortLink{Code: "abc123", Target: "https://example.com/docs?a=1&b=2", OwnerId: &owner,
+						RedirectStatus: http.StatusMovedPermanently}, nil).Once()
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, userFingerprint).Return("abc123", true, nil).Once()
+				index.On("Set", mock.Anything, userFingerprint, mock.Anything, 30*time.Minute).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:    "index failures fall back to a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com/docs?a=1&b=2", ExpInSeconds: 3600, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, mock.Anything).Return("", false, assert.AnError).Once()
+				index.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:    "link without expiry is indexed without expiry",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: -1, OwnerId: testOwnerID},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, mock.Anything).Return("", false, nil).Once()
+				index.On("Set", mock.Anything, mock.Anything, mock.Anything, time.Duration(0)).Return(nil).Once()
+				return index
+			},
+			expectNew: true,
+		},
+		{
+			name:      "anonymous links are deduplicated when enabled",
+			anonymous: true,
+			request:   dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("GetLink", mock.Anything, "anon12").
+					Return(&model.ShortLink{Code: "anon12", Target: "https://example.com"}, nil).Once()
+				return storage
+			},
+			setupIndex: func(t *testing.T) *mocks.TargetIndex {
+				index := mocks.NewTargetIndex(t)
+				index.On("Get", mock.Anything, "anonymous\nhttps://example.com/\n3600\nfalse\n0\nfalse\n").Return("anon12", true, nil).Once()
+				return index
+			},
+			expectedCode: "anon12",
+		},
+		{
+			name:    "anonymous links are not deduplicated by default",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: newUnusedTargetIndex,
+			expectNew:  true,
+		},
+		{
+			name:    "one-time links always get a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600, OwnerId: testOwnerID, MaxClicks: maxClicks},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: newUnusedTargetIndex,
+			expectNew:  true,
+		},
+		{
+			name:    "protected links always get a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600, OwnerId: testOwnerID, Password: "open-sesame"},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: newUnusedTargetIndex,
+			expectNew:  true,
+		},
+		{
+			name: "routed links always get a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600, OwnerId: testOwnerID, Routing: &dto.LinkRoutingDto{
+				Variants: []dto.LinkVariantDto{{Name: "b", Url: "https://example.com/b", Weight: 1}},
+			}},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: newUnusedTargetIndex,
+			expectNew:  true,
+		},
+		{
+			name:    "links with utm parameters always get a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600, OwnerId: testOwnerID, Utm: &dto.LinkUtmDto{Source: "newsletter"}},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				expectNewCode(storage)
+				return storage
+			},
+			setupIndex: newUnusedTargetIndex,
+			expectNew:  true,
+		},
+		{
+			name:    "links on custom domains always get a new code",
+			request: dto.LinkShortenRequestDto{Url: "https://example.com", ExpInSeconds: 3600, OwnerId: testOwnerID, Domain: "go.example.com"},
+			setupStorage: func(t *testing.T) *mocks.UrlStorage {
+				storage := mocks.NewUrlStorage(t)
+				storage.On("CheckKeyExists", mock.Anything, mock.Anything).Return(false, nil)
+				storage.On("Store", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasSuffix(key, "@go.example.com")
+				}), mock.Anything).Return(nil)
+				return storage
+			},
+			setupIndex: newUnusedTargetIndex,
+			expectNew:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := tc.setupStorage(t)
+			limits := mocks.NewClickLimit(t)
+			limits.On("Reset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			inner := NewUrlShorten(storage, newTestCodes(t, storage), limits, mocks.NewRateLimiter(t), testPolicy)
+			svc := NewIdempotentUrlShorten(inner, storage, tc.setupIndex(t), tc.anonymous)
+
+			code, err := svc.Shorten(t.Context(), tc.request)
+
+			require.NoError(t, err)
+			if tc.expectNew {
+				assert.Len(t, code, defaultCodeLength)
+			} else {
+				assert.Equal(t, tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestIdempotentUrlShorten_ShortenError(t *testing.T) {
+	t.Parallel()
+
+	index := mocks.NewTargetIndex(t)
+	index.On("Get", mock.Anything, mock.Anything).Return("", false, nil).Once()
+	storage := mocks.NewUrlStorage(t)
+	inner := NewUrlShorten(storage, newTestCodes(t, storage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), testPolicy)
+	svc := NewIdempotentUrlShorten(inner, storage, index, false)
+
+	// Rejected destinations are never indexed
+	_, err := svc.Shorten(t.Context(), dto.LinkShortenRequestDto{Url: "http://10.0.0.1/", OwnerId: testOwnerID})
+
+	assert.Error(t, err)
+}
+
+func TestIdempotentUrlShorten_ShortenMany(t *testing.T) {
+	t.Parallel()
+
+	// Bulk requests skip the index
+	storage := mocks.NewUrlStorage(t)
+	storage.On("CheckKeysExist", mock.Anything, mock.Anything).Return(map[string]bool{}, nil).Once()
+	storage.On("StoreMany", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	inner := NewUrlShorten(storage, newTestCodes(t, storage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), testPolicy)
+	svc := NewIdempotentUrlShorten(inner, storage, newUnusedTargetIndex(t), true)
+
+	results, err := svc.ShortenMany(t.Context(), []dto.LinkShortenRequestDto{{Url: "https://example.com", OwnerId: testOwnerID}})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Len(t, results[0].Code, defaultCodeLength)
+}
+
+func TestCanonicalUrl(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "https://example.com", expected: "https://example.com/"},
+		{input: "HTTPS://EXAMPLE.com./Path", expected: "https://example.com/Path"},
+		{input: "http://example.com:80/a", expected: "http://example.com/a"},
+		{input: "https://example.com:8443/a", expected: "https://example.com:8443/a"},
+		{input: "https://example.com/?b=2&a=1&a=0", expected: "https://example.com/?a=1&a=0&b=2"},
+		{input: "http://[2001:DB8::1]:80/", expected: "http://[2001:db8::1]/"},
+		{input: "https://example.com/#section", expected: "https://example.com/#section"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := canonicalUrl(tc.input)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+// newUnusedTargetIndex returns a mock that fails the test if it is called
+func newUnusedTargetIndex(t *testing.T) *mocks.TargetIndex {
+	return mocks.NewTargetIndex(t)
+}
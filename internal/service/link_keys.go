@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+)
+
+// LinkKeys holds the stores keeping state about links in Redis besides the database.
+// Once a link is deleted all of them forget it, so the link stops redirecting, is not
+// handed out again for the same target and its code starts afresh when reused.
+type LinkKeys struct {
+	Cache        repository.LinkCache
+	Limits       repository.ClickLimit
+	Visitors     repository.UniqueVisitor
+	Reservations repository.CodeReservation
+	Targets      repository.TargetIndex
+}
+
+// forget removes everything Redis keeps about the deleted links.
+// Failures are logged only; the links are gone from the database either way.
+func (k LinkKeys) forget(ctx context.Context, codes ...string) {
+	for _, code := range codes {
+		if err := k.Cache.Invalidate(ctx, code); err != nil {
+			log.Error().Err(err).Str("code", code).Msg("Failed to invalidate link cache")
+		}
+		if err := k.Limits.Delete(ctx, code); err != nil {
+			log.Error().Err(err).Str("code", code).Msg("Failed to delete click limit")
+		}
+		if err := k.Visitors.Delete(ctx, code); err != nil {
+			log.Error().Err(err).Str("code", code).Msg("Failed to delete unique visitors")
+		}
+		// Codes are reserved in lower case, see codeRegistry
+		if err := k.Reservations.Release(ctx, strings.ToLower(code)); err != nil {
+			log.Error().Err(err).Str("code", code).Msg("Failed to release code reservation")
+		}
+		if err := k.Targets.Remove(ctx, code); err != nil {
+			log.Error().Err(err).Str("code", code).Msg("Failed to remove target index entry")
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=LinkManagement --filename=link_management.go
+
+// LinkManagement defines the interface for managing the short links of their owners.
+// All methods return ErrUrlNotFound if the link does not exist or is not owned by the user.
+// The code of a link on a custom domain is its storage key, see dto.LinkKey.
+type LinkManagement interface {
+	// ListLinks returns one page of the user's links, newest first.
+	ListLinks(ctx context.Context, userId string, q dto.LinkListQueryDto) (dto.LinkListResponseDto, error)
+	// UpdateLink changes the destination, expiry, disabled or interstitial flag of an owned link.
+	// A new destination must pass the destination policy, otherwise an error wrapping
+	// destpolicy.ErrRejected is returned; a schedule under which the link would never
+	// resolve returns an error wrapping ErrInvalidSchedule.
+	UpdateLink(ctx context.Context, userId, code string, r dto.LinkUpdateRequestDto) (dto.LinkDto, error)
+	// DeleteLink deletes an owned link and frees its code. Its unique visitors, click limit,
+	// code reservation and target index entry are removed from Redis as well.
+	DeleteLink(ctx context.Context, userId, code string) error
+}
+
+type linkManagement struct {
+	links  repository.ShortLink
+	keys   LinkKeys
+	policy destpolicy.Policy
+}
+
+// NewLinkManagement creates and returns a new link management service instance.
+// Changed links are evicted from the redirect cache so they take effect immediately,
+// the redirect counters of click-limited links follow their expiry and deleted links
+// are removed from all Redis stores in keys. New destinations are checked against
+// the same policy as newly shortened links.
+func NewLinkManagement(links repository.ShortLink, keys LinkKeys, policy destpolicy.Policy) LinkManagement {
+	return &linkManagement{
+		links:  links,
+		keys:   keys,
+		policy: policy,
+	}
+}
+
+// ListLinks returns one page of the user's links, newest first.
+func (s *linkManagement) ListLinks(ctx context.Context, userId string, q dto.LinkListQueryDto) (dto.LinkListResponseDto, error) {
+	links, total, err := s.links.ListOwnedLinks(ctx, repository.LinkListFilter{
+		OwnerId: userId,
+		Search:  q.Search,
+		Limit:   q.PageSize,
+		Offset:  (q.Page - 1) * q.PageSize,
+	})
+	if err != nil {
+		return dto.LinkListResponseDto{}, err
+	}
+
+	now := time.Now()
+	items := make([]dto.LinkDto, 0, len(links))
+	for i := range links {
+		items = append(items, toLinkDto(&links[i], now))
+	}
+
+	return dto.LinkListResponseDto{
+		Items:    items,
+		Page:     q.Page,
+		PageSize: q.PageSize,
+		Total:    total,
+	}, nil
+}
+
+// UpdateLink changes the destination, expiry, activation time, disabled or interstitial flag, the routing,
+// the UTM parameters or the redirect options of an owned link.
+func (s *linkManagement) UpdateLink(ctx context.Context, userId, code string, r dto.LinkUpdateRequestDto) (dto.LinkDto, error) {
+	if r.Url != nil {
+		if err := checkDestination(ctx, s.policy, *r.Url); err != nil {
+			return dto.LinkDto{}, err
+		}
+	}
+	routes := r.Routing.ToRouting()
+	if err := routes.Validate(); err != nil {
+		return dto.LinkDto{}, err
+	}
+	params := r.Utm.ToParams()
+	if err := params.Validate(); err != nil {
+		return dto.LinkDto{}, err
+	}
+	for _, url := range routes.Urls() {
+		if err := checkDestination(ctx, s.policy, url); err != nil {
+			return dto.LinkDto{}, err
+		}
+	}
+
+	link, err := s.links.GetOwnedLink(ctx, code, userId)
+	if err != nil {
+		return dto.LinkDto{}, mapLinkNotFound(err)
+	}
+
+	now := time.Now()
+	if r.Url != nil {
+		link.Target = *r.Url
+	}
+	if r.NotBefore != nil {
+		notBefore := r.NotBefore.UTC()
+		link.NotBefore = &notBefore
+		// The zero time removes the schedule, like exp 0 removes the expiry
+		if notBefore.IsZero() {
+			link.NotBefore = nil
+		}
+	}
+	rescheduled := r.ExpInSeconds != nil || r.NotAfter != nil
+	if rescheduled {
+		// Like on creation, exp counts from when a scheduled link goes live
+		start := now
+		if link.NotBefore != nil && link.NotBefore.After(now) {
+			start = *link.NotBefore
+		}
+		link.ExpiresAt = r.ExpiresAt(start)
+	}
+	if rescheduled || r.NotBefore != nil {
+		if err := checkSchedule(link.NotBefore, link.ExpiresAt, now); err != nil {
+			return dto.LinkDto{}, err
+		}
+	}
+	if r.Disabled != nil {
+		link.Disabled = *r.Disabled
+	}
+	if r.Interstitial != nil {
+		link.Interstitial = *r.Interstitial
+	}
+	if r.Routing != nil {
+		link.Routing = routes
+	}
+	if r.Utm != nil {
+		link.Utm = params
+	}
+	if r.RedirectStatus != nil {
+		link.RedirectStatus = *r.RedirectStatus
+	}
+	if r.NoIndex != nil {
+		link.NoIndex = *r.NoIndex
+	}
+	if r.ReferrerPolicy != nil {
+		link.ReferrerPolicy = *r.ReferrerPolicy
+	}
+
+	if err := s.links.UpdateOwnedLink(ctx, link); err != nil {
+		return dto.LinkDto{}, mapLinkNotFound(err)
+	}
+	s.invalidate(ctx, code)
+	if rescheduled && link.MaxClicks != nil {
+		if err := s.keys.Limits.SetExpiry(ctx, code, link.ExpiresAt); err != nil {
+			return dto.LinkDto{}, err
+		}
+	}
+
+	return toLinkDto(link, now), nil
+}
+
+// DeleteLink deletes an owned link and frees its code.
+func (s *linkManagement) DeleteLink(ctx context.Context, userId, code string) error {
+	if err := s.links.DeleteOwnedLink(ctx, code, userId); err != nil {
+		return mapLinkNotFound(err)
+	}
+	s.keys.forget(ctx, code)
+
+	return nil
+}
+
+// invalidate evicts the link from the redirect cache. A failure is logged only,
+// the cached entry then expires on its own.
+func (s *linkManagement) invalidate(ctx context.Context, code string) {
+	if err := s.keys.Cache.Invalidate(ctx, code); err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to invalidate link cache")
+	}
+}
+
+func mapLinkNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.ErrUrlNotFound
+	}
+
+	return err
+}
+
+func toLinkDto(link *model.ShortLink, now time.Time) dto.LinkDto {
+	code, domain := dto.SplitLinkKey(link.Code)
+	res := dto.LinkDto{
+		Code:      code,
+		Domain:    domain,
+		Url:       link.Target,
+		CreatedAt: link.CreatedAt.UTC().Format(time.RFC3339),
+		Disabled:  link.Disabled,
+		MaxClicks: link.MaxClicks,
+
+		PasswordProtected: link.PasswordHash != "",
+		Interstitial:      link.Interstitial,
+		Routing:           dto.NewLinkRoutingDto(link.Routing),
+		Utm:               dto.NewLinkUtmDto(link.Utm),
+
+		RedirectStatus: redirectStatus(link),
+		NoIndex:        link.NoIndex,
+		ReferrerPolicy: link.ReferrerPolicy,
+	}
+	if link.NotBefore != nil {
+		notBefore := link.NotBefore.UTC().Format(time.RFC3339)
+		res.NotBefore = &notBefore
+		res.Scheduled = now.Before(*link.NotBefore)
+	}
+	if link.ExpiresAt != nil {
+		expiresAt := link.ExpiresAt.UTC().Format(time.RFC3339)
+		res.ExpiresAt = &expiresAt
+		res.Expired = !link.ExpiresAt.After(now)
+	}
+
+	return res
+}
@@ -0,0 +1,523 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
+	"gorm.io/gorm"
+)
+
+// newUnusedLinkCache returns a mock that fails the test if it is called
+func newUnusedLinkCache(t *testing.T) *mocks.LinkCache {
+	return mocks.NewLinkCache(t)
+}
+
+// newUnusedClickLimit returns a mock that fails the test if it is called
+func newUnusedClickLimit(t *testing.T) *mocks.ClickLimit {
+	return mocks.NewClickLimit(t)
+}
+
+// newInvalidatedLinkCache returns a mock expecting the code to be evicted once
+func newInvalidatedLinkCache(code string) func(t *testing.T) *mocks.LinkCache {
+	return func(t *testing.T) *mocks.LinkCache {
+		cache := mocks.NewLinkCache(t)
+		cache.On("Invalidate", mock.Anything, code).Return(nil).Once()
+		return cache
+	}
+}
+
+// newLinkKeys returns the Redis stores of the services under test; the stores that are
+// only used for deleted links fail the test if they are called
+func newLinkKeys(t *testing.T, cache *mocks.LinkCache, limits *mocks.ClickLimit) LinkKeys {
+	return LinkKeys{
+		Cache:        cache,
+		Limits:       limits,
+		Visitors:     mocks.NewUniqueVisitor(t),
+		Reservations: mocks.NewCodeReservation(t),
+		Targets:      mocks.NewTargetIndex(t),
+	}
+}
+
+// newForgettingLinkKeys returns Redis stores expecting each of the codes to be forgotten once
+func newForgettingLinkKeys(t *testing.T, codes ...string) LinkKeys {
+	keys := newLinkKeys(t, mocks.NewLinkCache(t), mocks.NewClickLimit(t))
+	for _, code := range codes {
+		keys.Cache.(*mocks.LinkCache).On("Invalidate", mock.Anything, code).Return(nil).Once()
+		keys.Limits.(*mocks.ClickLimit).On("Delete", mock.Anything, code).Return(nil).Once()
+		keys.Visitors.(*mocks.UniqueVisitor).On("Delete", mock.Anything, code).Return(nil).Once()
+		keys.Reservations.(*mocks.CodeReservation).On("Release", mock.Anything, strings.ToLower(code)).Return(nil).Once()
+		keys.Targets.(*mocks.TargetIndex).On("Remove", mock.Anything, code).Return(nil).Once()
+	}
+
+	return keys
+}
+
+func TestLinkManagement_ListLinks(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name           string
+		query          dto.LinkListQueryDto
+		setupLinks     func(t *testing.T) *mocks.ShortLink
+		expectedError  error
+		validateResult func(t *testing.T, res dto.LinkListResponseDto)
+	}{
+		{
+			name:  "page of links",
+			query: dto.LinkListQueryDto{Page: 3, PageSize: 10, Search: "golang"},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("ListOwnedLinks", mock.Anything, repository.LinkListFilter{
+					OwnerId: testOwnerID,
+					Search:  "golang",
+					Limit:   10,
+					Offset:  20,
+				}).Return([]model.ShortLink{
+					{Code: "abc", Target: "https://golang.org", CreatedAt: createdAt},
+					{Code: "old", Target: "https://golang.org/doc", CreatedAt: createdAt, ExpiresAt: &past, Disabled: true},
+				}, int64(22), nil)
+				return links
+			},
+			validateResult: func(t *testing.T, res dto.LinkListResponseDto) {
+				assert.Equal(t, int64(22), res.Total)
+				assert.Equal(t, 3, res.Page)
+				assert.Equal(t, 10, res.PageSize)
+				assert.Len(t, res.Items, 2)
+				assert.Equal(t, dto.LinkDto{Code: "abc", Url: "https://golang.org", CreatedAt: "2026-01-01T00:00:00Z", RedirectStatus: http.StatusFound}, res.Items[0])
+				assert.True(t, res.Items[1].Expired)
+				assert.True(t, res.Items[1].Disabled)
+				assert.NotNil(t, res.Items[1].ExpiresAt)
+			},
+		},
+		{
+			name:  "repository error",
+			query: dto.LinkListQueryDto{Page: 1, PageSize: 10},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("ListOwnedLinks", mock.Anything, mock.Anything).Return(nil, int64(0), assert.AnError)
+				return links
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewLinkManagement(tc.setupLinks(t), newLinkKeys(t, newUnusedLinkCache(t), newUnusedClickLimit(t)), testPolicy)
+
+			res, err := svc.ListLinks(t.Context(), testOwnerID, tc.query)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.validateResult != nil {
+				tc.validateResult(t, res)
+			}
+		})
+	}
+}
+
+func TestLinkManagement_UpdateLink(t *testing.T) {
+	t.Parallel()
+
+	owner := testOwnerID
+	newUrl := "https://go.dev"
+	blockedUrl := "https://evil.example/login"
+	expIn := 3600
+	noExpiry := 0
+	launch := time.Now().Add(24 * time.Hour).UTC()
+	launchEnd := launch.Add(time.Hour)
+	unscheduled := time.Time{}
+	disabled := true
+	interstitial := true
+	permanent := http.StatusMovedPermanently
+	noIndex := true
+	noPolicy := ""
+
+	testCases := []struct {
+		name           string
+		request        dto.LinkUpdateRequestDto
+		setupLinks     func(t *testing.T) *mocks.ShortLink
+		setupCache     func(t *testing.T) *mocks.LinkCache
+		setupLimits    func(t *testing.T) *mocks.ClickLimit
+		expectedError  error
+		validateResult func(t *testing.T, res dto.LinkDto)
+	}{
+		{
+			name:    "change destination and extend expiry",
+			request: dto.LinkUpdateRequestDto{Url: &newUrl, ExpInSeconds: &expIn},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.Target == newUrl && link.ExpiresAt != nil &&
+						time.Until(*link.ExpiresAt) > 59*time.Minute && !link.Disabled
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Equal(t, newUrl, res.Url)
+				assert.NotNil(t, res.ExpiresAt)
+				assert.False(t, res.Expired)
+			},
+		},
+		{
+			name:    "remove expiry and disable",
+			request: dto.LinkUpdateRequestDto{ExpInSeconds: &noExpiry, Disabled: &disabled},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				expiresAt := time.Now().Add(-time.Hour)
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner, ExpiresAt: &expiresAt}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.Target == "https://golang.org" && link.ExpiresAt == nil && link.Disabled
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Nil(t, res.ExpiresAt)
+				assert.False(t, res.Expired)
+				assert.True(t, res.Disabled)
+			},
+		},
+		{
+			name:    "force interstitial",
+			request: dto.LinkUpdateRequestDto{Interstitial: &interstitial},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.Interstitial && link.Target == "https://golang.org"
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.True(t, res.Interstitial)
+			},
+		},
+		{
+			name: "replace routing",
+			request: dto.LinkUpdateRequestDto{Routing: &dto.LinkRoutingDto{
+				Variants: []dto.LinkVariantDto{{Name: "b", Url: "https://go.dev/b", Weight: 1}},
+			}},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).Return(&model.ShortLink{
+					Code: "abc", Target: "https://golang.org", OwnerId: &owner,
+					Routing: &routing.Routing{Rules: []routing.Rule{{Name: "mobile", Url: "https://m.golang.org", Device: "mobile"}}},
+				}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return len(link.Routing.Rules) == 0 && len(link.Routing.Variants) == 1
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Equal(t, &dto.LinkRoutingDto{
+					Variants: []dto.LinkVariantDto{{Name: "b", Url: "https://go.dev/b", Weight: 1}},
+				}, res.Routing)
+			},
+		},
+		{
+			name:    "empty routing removes it",
+			request: dto.LinkUpdateRequestDto{Routing: &dto.LinkRoutingDto{}},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).Return(&model.ShortLink{
+					Code: "abc", Target: "https://golang.org", OwnerId: &owner,
+					Routing: &routing.Routing{Rules: []routing.Rule{{Name: "mobile", Url: "https://m.golang.org", Device: "mobile"}}},
+				}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.Routing == nil
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Nil(t, res.Routing)
+			},
+		},
+		{
+			name:    "set utm parameters",
+			request: dto.LinkUpdateRequestDto{Utm: &dto.LinkUtmDto{Source: "newsletter", Campaign: "{code}"}},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return *link.Utm == utm.Params{Source: "newsletter", Campaign: "{code}"}
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Equal(t, &dto.LinkUtmDto{Source: "newsletter", Campaign: "{code}"}, res.Utm)
+			},
+		},
+		{
+			name:    "set redirect options",
+			request: dto.LinkUpdateRequestDto{RedirectStatus: &permanent, NoIndex: &noIndex, ReferrerPolicy: &noPolicy},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner, ReferrerPolicy: "origin"}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.RedirectStatus == http.StatusMovedPermanently && link.NoIndex && link.ReferrerPolicy == ""
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Equal(t, http.StatusMovedPermanently, res.RedirectStatus)
+				assert.True(t, res.NoIndex)
+				assert.Empty(t, res.ReferrerPolicy)
+			},
+		},
+		{
+			name:    "unknown utm placeholder is rejected",
+			request: dto.LinkUpdateRequestDto{Utm: &dto.LinkUtmDto{Term: "{keyword}"}},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				return mocks.NewShortLink(t)
+			},
+			setupCache:    newUnusedLinkCache,
+			expectedError: utm.ErrInvalidTemplate,
+		},
+		{
+			name: "blocked routing destination is rejected",
+			request: dto.LinkUpdateRequestDto{Routing: &dto.LinkRoutingDto{
+				Rules: []dto.LinkRuleDto{{Name: "mobile", Url: blockedUrl, Device: "mobile"}},
+			}},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				return mocks.NewShortLink(t)
+			},
+			setupCache:    newUnusedLinkCache,
+			expectedError: destpolicy.ErrDomainBlocked,
+		},
+		{
+			name:    "schedule a launch, exp counts from the activation time",
+			request: dto.LinkUpdateRequestDto{NotBefore: &launch, ExpInSeconds: &expIn},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.NotBefore.Equal(launch) && link.ExpiresAt.Equal(launchEnd)
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.True(t, res.Scheduled)
+				assert.Equal(t, launch.Format(time.RFC3339), *res.NotBefore)
+				assert.Equal(t, launchEnd.Format(time.RFC3339), *res.ExpiresAt)
+			},
+		},
+		{
+			name:    "zero not_before removes the schedule",
+			request: dto.LinkUpdateRequestDto{NotBefore: &unscheduled},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner, NotBefore: &launch}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.NotBefore == nil
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.False(t, res.Scheduled)
+				assert.Nil(t, res.NotBefore)
+			},
+		},
+		{
+			name:    "not_after replaces the expiry",
+			request: dto.LinkUpdateRequestDto{NotAfter: &launchEnd},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				expiresAt := time.Now().Add(time.Hour)
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner, ExpiresAt: &expiresAt}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.MatchedBy(func(link *model.ShortLink) bool {
+					return link.ExpiresAt.Equal(launchEnd)
+				})).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.False(t, res.Scheduled)
+				assert.Nil(t, res.NotBefore)
+			},
+		},
+		{
+			name:    "activation after the expiry is rejected",
+			request: dto.LinkUpdateRequestDto{NotBefore: &launch},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				expiresAt := time.Now().Add(time.Hour)
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Target: "https://golang.org", OwnerId: &owner, ExpiresAt: &expiresAt}, nil)
+				return links
+			},
+			setupCache:    newUnusedLinkCache,
+			expectedError: e.ErrInvalidSchedule,
+		},
+		{
+			name:    "click counter follows the new expiry",
+			request: dto.LinkUpdateRequestDto{ExpInSeconds: &noExpiry},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				maxClicks := int64(3)
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", OwnerId: &owner, MaxClicks: &maxClicks}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.Anything).Return(nil)
+				return links
+			},
+			setupCache: newInvalidatedLinkCache("abc"),
+			setupLimits: func(t *testing.T) *mocks.ClickLimit {
+				limits := mocks.NewClickLimit(t)
+				limits.On("SetExpiry", mock.Anything, "abc", (*time.Time)(nil)).Return(nil).Once()
+				return limits
+			},
+			validateResult: func(t *testing.T, res dto.LinkDto) {
+				assert.Equal(t, int64(3), *res.MaxClicks)
+			},
+		},
+		{
+			name:    "blocked destination is rejected",
+			request: dto.LinkUpdateRequestDto{Url: &blockedUrl},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				return mocks.NewShortLink(t)
+			},
+			setupCache:    newUnusedLinkCache,
+			expectedError: destpolicy.ErrDomainBlocked,
+		},
+		{
+			name:    "link not owned by user",
+			request: dto.LinkUpdateRequestDto{Url: &newUrl},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).Return(nil, gorm.ErrRecordNotFound)
+				return links
+			},
+			setupCache:    newUnusedLinkCache,
+			expectedError: e.ErrUrlNotFound,
+		},
+		{
+			name:    "update fails",
+			request: dto.LinkUpdateRequestDto{Url: &newUrl},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", OwnerId: &owner}, nil)
+				links.On("UpdateOwnedLink", mock.Anything, mock.Anything).Return(assert.AnError)
+				return links
+			},
+			setupCache:    newUnusedLinkCache,
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setupLimits := tc.setupLimits
+			if setupLimits == nil {
+				setupLimits = newUnusedClickLimit
+			}
+			svc := NewLinkManagement(tc.setupLinks(t), newLinkKeys(t, tc.setupCache(t), setupLimits(t)), testPolicy)
+
+			res, err := svc.UpdateLink(t.Context(), testOwnerID, "abc", tc.request)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.validateResult != nil {
+				tc.validateResult(t, res)
+			}
+		})
+	}
+}
+
+func TestLinkManagement_DeleteLink(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		setupLinks    func(t *testing.T) *mocks.ShortLink
+		setupKeys     func(t *testing.T) LinkKeys
+		expectedError error
+	}{
+		{
+			name: "delete owned link",
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("DeleteOwnedLink", mock.Anything, "AbC", testOwnerID).Return(nil)
+				return links
+			},
+			setupKeys: func(t *testing.T) LinkKeys {
+				return newForgettingLinkKeys(t, "AbC")
+			},
+		},
+		{
+			name: "redis failures do not fail the delete",
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("DeleteOwnedLink", mock.Anything, "AbC", testOwnerID).Return(nil)
+				return links
+			},
+			setupKeys: func(t *testing.T) LinkKeys {
+				keys := newLinkKeys(t, mocks.NewLinkCache(t), mocks.NewClickLimit(t))
+				keys.Cache.(*mocks.LinkCache).On("Invalidate", mock.Anything, "AbC").Return(assert.AnError)
+				keys.Limits.(*mocks.ClickLimit).On("Delete", mock.Anything, "AbC").Return(assert.AnError)
+				keys.Visitors.(*mocks.UniqueVisitor).On("Delete", mock.Anything, "AbC").Return(assert.AnError)
+				keys.Reservations.(*mocks.CodeReservation).On("Release", mock.Anything, "abc").Return(assert.AnError)
+				keys.Targets.(*mocks.TargetIndex).On("Remove", mock.Anything, "AbC").Return(assert.AnError)
+				return keys
+			},
+		},
+		{
+			name: "link not owned by user",
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("DeleteOwnedLink", mock.Anything, "AbC", testOwnerID).Return(gorm.ErrRecordNotFound)
+				return links
+			},
+			setupKeys: func(t *testing.T) LinkKeys {
+				return newLinkKeys(t, newUnusedLinkCache(t), newUnusedClickLimit(t))
+			},
+			expectedError: e.ErrUrlNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewLinkManagement(tc.setupLinks(t), tc.setupKeys(t), testPolicy)
+
+			err := svc.DeleteLink(t.Context(), testOwnerID, "AbC")
+
+			assert.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/pagetitle"
+	"gorm.io/gorm"
+)
+
+const (
+	// titleTTL is how long a fetched page title is reused.
+	titleTTL = 24 * time.Hour
+	// failedTitleTTL keeps pages that could not be fetched from being retried on every preview.
+	failedTitleTTL = time.Hour
+)
+
+// Warnings about suspicious destinations shown on the preview page
+const (
+	WarningUnparsable = "The destination address could not be parsed"
+	WarningNoHttps    = "The destination does not use a secure connection (HTTPS)"
+	WarningIpAddress  = "The destination is an IP address instead of a domain name"
+	WarningPunycode   = "The domain contains international characters that can imitate another domain"
+	WarningUserInfo   = "The address contains a user name, which can disguise the real domain"
+	WarningPort       = "The destination uses a non-standard port"
+	WarningShortener  = "The destination is another link shortener, which hides the final destination"
+)
+
+// shortenerDomains are public link shorteners whose links hide their real destination.
+var shortenerDomains = []string{
+	"bit.ly", "buff.ly", "cutt.ly", "goo.gl", "is.gd", "ow.ly", "rb.gy", "rebrand.ly", "t.co", "t.ly", "tiny.cc", "tinyurl.com",
+}
+
+//go:generate mockery --name=LinkPreview --filename=link_preview.go
+
+// LinkPreview defines the interface for describing short links without following them.
+type LinkPreview interface {
+	// Preview describes the destination of an active link. It never counts as a click
+	// and hides the destination of password-protected links.
+	// The code of a link on a custom domain is its storage key, see dto.LinkKey.
+	// Returns ErrUrlNotFound if the link does not exist, is disabled or has expired, and
+	// ErrLinkNotYetActive before the activation time of a scheduled link.
+	Preview(ctx context.Context, code string) (dto.LinkPreviewDto, error)
+}
+
+type linkPreview struct {
+	repo    repository.UrlStorage
+	titles  repository.PageTitle
+	fetcher pagetitle.Fetcher
+	baseUrl string
+}
+
+// NewLinkPreview creates and returns a new link preview service instance.
+// Page titles are fetched with the fetcher and cached in titles;
+// baseUrl is the public prefix the code is appended to, e.g. https://sho.rt/.
+func NewLinkPreview(repo repository.UrlStorage, titles repository.PageTitle, fetcher pagetitle.Fetcher, baseUrl string) LinkPreview {
+	return &linkPreview{
+		repo:    repo,
+		titles:  titles,
+		fetcher: fetcher,
+		baseUrl: baseUrl,
+	}
+}
+
+// Preview describes the destination of an active link.
+func (s *linkPreview) Preview(ctx context.Context, code string) (dto.LinkPreviewDto, error) {
+	link, err := s.repo.GetLink(ctx, code)
+	if err != nil {
+		if errors.Is(err, redis.Nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.LinkPreviewDto{}, e.ErrUrlNotFound
+		}
+		return dto.LinkPreviewDto{}, err
+	}
+	if link.NotBefore != nil && time.Now().Before(*link.NotBefore) {
+		return dto.LinkPreviewDto{}, e.ErrLinkNotYetActive
+	}
+
+	shortUrl := ShortUrl(s.baseUrl, code)
+	plainCode, _ := dto.SplitLinkKey(code)
+	res := dto.LinkPreviewDto{
+		Code:              plainCode,
+		ShortUrl:          shortUrl,
+		ContinueUrl:       shortUrl + "?confirm=1",
+		Warnings:          []string{},
+		PasswordProtected: link.PasswordHash != "",
+	}
+	// The destination of a protected link is only revealed with the password
+	if res.PasswordProtected {
+		return res, nil
+	}
+
+	res.Url = link.Target
+	res.Warnings = destinationWarnings(link.Target)
+	res.Suspicious = len(res.Warnings) > 0
+	res.Title = s.title(ctx, link.Target)
+
+	return res, nil
+}
+
+// title returns the cached title of the page, fetching it on a miss.
+// Failures are logged only; the preview is shown without a title.
+func (s *linkPreview) title(ctx context.Context, target string) string {
+	title, found, err := s.titles.Get(ctx, target)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read page title cache")
+	}
+	if found {
+		return title
+	}
+
+	ttl := titleTTL
+	title, err = s.fetcher.Fetch(ctx, target)
+	if err != nil {
+		log.Debug().Err(err).Str("url", target).Msg("Failed to fetch page title")
+		ttl = failedTitleTTL
+	}
+	if err := s.titles.Set(ctx, target, title, ttl); err != nil {
+		log.Warn().Err(err).Msg("Failed to write page title cache")
+	}
+
+	return title
+}
+
+// destinationWarnings lists the reasons a destination URL looks suspicious.
+func destinationWarnings(target string) []string {
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		return []string{WarningUnparsable}
+	}
+
+	warnings := []string{}
+	host := strings.ToLower(u.Hostname())
+	if u.Scheme != "https" {
+		warnings = append(warnings, WarningNoHttps)
+	}
+	if net.ParseIP(host) != nil {
+		warnings = append(warnings, WarningIpAddress)
+	}
+	if strings.HasPrefix(host, "xn--") || strings.Contains(host, ".xn--") {
+		warnings = append(warnings, WarningPunycode)
+	}
+	if u.User != nil {
+		warnings = append(warnings, WarningUserInfo)
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		warnings = append(warnings, WarningPort)
+	}
+	for _, domain := range shortenerDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			warnings = append(warnings, WarningShortener)
+			break
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	titleMocks "github.com/vincent-tien/bookmark-management/pkg/pagetitle/mocks"
+	"gorm.io/gorm"
+)
+
+func TestLinkPreview_Preview(t *testing.T) {
+	t.Parallel()
+
+	launch := time.Now().Add(time.Hour)
+
+	linkTo := func(link *model.ShortLink) func(t *testing.T) *mocks.UrlStorage {
+		return func(t *testing.T) *mocks.UrlStorage {
+			repo := mocks.NewUrlStorage(t)
+			repo.On("GetLink", mock.Anything, "abc").Return(link, nil)
+			return repo
+		}
+	}
+	newUnusedPageTitleRepo := func(t *testing.T) *mocks.PageTitle {
+		return mocks.NewPageTitle(t)
+	}
+	newUnusedFetcher := func(t *testing.T) *titleMocks.Fetcher {
+		return titleMocks.NewFetcher(t)
+	}
+
+	testCases := []struct {
+		name           string
+		setupRepo      func(t *testing.T) *mocks.UrlStorage
+		setupTitles    func(t *testing.T) *mocks.PageTitle
+		setupFetcher   func(t *testing.T) *titleMocks.Fetcher
+		expectedError  error
+		validateResult func(t *testing.T, res dto.LinkPreviewDto)
+	}{
+		{
+			name:      "cached title",
+			setupRepo: linkTo(&model.ShortLink{Code: "abc", Target: "https://example.com"}),
+			setupTitles: func(t *testing.T) *mocks.PageTitle {
+				titles := mocks.NewPageTitle(t)
+				titles.On("Get", mock.Anything, "https://example.com").Return("Example Domain", true, nil)
+				return titles
+			},
+			setupFetcher: newUnusedFetcher,
+			validateResult: func(t *testing.T, res dto.LinkPreviewDto) {
+				assert.Equal(t, dto.LinkPreviewDto{
+					Code:        "abc",
+					ShortUrl:    "https://sho.rt/abc",
+					ContinueUrl: "https://sho.rt/abc?confirm=1",
+					Url:         "https://example.com",
+					Title:       "Example Domain",
+					Warnings:    []string{},
+				}, res)
+			},
+		},
+		{
+			name:      "title fetched on cache miss",
+			setupRepo: linkTo(&model.ShortLink{Code: "abc", Target: "https://example.com"}),
+			setupTitles: func(t *testing.T) *mocks.PageTitle {
+				titles := mocks.NewPageTitle(t)
+				titles.On("Get", mock.Anything, "https://example.com").Return("", false, nil)
+				titles.On("Set", mock.Anything, "https://example.com", "Example Domain", titleTTL).Return(nil).Once()
+				return titles
+			},
+			setupFetcher: func(t *testing.T) *titleMocks.Fetcher {
+				fetcher := titleMocks.NewFetcher(t)
+				fetcher.On("Fetch", mock.Anything, "https://example.com").Return("Example Domain", nil).Once()
+				return fetcher
+			},
+			validateResult: func(t *testing.T, res dto.LinkPreviewDto) {
+				assert.Equal(t, "Example Domain", res.Title)
+			},
+		},
+		{
+			name:      "failed fetch is cached briefly and shown without title",
+			setupRepo: linkTo(&model.ShortLink{Code: "abc", Target: "http://203.0.113.7:8080/login"}),
+			setupTitles: func(t *testing.T) *mocks.PageTitle {
+				titles := mocks.NewPageTitle(t)
+				titles.On("Get", mock.Anything, mock.Anything).Return("", false, nil)
+				titles.On("Set", mock.Anything, "http://203.0.113.7:8080/login", "", failedTitleTTL).Return(nil).Once()
+				return titles
+			},
+			setupFetcher: func(t *testing.T) *titleMocks.Fetcher {
+				fetcher := titleMocks.NewFetcher(t)
+				fetcher.On("Fetch", mock.Anything, mock.Anything).Return("", assert.AnError)
+				return fetcher
+			},
+			validateResult: func(t *testing.T, res dto.LinkPreviewDto) {
+				assert.Empty(t, res.Title)
+				assert.True(t, res.Suspicious)
+				assert.Equal(t, []string{WarningNoHttps, WarningIpAddress, WarningPort}, res.Warnings)
+			},
+		},
+		{
+			name:         "password-protected link hides its destination",
+			setupRepo:    linkTo(&model.ShortLink{Code: "abc", Target: "https://example.com", PasswordHash: "hash"}),
+			setupTitles:  newUnusedPageTitleRepo,
+			setupFetcher: newUnusedFetcher,
+			validateResult: func(t *testing.T, res dto.LinkPreviewDto) {
+				assert.True(t, res.PasswordProtected)
+				assert.Empty(t, res.Url)
+				assert.Empty(t, res.Title)
+			},
+		},
+		{
+			name:          "scheduled link before its activation time",
+			setupRepo:     linkTo(&model.ShortLink{Code: "abc", Target: "https://example.com", NotBefore: &launch}),
+			setupTitles:   newUnusedPageTitleRepo,
+			setupFetcher:  newUnusedFetcher,
+			expectedError: e.ErrLinkNotYetActive,
+		},
+		{
+			name: "link not found",
+			setupRepo: func(t *testing.T) *mocks.UrlStorage {
+				repo := mocks.NewUrlStorage(t)
+				repo.On("GetLink", mock.Anything, "abc").Return(nil, gorm.ErrRecordNotFound)
+				return repo
+			},
+			setupTitles:   newUnusedPageTitleRepo,
+			setupFetcher:  newUnusedFetcher,
+			expectedError: e.ErrUrlNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewLinkPreview(tc.setupRepo(t), tc.setupTitles(t), tc.setupFetcher(t), "https://sho.rt/")
+
+			res, err := svc.Preview(t.Context(), "abc")
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.validateResult != nil {
+				tc.validateResult(t, res)
+			}
+		})
+	}
+}
+
+func TestDestinationWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		target   string
+		expected []string
+	}{
+		{target: "https://example.com/path", expected: []string{}},
+		{target: "https://example.com:443/", expected: []string{}},
+		{target: "http://example.com", expected: []string{WarningNoHttps}},
+		{target: "https://[2001:db8::1]/", expected: []string{WarningIpAddress}},
+		{target: "https://xn--pple-43d.com/", expected: []string{WarningPunycode}},
+		{target: "https://paypal.com@evil.example/", expected: []string{WarningUserInfo}},
+		{target: "https://example.com:8443/", expected: []string{WarningPort}},
+		{target: "https://bit.ly/abc", expected: []string{WarningShortener}},
+		{target: "https://www.tinyurl.com/abc", expected: []string{WarningShortener}},
+		{target: "https://notbit.ly/abc", expected: []string{}},
+		{target: "not a url", expected: []string{WarningUnparsable}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, destinationWarnings(tc.target))
+		})
+	}
+}
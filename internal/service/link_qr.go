@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/qrcode"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=LinkQr --filename=link_qr.go
+
+// LinkQr defines the interface for rendering QR codes of short links.
+type LinkQr interface {
+	// Render draws the QR code of the full short URL of an active link in the requested format.
+	// Looking up the link does not count as a click.
+	// Returns ErrUrlNotFound if the link does not exist, is disabled or has expired,
+	// and ErrInvalidQrColor if a color is not six hex digits.
+	Render(ctx context.Context, q dto.LinkQrQueryDto) ([]byte, error)
+}
+
+type linkQr struct {
+	repo    repository.UrlStorage
+	baseUrl string
+}
+
+// NewLinkQr creates and returns a new QR code service instance.
+// baseUrl is the public prefix the code is appended to, e.g. https://sho.rt/.
+func NewLinkQr(repo repository.UrlStorage, baseUrl string) LinkQr {
+	return &linkQr{
+		repo:    repo,
+		baseUrl: baseUrl,
+	}
+}
+
+// Render draws the QR code of the full short URL of an active link in the requested format.
+func (s *linkQr) Render(ctx context.Context, q dto.LinkQrQueryDto) ([]byte, error) {
+	opts := qrcode.Options{
+		Size:   q.Size,
+		Margin: *q.Margin,
+		Level:  q.Level,
+	}
+	var err error
+	if opts.Foreground, err = qrcode.ParseHexColor(q.Foreground); err != nil {
+		return nil, e.ErrInvalidQrColor
+	}
+	if opts.Background, err = qrcode.ParseHexColor(q.Background); err != nil {
+		return nil, e.ErrInvalidQrColor
+	}
+
+	if _, err := s.repo.GetLink(ctx, q.Code); err != nil {
+		if errors.Is(err, redis.Nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, e.ErrUrlNotFound
+		}
+		return nil, err
+	}
+
+	shortUrl := ShortUrl(s.baseUrl, q.Code)
+	if q.Format == dto.QrFormatSvg {
+		return qrcode.SVG(shortUrl, opts)
+	}
+
+	return qrcode.PNG(shortUrl, opts)
+}
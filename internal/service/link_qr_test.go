@@ -0,0 +1,96 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+)
+
+func TestLinkQr_Render(t *testing.T) {
+	t.Parallel()
+
+	query := func(format, fg string) dto.LinkQrQueryDto {
+		q := dto.LinkQrQueryDto{Code: "abc", Format: format, Foreground: fg}
+		q.Prepare()
+		return q
+	}
+	activeLink := func(t *testing.T) *mocks.UrlStorage {
+		repo := mocks.NewUrlStorage(t)
+		repo.On("GetLink", mock.Anything, "abc").Return(&model.ShortLink{Code: "abc", Target: "https://example.com"}, nil)
+		return repo
+	}
+
+	testCases := []struct {
+		name           string
+		query          dto.LinkQrQueryDto
+		setupRepo      func(t *testing.T) *mocks.UrlStorage
+		expectedError  error
+		validateResult func(t *testing.T, img []byte)
+	}{
+		{
+			name:      "png",
+			query:     query(dto.QrFormatPng, ""),
+			setupRepo: activeLink,
+			validateResult: func(t *testing.T, img []byte) {
+				assert.True(t, strings.HasPrefix(string(img), "\x89PNG"))
+			},
+		},
+		{
+			name:      "svg with custom foreground",
+			query:     query(dto.QrFormatSvg, "#1A2B3C"),
+			setupRepo: activeLink,
+			validateResult: func(t *testing.T, img []byte) {
+				assert.True(t, strings.HasPrefix(string(img), "<svg "))
+				assert.Contains(t, string(img), `fill="#1a2b3c"`)
+			},
+		},
+		{
+			name:          "invalid color",
+			query:         query(dto.QrFormatPng, "red"),
+			setupRepo:     func(t *testing.T) *mocks.UrlStorage { return mocks.NewUrlStorage(t) },
+			expectedError: e.ErrInvalidQrColor,
+		},
+		{
+			name:  "link not found",
+			query: query(dto.QrFormatPng, ""),
+			setupRepo: func(t *testing.T) *mocks.UrlStorage {
+				repo := mocks.NewUrlStorage(t)
+				repo.On("GetLink", mock.Anything, "abc").Return(nil, redis.Nil)
+				return repo
+			},
+			expectedError: e.ErrUrlNotFound,
+		},
+		{
+			name:  "lookup fails",
+			query: query(dto.QrFormatPng, ""),
+			setupRepo: func(t *testing.T) *mocks.UrlStorage {
+				repo := mocks.NewUrlStorage(t)
+				repo.On("GetLink", mock.Anything, "abc").Return(nil, assert.AnError)
+				return repo
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewLinkQr(tc.setupRepo(t), "https://sho.rt/")
+
+			img, err := svc.Render(t.Context(), tc.query)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.validateResult != nil {
+				tc.validateResult(t, img)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxStatsBuckets bounds the size of the returned time series.
+const maxStatsBuckets = 1000
+
+//go:generate mockery --name=LinkStats --filename=link_stats.go
+
+// LinkStats defines the interface for short link analytics services.
+// It provides click statistics to the owner of a link.
+type LinkStats interface {
+	// GetStats aggregates the clicks of the owner's link over the requested range.
+	// Returns ErrUrlNotFound if the link does not exist or is not owned by the user,
+	// and ErrInvalidStatsRange if the range is empty or too fine-grained.
+	GetStats(ctx context.Context, userId string, q dto.LinkStatsQueryDto) (dto.LinkStatsResponseDto, error)
+}
+
+type linkStats struct {
+	links    repository.ShortLink
+	clicks   repository.LinkClick
+	visitors repository.UniqueVisitor
+}
+
+// NewLinkStats creates and returns a new link analytics service instance.
+func NewLinkStats(links repository.ShortLink, clicks repository.LinkClick, visitors repository.UniqueVisitor) LinkStats {
+	return &linkStats{
+		links:    links,
+		clicks:   clicks,
+		visitors: visitors,
+	}
+}
+
+// GetStats aggregates the clicks of the owner's link over the requested range.
+func (s *linkStats) GetStats(ctx context.Context, userId string, q dto.LinkStatsQueryDto) (dto.LinkStatsResponseDto, error) {
+	if !q.From.Before(q.To) || bucketCount(q.From, q.To, q.Granularity) > maxStatsBuckets {
+		return dto.LinkStatsResponseDto{}, e.ErrInvalidStatsRange
+	}
+
+	link, err := s.links.GetOwnedLink(ctx, q.Code, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.LinkStatsResponseDto{}, e.ErrUrlNotFound
+		}
+		return dto.LinkStatsResponseDto{}, err
+	}
+
+	filter := repository.ClickFilter{Code: q.Code, From: q.From, To: q.To, IncludeBots: q.IncludeBots}
+
+	buckets, err := s.clicks.CountClicksOverTime(ctx, filter, q.Granularity)
+	if err != nil {
+		return dto.LinkStatsResponseDto{}, err
+	}
+
+	code, _ := dto.SplitLinkKey(q.Code)
+	res := dto.LinkStatsResponseDto{
+		Code:        code,
+		From:        q.From.Format(time.RFC3339),
+		To:          q.To.Format(time.RFC3339),
+		Granularity: q.Granularity,
+		IncludeBots: q.IncludeBots,
+		Series:      buildSeries(buckets, q.From, q.To, q.Granularity),
+	}
+	for _, b := range buckets {
+		res.TotalClicks += b.Clicks
+	}
+
+	breakdowns := []struct {
+		field  string
+		target *[]dto.LinkStatsBreakdownDto
+	}{
+		{repository.ClickFieldReferrerHost, &res.Referrers},
+		{repository.ClickFieldDevice, &res.Devices},
+		{repository.ClickFieldBrowser, &res.Browsers},
+		{repository.ClickFieldOS, &res.OperatingSystems},
+	}
+	for _, b := range breakdowns {
+		counts, err := s.clicks.CountClicksBy(ctx, filter, b.field)
+		if err != nil {
+			return dto.LinkStatsResponseDto{}, err
+		}
+		*b.target = toBreakdown(counts)
+	}
+
+	variants, err := s.clicks.CountClicksBy(ctx, filter, repository.ClickFieldVariant)
+	if err != nil {
+		return dto.LinkStatsResponseDto{}, err
+	}
+	res.Variants = toVariants(variants, link)
+
+	if res.UniqueVisitors, err = s.visitors.CountVisitors(ctx, q.Code, q.From, q.To); err != nil {
+		return dto.LinkStatsResponseDto{}, err
+	}
+	if res.AllTimeUniqueVisitors, err = s.visitors.CountAllTimeVisitors(ctx, q.Code); err != nil {
+		return dto.LinkStatsResponseDto{}, err
+	}
+
+	return res, nil
+}
+
+// buildSeries spreads the sorted click counts over all buckets of the range, including empty buckets.
+func buildSeries(buckets []repository.ClickBucket, from, to time.Time, granularity string) []dto.LinkStatsPointDto {
+	series := make([]dto.LinkStatsPointDto, 0)
+	i := 0
+	for start := truncateTime(from, granularity); start.Before(to); start = nextBucket(start, granularity) {
+		var clicks int64
+		if i < len(buckets) && buckets[i].Start.Equal(start) {
+			clicks = buckets[i].Clicks
+			i++
+		}
+
+		series = append(series, dto.LinkStatsPointDto{
+			Time:   start.Format(time.RFC3339),
+			Clicks: clicks,
+		})
+	}
+
+	return series
+}
+
+func bucketCount(from, to time.Time, granularity string) int {
+	switch granularity {
+	case dto.GranularityHour:
+		return int(to.Sub(from)/time.Hour) + 1
+	case dto.GranularityMonth:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	default:
+		return int(to.Sub(from)/(24*time.Hour)) + 1
+	}
+}
+
+func truncateTime(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	switch granularity {
+	case dto.GranularityHour:
+		return t.Truncate(time.Hour)
+	case dto.GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case dto.GranularityHour:
+		return t.Add(time.Hour)
+	case dto.GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func toBreakdown(counts []repository.ClickCount) []dto.LinkStatsBreakdownDto {
+	res := make([]dto.LinkStatsBreakdownDto, 0, len(counts))
+	for _, c := range counts {
+		res = append(res, dto.LinkStatsBreakdownDto{Value: c.Value, Clicks: c.Clicks})
+	}
+
+	return res
+}
+
+// toVariants reports the clicks per rule or variant together with the UTM parameters
+// its visitors get.
+func toVariants(counts []repository.ClickCount, link *model.ShortLink) []dto.LinkStatsVariantDto {
+	res := make([]dto.LinkStatsVariantDto, 0, len(counts))
+	code, _ := dto.SplitLinkKey(link.Code)
+	for _, c := range counts {
+		variant := dto.LinkStatsVariantDto{Value: c.Value, Clicks: c.Clicks}
+		if !link.Utm.IsEmpty() {
+			params := link.Utm.Expand(code, c.Value)
+			variant.Utm = dto.NewLinkUtmDto(&params)
+		}
+		res = append(res, variant)
+	}
+
+	return res
+}
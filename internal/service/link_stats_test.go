@@ -0,0 +1,188 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
+	"gorm.io/gorm"
+)
+
+const testOwnerID = "deb745af-1a62-4efa-99a0-f06b274bd993"
+
+// newUnusedShortLinkRepo returns a mock that fails the test if it is called
+func newUnusedShortLinkRepo(t *testing.T) *mocks.ShortLink {
+	return mocks.NewShortLink(t)
+}
+
+// newUnusedLinkClickRepo returns a mock that fails the test if it is called
+func newUnusedLinkClickRepo(t *testing.T) *mocks.LinkClick {
+	return mocks.NewLinkClick(t)
+}
+
+// newUnusedUniqueVisitorRepo returns a mock that fails the test if it is called
+func newUnusedUniqueVisitorRepo(t *testing.T) *mocks.UniqueVisitor {
+	return mocks.NewUniqueVisitor(t)
+}
+
+func TestLinkStats_GetStats(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          dto.LinkStatsQueryDto
+		setupLinks     func(t *testing.T) *mocks.ShortLink
+		setupClicks    func(t *testing.T) *mocks.LinkClick
+		setupVisitors  func(t *testing.T) *mocks.UniqueVisitor
+		expectedError  error
+		validateResult func(t *testing.T, res dto.LinkStatsResponseDto)
+	}{
+		{
+			name:  "daily series with breakdowns",
+			query: dto.LinkStatsQueryDto{Code: "abc", From: from, To: to, Granularity: dto.GranularityDay},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).
+					Return(&model.ShortLink{Code: "abc", Utm: &utm.Params{Campaign: "spring", Content: "{code}-{variant}"}}, nil)
+				return links
+			},
+			setupClicks: func(t *testing.T) *mocks.LinkClick {
+				clicks := mocks.NewLinkClick(t)
+				clicks.On("CountClicksOverTime", mock.Anything, repository.ClickFilter{Code: "abc", From: from, To: to}, dto.GranularityDay).
+					Return([]repository.ClickBucket{
+						{Start: from, Clicks: 2},
+						{Start: from.Add(48 * time.Hour), Clicks: 1},
+					}, nil)
+				clicks.On("CountClicksBy", mock.Anything, mock.Anything, repository.ClickFieldBrowser).
+					Return([]repository.ClickCount{{Value: "Chrome", Clicks: 3}}, nil)
+				clicks.On("CountClicksBy", mock.Anything, mock.Anything, repository.ClickFieldVariant).
+					Return([]repository.ClickCount{{Value: "b", Clicks: 2}, {Value: "default", Clicks: 1}}, nil)
+				clicks.On("CountClicksBy", mock.Anything, mock.Anything, mock.Anything).
+					Return([]repository.ClickCount{}, nil)
+				return clicks
+			},
+			setupVisitors: func(t *testing.T) *mocks.UniqueVisitor {
+				visitors := mocks.NewUniqueVisitor(t)
+				visitors.On("CountVisitors", mock.Anything, "abc", from, to).Return(int64(2), nil)
+				visitors.On("CountAllTimeVisitors", mock.Anything, "abc").Return(int64(10), nil)
+				return visitors
+			},
+			validateResult: func(t *testing.T, res dto.LinkStatsResponseDto) {
+				assert.Equal(t, int64(3), res.TotalClicks)
+				assert.Equal(t, int64(2), res.UniqueVisitors)
+				assert.Equal(t, int64(10), res.AllTimeUniqueVisitors)
+				assert.Equal(t, []dto.LinkStatsPointDto{
+					{Time: "2026-01-01T00:00:00Z", Clicks: 2},
+					{Time: "2026-01-02T00:00:00Z", Clicks: 0},
+					{Time: "2026-01-03T00:00:00Z", Clicks: 1},
+				}, res.Series)
+				assert.Equal(t, []dto.LinkStatsBreakdownDto{{Value: "Chrome", Clicks: 3}}, res.Browsers)
+				assert.Equal(t, []dto.LinkStatsVariantDto{
+					{Value: "b", Clicks: 2, Utm: &dto.LinkUtmDto{Campaign: "spring", Content: "abc-b"}},
+					{Value: "default", Clicks: 1, Utm: &dto.LinkUtmDto{Campaign: "spring", Content: "abc-default"}},
+				}, res.Variants)
+				assert.Empty(t, res.Devices)
+			},
+		},
+		{
+			name:  "bot clicks included on request",
+			query: dto.LinkStatsQueryDto{Code: "abc", From: from, To: to, Granularity: dto.GranularityDay, IncludeBots: true},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).Return(&model.ShortLink{Code: "abc"}, nil)
+				return links
+			},
+			setupClicks: func(t *testing.T) *mocks.LinkClick {
+				filter := repository.ClickFilter{Code: "abc", From: from, To: to, IncludeBots: true}
+				clicks := mocks.NewLinkClick(t)
+				clicks.On("CountClicksOverTime", mock.Anything, filter, dto.GranularityDay).
+					Return([]repository.ClickBucket{{Start: from, Clicks: 1}}, nil)
+				clicks.On("CountClicksBy", mock.Anything, filter, repository.ClickFieldDevice).
+					Return([]repository.ClickCount{{Value: "bot", Clicks: 1}}, nil)
+				clicks.On("CountClicksBy", mock.Anything, filter, mock.Anything).
+					Return([]repository.ClickCount{}, nil)
+				return clicks
+			},
+			setupVisitors: func(t *testing.T) *mocks.UniqueVisitor {
+				visitors := mocks.NewUniqueVisitor(t)
+				visitors.On("CountVisitors", mock.Anything, "abc", from, to).Return(int64(0), nil)
+				visitors.On("CountAllTimeVisitors", mock.Anything, "abc").Return(int64(0), nil)
+				return visitors
+			},
+			validateResult: func(t *testing.T, res dto.LinkStatsResponseDto) {
+				assert.True(t, res.IncludeBots)
+				assert.Equal(t, int64(1), res.TotalClicks)
+				assert.Equal(t, []dto.LinkStatsBreakdownDto{{Value: "bot", Clicks: 1}}, res.Devices)
+			},
+		},
+		{
+			name:  "link not owned by user",
+			query: dto.LinkStatsQueryDto{Code: "abc", From: from, To: to, Granularity: dto.GranularityDay},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).Return(nil, gorm.ErrRecordNotFound)
+				return links
+			},
+			setupClicks:   newUnusedLinkClickRepo,
+			expectedError: e.ErrUrlNotFound,
+		},
+		{
+			name:          "empty range",
+			query:         dto.LinkStatsQueryDto{Code: "abc", From: to, To: from, Granularity: dto.GranularityDay},
+			setupLinks:    newUnusedShortLinkRepo,
+			setupClicks:   newUnusedLinkClickRepo,
+			expectedError: e.ErrInvalidStatsRange,
+		},
+		{
+			name:          "too many hourly buckets",
+			query:         dto.LinkStatsQueryDto{Code: "abc", From: from, To: from.AddDate(1, 0, 0), Granularity: dto.GranularityHour},
+			setupLinks:    newUnusedShortLinkRepo,
+			setupClicks:   newUnusedLinkClickRepo,
+			expectedError: e.ErrInvalidStatsRange,
+		},
+		{
+			name:  "click query fails",
+			query: dto.LinkStatsQueryDto{Code: "abc", From: from, To: to, Granularity: dto.GranularityMonth},
+			setupLinks: func(t *testing.T) *mocks.ShortLink {
+				links := mocks.NewShortLink(t)
+				links.On("GetOwnedLink", mock.Anything, "abc", testOwnerID).Return(&model.ShortLink{Code: "abc"}, nil)
+				return links
+			},
+			setupClicks: func(t *testing.T) *mocks.LinkClick {
+				clicks := mocks.NewLinkClick(t)
+				clicks.On("CountClicksOverTime", mock.Anything, mock.Anything, dto.GranularityMonth).Return(nil, assert.AnError)
+				return clicks
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setupVisitors := tc.setupVisitors
+			if setupVisitors == nil {
+				setupVisitors = newUnusedUniqueVisitorRepo
+			}
+			svc := NewLinkStats(tc.setupLinks(t), tc.setupClicks(t), setupVisitors(t))
+
+			res, err := svc.GetStats(t.Context(), testOwnerID, tc.query)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.validateResult != nil {
+				tc.validateResult(t, res)
+			}
+		})
+	}
+}
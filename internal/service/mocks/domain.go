@@ -0,0 +1,177 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+)
+
+// Domain is an autogenerated mock type for the Domain type
+type Domain struct {
+	mock.Mock
+}
+
+// CheckUsable provides a mock function with given fields: ctx, userId, name
+func (_m *Domain) CheckUsable(ctx context.Context, userId string, name string) error {
+	ret := _m.Called(ctx, userId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckUsable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDomain provides a mock function with given fields: ctx, userId, name
+func (_m *Domain) DeleteDomain(ctx context.Context, userId string, name string) error {
+	ret := _m.Called(ctx, userId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDomain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListDomains provides a mock function with given fields: ctx, userId
+func (_m *Domain) ListDomains(ctx context.Context, userId string) (dto.DomainListResponseDto, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDomains")
+	}
+
+	var r0 dto.DomainListResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.DomainListResponseDto, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.DomainListResponseDto); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(dto.DomainListResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: ctx, userId, r
+func (_m *Domain) Register(ctx context.Context, userId string, r dto.DomainRegisterRequestDto) (dto.DomainDto, error) {
+	ret := _m.Called(ctx, userId, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 dto.DomainDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, dto.DomainRegisterRequestDto) (dto.DomainDto, error)); ok {
+		return rf(ctx, userId, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, dto.DomainRegisterRequestDto) dto.DomainDto); ok {
+		r0 = rf(ctx, userId, r)
+	} else {
+		r0 = ret.Get(0).(dto.DomainDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, dto.DomainRegisterRequestDto) error); ok {
+		r1 = rf(ctx, userId, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveHost provides a mock function with given fields: ctx, host
+func (_m *Domain) ResolveHost(ctx context.Context, host string) (string, error) {
+	ret := _m.Called(ctx, host)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveHost")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, host)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, host)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, userId, name
+func (_m *Domain) Verify(ctx context.Context, userId string, name string) (dto.DomainDto, error) {
+	ret := _m.Called(ctx, userId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 dto.DomainDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (dto.DomainDto, error)); ok {
+		return rf(ctx, userId, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) dto.DomainDto); ok {
+		r0 = rf(ctx, userId, name)
+	} else {
+		r0 = ret.Get(0).(dto.DomainDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDomain creates a new instance of Domain. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDomain(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Domain {
+	mock := &Domain{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
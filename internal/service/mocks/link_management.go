@@ -0,0 +1,103 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+)
+
+// LinkManagement is an autogenerated mock type for the LinkManagement type
+type LinkManagement struct {
+	mock.Mock
+}
+
+// DeleteLink provides a mock function with given fields: ctx, userId, code
+func (_m *LinkManagement) DeleteLink(ctx context.Context, userId string, code string) error {
+	ret := _m.Called(ctx, userId, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListLinks provides a mock function with given fields: ctx, userId, q
+func (_m *LinkManagement) ListLinks(ctx context.Context, userId string, q dto.LinkListQueryDto) (dto.LinkListResponseDto, error) {
+	ret := _m.Called(ctx, userId, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLinks")
+	}
+
+	var r0 dto.LinkListResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, dto.LinkListQueryDto) (dto.LinkListResponseDto, error)); ok {
+		return rf(ctx, userId, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, dto.LinkListQueryDto) dto.LinkListResponseDto); ok {
+		r0 = rf(ctx, userId, q)
+	} else {
+		r0 = ret.Get(0).(dto.LinkListResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, dto.LinkListQueryDto) error); ok {
+		r1 = rf(ctx, userId, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLink provides a mock function with given fields: ctx, userId, code, r
+func (_m *LinkManagement) UpdateLink(ctx context.Context, userId string, code string, r dto.LinkUpdateRequestDto) (dto.LinkDto, error) {
+	ret := _m.Called(ctx, userId, code, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLink")
+	}
+
+	var r0 dto.LinkDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, dto.LinkUpdateRequestDto) (dto.LinkDto, error)); ok {
+		return rf(ctx, userId, code, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, dto.LinkUpdateRequestDto) dto.LinkDto); ok {
+		r0 = rf(ctx, userId, code, r)
+	} else {
+		r0 = ret.Get(0).(dto.LinkDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, dto.LinkUpdateRequestDto) error); ok {
+		r1 = rf(ctx, userId, code, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLinkManagement creates a new instance of LinkManagement. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkManagement(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkManagement {
+	mock := &LinkManagement{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
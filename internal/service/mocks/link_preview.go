@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+)
+
+// LinkPreview is an autogenerated mock type for the LinkPreview type
+type LinkPreview struct {
+	mock.Mock
+}
+
+// Preview provides a mock function with given fields: ctx, code
+func (_m *LinkPreview) Preview(ctx context.Context, code string) (dto.LinkPreviewDto, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Preview")
+	}
+
+	var r0 dto.LinkPreviewDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dto.LinkPreviewDto, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) dto.LinkPreviewDto); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(dto.LinkPreviewDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLinkPreview creates a new instance of LinkPreview. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkPreview(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkPreview {
+	mock := &LinkPreview{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+)
+
+// LinkQr is an autogenerated mock type for the LinkQr type
+type LinkQr struct {
+	mock.Mock
+}
+
+// Render provides a mock function with given fields: ctx, q
+func (_m *LinkQr) Render(ctx context.Context, q dto.LinkQrQueryDto) ([]byte, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Render")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LinkQrQueryDto) ([]byte, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LinkQrQueryDto) []byte); ok {
+		r0 = rf(ctx, q)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.LinkQrQueryDto) error); ok {
+		r1 = rf(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLinkQr creates a new instance of LinkQr. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkQr(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkQr {
+	mock := &LinkQr{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+)
+
+// LinkStats is an autogenerated mock type for the LinkStats type
+type LinkStats struct {
+	mock.Mock
+}
+
+// GetStats provides a mock function with given fields: ctx, userId, q
+func (_m *LinkStats) GetStats(ctx context.Context, userId string, q dto.LinkStatsQueryDto) (dto.LinkStatsResponseDto, error) {
+	ret := _m.Called(ctx, userId, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 dto.LinkStatsResponseDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, dto.LinkStatsQueryDto) (dto.LinkStatsResponseDto, error)); ok {
+		return rf(ctx, userId, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, dto.LinkStatsQueryDto) dto.LinkStatsResponseDto); ok {
+		r0 = rf(ctx, userId, q)
+	} else {
+		r0 = ret.Get(0).(dto.LinkStatsResponseDto)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, dto.LinkStatsQueryDto) error); ok {
+		r1 = rf(ctx, userId, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLinkStats creates a new instance of LinkStats. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLinkStats(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LinkStats {
+	mock := &LinkStats{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 	dto "github.com/vincent-tien/bookmark-management/internal/dto"
+
+	service "github.com/vincent-tien/bookmark-management/internal/service"
 )
 
 // UrlShorten is an autogenerated mock type for the UrlShorten type
@@ -14,27 +16,27 @@ type UrlShorten struct {
 	mock.Mock
 }
 
-// GetUrl provides a mock function with given fields: ctx, code
-func (_m *UrlShorten) GetUrl(ctx context.Context, code string) (string, error) {
-	ret := _m.Called(ctx, code)
+// GetUrl provides a mock function with given fields: ctx, r
+func (_m *UrlShorten) GetUrl(ctx context.Context, r dto.LinkRedirectRequestDto) (service.Destination, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUrl")
 	}
 
-	var r0 string
+	var r0 service.Destination
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LinkRedirectRequestDto) (service.Destination, error)); ok {
+		return rf(ctx, r)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LinkRedirectRequestDto) service.Destination); ok {
+		r0 = rf(ctx, r)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(service.Destination)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, code)
+	if rf, ok := ret.Get(1).(func(context.Context, dto.LinkRedirectRequestDto) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,6 +72,36 @@ func (_m *UrlShorten) Shorten(ctx context.Context, r dto.LinkShortenRequestDto)
 	return r0, r1
 }
 
+// ShortenMany provides a mock function with given fields: ctx, rs
+func (_m *UrlShorten) ShortenMany(ctx context.Context, rs []dto.LinkShortenRequestDto) ([]service.ShortenResult, error) {
+	ret := _m.Called(ctx, rs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShortenMany")
+	}
+
+	var r0 []service.ShortenResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.LinkShortenRequestDto) ([]service.ShortenResult, error)); ok {
+		return rf(ctx, rs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.LinkShortenRequestDto) []service.ShortenResult); ok {
+		r0 = rf(ctx, rs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ShortenResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []dto.LinkShortenRequestDto) error); ok {
+		r1 = rf(ctx, rs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUrlShorten creates a new instance of UrlShorten. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUrlShorten(t interface {
@@ -3,96 +3,445 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
 	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
 	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/useragent"
 	"github.com/vincent-tien/bookmark-management/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 const (
-	urlCodeLength    = 8
+	// Number of codes a generator tries before giving up.
 	defaultThreshold = 5
+
+	// A password-protected code is locked for everyone once this many wrong
+	// passwords were tried within the window.
+	passwordAttemptLimit  = 10
+	passwordAttemptWindow = 15 * time.Minute
+
+	// Clients may cache permanent redirects for up to this long.
+	permanentRedirectMaxAge = 24 * time.Hour
 )
 
+// reservedAliases holds aliases that would shadow API paths or are kept for future use.
+// Entries are lower case; aliases are compared case-insensitively.
+var reservedAliases = map[string]struct{}{
+	"admin":        {},
+	"api":          {},
+	"domains":      {},
+	"health-check": {},
+	"links":        {},
+	"self":         {},
+	"swagger":      {},
+	"users":        {},
+	"v1":           {},
+}
+
 //go:generate mockery --name=UrlShorten --filename=url_shorten.go
 
 // UrlShorten defines the interface for URL shortening services.
 // It provides methods to generate short codes and store URL mappings.
 type UrlShorten interface {
 	// Shorten generates a short code for the given URL and stores the mapping.
-	// It returns the generated short code and an error if the operation fails.
+	// It returns the generated short code, an error wrapping destpolicy.ErrRejected if the
+	// destination or a routing destination violates the destination policy, an error wrapping
+	// routing.ErrInvalidRouting for invalid routing, an error wrapping utm.ErrInvalidTemplate for
+	// unknown placeholders in UTM parameters, an error wrapping ErrInvalidSchedule if the link
+	// would never resolve, or an error if the operation fails.
 	Shorten(ctx context.Context, r dto.LinkShortenRequestDto) (string, error)
-	// GetUrl retrieves the original URL associated with the given code.
-	// It returns the original URL and an error if the code is not found or retrieval fails.
-	GetUrl(ctx context.Context, code string) (string, error)
+	// ShortenMany shortens every URL of a bulk request. It returns one result per request,
+	// in request order, each carrying the code or the error Shorten would have returned.
+	// It returns an error only if the batch as a whole could not be stored.
+	ShortenMany(ctx context.Context, rs []dto.LinkShortenRequestDto) ([]ShortenResult, error)
+	// GetUrl retrieves the destination of the link for the visitor following it.
+	// Links with routing send the visitor to the destination of the first matching rule
+	// or of their variant, and UTM parameters of the link are merged into the destination.
+	// Links with an interstitial need the visitor's confirmation, the
+	// password is only checked for password-protected links, and for click-limited links
+	// every successful call uses up one redirect.
+	// It returns the destination and an error if the code is not found, ErrConfirmationRequired
+	// for unconfirmed visits of interstitial links, ErrPasswordRequired, ErrWrongPassword or
+	// ErrTooManyAttempts for protected links, ErrLinkExhausted once a click-limited link has
+	// no redirects left, ErrLinkNotYetActive before the activation time of a scheduled link,
+	// ErrDestinationBlocked if the destination no longer passes the destination policy,
+	// or an error if retrieval fails.
+	GetUrl(ctx context.Context, r dto.LinkRedirectRequestDto) (Destination, error)
+}
+
+// Destination is where a visitor of a short link is sent.
+type Destination struct {
+	Url string
+	// Variant names the rule or variant that chose the URL, routing.DefaultVariant for the link's own target
+	Variant string
+	// Status is the HTTP status of the redirect
+	Status int
+	// MaxAge is how long clients may cache the redirect, 0 if every visit must reach the service
+	MaxAge time.Duration
+	// NoIndex asks search engines not to index the link
+	NoIndex bool
+	// ReferrerPolicy is the Referrer-Policy of the redirect, empty for none
+	ReferrerPolicy string
+}
+
+// ShortenResult is the outcome of one request of a bulk shortening.
+type ShortenResult struct {
+	Code string
+	Err  error
 }
 
 type urlShorten struct {
-	repo repository.UrlStorage
+	repo     repository.UrlStorage
+	codes    CodeGenerator
+	limits   repository.ClickLimit
+	attempts repository.RateLimiter
+	policy   destpolicy.Policy
 }
 
 // NewUrlShorten creates and returns a new URL shortening service instance.
-// It initializes the service with a URL storage repository.
+// It initializes the service with a URL storage repository, the generator of
+// short codes, the redirect counters of click-limited links, the counter of wrong
+// password attempts and the policy destinations are checked against when links
+// are created and followed.
 // Returns a UrlShorten interface implementation.
-func NewUrlShorten(repo repository.UrlStorage) UrlShorten {
+func NewUrlShorten(repo repository.UrlStorage, codes CodeGenerator, limits repository.ClickLimit, attempts repository.RateLimiter, policy destpolicy.Policy) UrlShorten {
 	return &urlShorten{
-		repo: repo,
+		repo:     repo,
+		codes:    codes,
+		limits:   limits,
+		attempts: attempts,
+		policy:   policy,
 	}
 }
 
 // Shorten generates a short code for the given URL and stores the mapping.
-// It creates a random code, checks for duplicates, and stores the URL with expiration.
+// When the request carries an alias it is used as the code, otherwise the code
+// generator hands out a new one. The URL is stored with expiration.
 // Returns the generated short code and an error if the operation fails.
 func (s *urlShorten) Shorten(ctx context.Context, r dto.LinkShortenRequestDto) (string, error) {
-	var code string
-	var err error
-	var foundValidCode bool
+	if err := checkLinkRequest(ctx, s.policy, r); err != nil {
+		return "", err
+	}
+
+	if r.Alias != "" {
+		return s.shortenWithAlias(ctx, r)
+	}
 
-	t := defaultThreshold
+	code, err := s.generate(ctx, r.Domain)
+	if err != nil {
+		return "", err
+	}
 
-	for i := 0; i < t; i++ {
-		code, err = utils.GenerateRandomString(urlCodeLength)
+	if err := s.store(ctx, dto.LinkKey(code, r.Domain), r); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// generate hands out a new code for a link on the domain. Generated codes stay unique
+// across domains, but an alias of the domain may already use the storage key of the code,
+// so on a custom domain the key is reserved as well and taken keys make it draw again.
+// Returns ErrKeyAlreadyExists if no free code was found.
+func (s *urlShorten) generate(ctx context.Context, domain string) (string, error) {
+	for i := 0; i < defaultThreshold; i++ {
+		code, err := s.codes.Generate(ctx)
+		if err != nil || domain == "" {
+			return code, err
+		}
+
+		ok, err := s.codes.Reserve(ctx, dto.LinkKey(code, domain))
 		if err != nil {
+			return "", err
+		}
+		if ok {
+			return code, nil
+		}
+	}
+
+	return "", e.ErrKeyAlreadyExists
+}
+
+// ShortenMany shortens plain requests in one batch: their codes are reserved and
+// their links stored together rather than one round trip per request.
+// Requests with an alias, a click limit, a password or a custom domain are shortened one
+// by one after the batch, so a failing batch does not leave some of them created.
+func (s *urlShorten) ShortenMany(ctx context.Context, rs []dto.LinkShortenRequestDto) ([]ShortenResult, error) {
+	results := make([]ShortenResult, len(rs))
+	var batch, single []int
+	for i, r := range rs {
+		// Codes on a custom domain also need their storage key checked, see generate
+		if r.Alias != "" || r.MaxClicks > 0 || r.Password != "" || r.Domain != "" {
+			single = append(single, i)
 			continue
 		}
+		if err := checkLinkRequest(ctx, s.policy, r); err != nil {
+			results[i].Err = err
+			continue
+		}
+		batch = append(batch, i)
+	}
 
-		exists, err := s.repo.CheckKeyExists(ctx, code)
+	if len(batch) > 0 {
+		codes, err := s.codes.GenerateMany(ctx, len(batch))
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		// If key doesn't exist, we can use this code
-		if !exists {
-			foundValidCode = true
-			break
+		keys := make([]string, len(batch))
+		requests := make([]dto.LinkShortenRequestDto, len(batch))
+		for j, i := range batch {
+			keys[j] = dto.LinkKey(codes[j], rs[i].Domain)
+			requests[j] = rs[i]
+		}
+		if err := s.repo.StoreMany(ctx, keys, requests); err != nil {
+			return nil, err
+		}
+
+		for j, i := range batch {
+			results[i].Code = codes[j]
 		}
 	}
 
-	// If we couldn't find a valid code after all retries
-	if !foundValidCode || code == "" {
-		return "", e.ErrKeyAlreadyExists
+	for _, i := range single {
+		results[i].Code, results[i].Err = s.Shorten(ctx, rs[i])
 	}
 
-	err = s.repo.Store(ctx, code, r)
+	return results, nil
+}
+
+// shortenWithAlias stores the URL under the requested alias, on the custom domain of the request if any.
+// Returns ErrAliasReserved for reserved aliases and ErrAliasTaken if the alias is already in use on the domain.
+func (s *urlShorten) shortenWithAlias(ctx context.Context, r dto.LinkShortenRequestDto) (string, error) {
+	if _, reserved := reservedAliases[strings.ToLower(r.Alias)]; reserved {
+		return "", e.ErrAliasReserved
+	}
+
+	key := dto.LinkKey(r.Alias, r.Domain)
+	reserved, err := s.codes.Reserve(ctx, key)
 	if err != nil {
 		return "", err
 	}
+	if !reserved {
+		return "", e.ErrAliasTaken
+	}
 
-	return code, nil
+	if err := s.store(ctx, key, r); err != nil {
+		return "", err
+	}
+
+	return r.Alias, nil
+}
+
+// store stores the URL mapping under the key with the password hashed. The redirect counter of a
+// click-limited link is set up first, so the link never resolves without its limit in place.
+func (s *urlShorten) store(ctx context.Context, key string, r dto.LinkShortenRequestDto) error {
+	if r.Password != "" {
+		hash, err := utils.HashPassword(r.Password)
+		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return e.ErrPasswordTooLong
+		}
+		if err != nil {
+			return err
+		}
+		r.PasswordHash = hash
+	}
+	if r.MaxClicks > 0 {
+		if err := s.limits.Reset(ctx, key, r.MaxClicks, r.ExpiresAt(time.Now())); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Store(ctx, key, r)
 }
 
-// GetUrl retrieves the original URL associated with the given code.
-// It returns the original URL and an error if the code is not found or retrieval fails.
-func (s *urlShorten) GetUrl(ctx context.Context, code string) (string, error) {
-	url, err := s.repo.GetUrl(ctx, code)
+// GetUrl retrieves the destination of the link with the given code for the visitor.
+// The code of a link on a custom domain is its storage key, see dto.LinkKey.
+// It returns the destination and an error if the code is not found or retrieval fails.
+func (s *urlShorten) GetUrl(ctx context.Context, r dto.LinkRedirectRequestDto) (Destination, error) {
+	link, err := s.repo.GetLink(ctx, r.Code)
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return "", e.ErrUrlNotFound
+		if errors.Is(err, redis.Nil) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return Destination{}, e.ErrUrlNotFound
 		}
-		return "", err
+		return Destination{}, err
+	}
+
+	// Scheduled links reveal nothing about their destination before they go live
+	if link.NotBefore != nil && time.Now().Before(*link.NotBefore) {
+		return Destination{}, e.ErrLinkNotYetActive
+	}
+
+	dest := Destination{Url: link.Target, Variant: routing.DefaultVariant}
+	if url, variant, ok := link.Routing.Choose(newVisitor(r)); ok {
+		dest = Destination{Url: url, Variant: variant}
+	}
+
+	// Lists change after links were created, so every redirect is checked again
+	if err := checkDestination(ctx, s.policy, dest.Url); err != nil {
+		log.Warn().Err(err).Str("code", r.Code).Msg("Blocked redirect to rejected destination")
+		return Destination{}, e.ErrDestinationBlocked
+	}
+
+	if link.Interstitial && !r.Confirmed {
+		return Destination{}, e.ErrConfirmationRequired
+	}
+
+	if link.PasswordHash != "" {
+		if err := s.checkPassword(ctx, link, r.Password, r.IP); err != nil {
+			return Destination{}, err
+		}
+	}
+
+	if link.MaxClicks != nil {
+		taken, err := s.limits.Consume(ctx, r.Code)
+		if err != nil {
+			return Destination{}, err
+		}
+		if !taken {
+			return Destination{}, e.ErrLinkExhausted
+		}
+	}
+
+	code, _ := dto.SplitLinkKey(r.Code)
+	dest.Url = link.Utm.Apply(dest.Url, code, dest.Variant)
+	dest.Status = redirectStatus(link)
+	dest.MaxAge = redirectMaxAge(link, time.Now())
+	dest.NoIndex = link.NoIndex
+	dest.ReferrerPolicy = link.ReferrerPolicy
+	return dest, nil
+}
+
+// redirectStatus returns the HTTP status of the link's redirects, 302 unless the owner chose another.
+func redirectStatus(link *model.ShortLink) int {
+	if link.RedirectStatus == 0 {
+		return http.StatusFound
+	}
+
+	return link.RedirectStatus
+}
+
+// redirectMaxAge returns how long clients may cache a redirect of the link. Only permanent
+// redirects are cached, and only if every visit would be answered the same way: links that
+// count their redirects, ask for a password or route visitors elsewhere must reach the
+// service each time. The cache never outlives the expiry of the link.
+func redirectMaxAge(link *model.ShortLink, now time.Time) time.Duration {
+	status := redirectStatus(link)
+	if status != http.StatusMovedPermanently && status != http.StatusPermanentRedirect {
+		return 0
+	}
+	if link.MaxClicks != nil || link.PasswordHash != "" || link.Interstitial || !link.Routing.IsEmpty() {
+		return 0
+	}
+
+	maxAge := permanentRedirectMaxAge
+	if link.ExpiresAt != nil {
+		maxAge = min(maxAge, link.ExpiresAt.Sub(now).Truncate(time.Second))
+	}
+
+	return max(maxAge, 0)
+}
+
+// newVisitor collects what routing rules match against. The visitor key keeps
+// repeated visits of the same client in the same variant of a weighted split.
+func newVisitor(r dto.LinkRedirectRequestDto) routing.Visitor {
+	return routing.Visitor{
+		Device:         useragent.Parse(r.UserAgent).Device,
+		AcceptLanguage: r.AcceptLanguage,
+		Query:          r.Query,
+		Key:            r.IP + "|" + r.UserAgent,
+	}
+}
+
+// checkPassword verifies the password of a protected link. Attempts are counted per code
+// and client before the password is hashed, so concurrent guesses cannot get past the limit
+// and refused attempts cost no bcrypt work; a correct password clears the count. Counting
+// per client keeps one visitor from locking everybody else out, at the cost of letting a
+// guesser with many addresses try more often.
+func (s *urlShorten) checkPassword(ctx context.Context, link *model.ShortLink, password, client string) error {
+	if password == "" {
+		return e.ErrPasswordRequired
+	}
+
+	key := "link_password:" + link.Code + ":" + client
+	attempts, _, err := s.attempts.Hit(ctx, key, passwordAttemptWindow)
+	if err != nil {
+		return err
+	}
+	if attempts > passwordAttemptLimit {
+		return e.ErrTooManyAttempts
+	}
+
+	if !utils.VerifyPassword(password, link.PasswordHash) {
+		return e.ErrWrongPassword
+	}
+
+	if err := s.attempts.Reset(ctx, key); err != nil {
+		log.Warn().Err(err).Str("code", link.Code).Msg("Failed to reset password attempts")
+	}
+	return nil
+}
+
+// checkLinkRequest validates the schedule, the routing and the UTM parameters of the request
+// and checks the target and every routing destination against the policy.
+func checkLinkRequest(ctx context.Context, policy destpolicy.Policy, r dto.LinkShortenRequestDto) error {
+	if err := checkSchedule(r.NotBefore, r.NotAfter, time.Now()); err != nil {
+		return err
+	}
+	routes := r.Routing.ToRouting()
+	if err := routes.Validate(); err != nil {
+		return err
+	}
+	if err := r.Utm.ToParams().Validate(); err != nil {
+		return err
+	}
+
+	for _, url := range append([]string{r.Url}, routes.Urls()...) {
+		if err := checkDestination(ctx, policy, url); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSchedule checks that a link ending at notAfter can still resolve: the end must
+// lie in the future and after the activation time. A nil notAfter always passes.
+// Returned errors wrap ErrInvalidSchedule.
+func checkSchedule(notBefore, notAfter *time.Time, now time.Time) error {
+	if notAfter == nil {
+		return nil
+	}
+	if !notAfter.After(now) {
+		return fmt.Errorf("%w: not_after must be in the future", e.ErrInvalidSchedule)
+	}
+	if notBefore != nil && !notAfter.After(*notBefore) {
+		return fmt.Errorf("%w: not_after must be later than not_before", e.ErrInvalidSchedule)
+	}
+
+	return nil
+}
+
+// checkDestination checks the destination against the policy. Only rejections are
+// returned; when the reputation check cannot be completed the destination is allowed,
+// so an outage of the reputation service does not take short links down with it.
+func checkDestination(ctx context.Context, policy destpolicy.Policy, target string) error {
+	err := policy.Check(ctx, target)
+	if err == nil || errors.Is(err, destpolicy.ErrRejected) {
+		return err
 	}
 
-	return url, nil
+	log.Warn().Err(err).Str("url", target).Msg("Destination check incomplete, allowing destination")
+	return nil
 }
@@ -1,21 +1,60 @@
 package service
 
 import (
+	"context"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
 	e "github.com/vincent-tien/bookmark-management/internal/errors"
+	"github.com/vincent-tien/bookmark-management/internal/model"
 	"github.com/vincent-tien/bookmark-management/internal/repository/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy"
+	policyMocks "github.com/vincent-tien/bookmark-management/pkg/destpolicy/mocks"
+	"github.com/vincent-tien/bookmark-management/pkg/routing"
+	"github.com/vincent-tien/bookmark-management/pkg/utils"
+	"github.com/vincent-tien/bookmark-management/pkg/utm"
+	"gorm.io/gorm"
 )
 
+// testPolicy is the destination policy used by service tests; it blocks evil.example
+var testPolicy = destpolicy.New(destpolicy.Config{
+	SelfHosts: []string{"sho.rt"},
+	Blocklist: []string{"evil.example"},
+})
+
+// newTestCodes returns the default code generator with reservations that always
+// succeed, so collisions are decided by the storage mock
+func newTestCodes(t *testing.T, storage *mocks.UrlStorage) CodeGenerator {
+	reservations := mocks.NewCodeReservation(t)
+	reservations.On("Reserve", mock.Anything, mock.Anything, codeReservationTTL).Return(true, nil).Maybe()
+	reservations.On("ReserveMany", mock.Anything, mock.Anything, codeReservationTTL).
+		Return(func(_ context.Context, codes []string, _ time.Duration) ([]bool, error) {
+			reserved := make([]bool, len(codes))
+			for i := range reserved {
+				reserved[i] = true
+			}
+			return reserved, nil
+		}).Maybe()
+
+	codes, err := NewCodeGenerator(CodeGeneratorConfig{}, storage, reservations)
+	require.NoError(t, err)
+
+	return codes
+}
+
 func TestUrlShorten_Shorten(t *testing.T) {
 	t.Parallel()
 
 	var testCases = []struct {
 		name                    string
 		setupMockUrlStorageRepo func() *mocks.UrlStorage
+		setupMockClickLimit     func() *mocks.ClickLimit
 		request                 dto.LinkShortenRequestDto
 		expectedError           error
 		validateResult          func(t *testing.T, code string, err error)
@@ -86,6 +125,254 @@ func TestUrlShorten_Shorten(t *testing.T) {
 			expectedError:  assert.AnError,
 			validateResult: nil,
 		},
+		{
+			name: "generated code taken by an alias of the domain is drawn again",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				onDomain := mock.MatchedBy(func(key string) bool {
+					return strings.HasSuffix(key, "@go.example.com")
+				})
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, mock.MatchedBy(func(code string) bool {
+					return len(code) == 8
+				})).Return(false, nil).Twice()
+				mockStorage.On("CheckKeyExists", mock.Anything, onDomain).Return(true, nil).Once()
+				mockStorage.On("CheckKeyExists", mock.Anything, onDomain).Return(false, nil).Once()
+				mockStorage.On("Store", mock.Anything, onDomain, mock.Anything).Return(nil).Once()
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Domain:       "go.example.com",
+			},
+			validateResult: func(t *testing.T, code string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, code, 8)
+			},
+		},
+		{
+			name: "password longer than 72 bytes",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, mock.MatchedBy(func(code string) bool {
+					return len(code) == 8
+				})).Return(false, nil)
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				// 30 characters, 90 bytes
+				Password: strings.Repeat("密", 30),
+			},
+			expectedError:  e.ErrPasswordTooLong,
+			validateResult: nil,
+		},
+		{
+			name: "alias success",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "my-launch").Return(false, nil)
+				mockStorage.On("Store", mock.Anything, "my-launch", mock.Anything).Return(nil)
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Alias:        "my-launch",
+			},
+			expectedError: nil,
+			validateResult: func(t *testing.T, code string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "my-launch", code)
+			},
+		},
+		{
+			name: "alias already taken",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "my-launch").Return(true, nil)
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Alias:        "my-launch",
+			},
+			expectedError:  e.ErrAliasTaken,
+			validateResult: nil,
+		},
+		{
+			name: "alias is reserved regardless of case",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				return mocks.NewUrlStorage(t)
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Alias:        "Swagger",
+			},
+			expectedError:  e.ErrAliasReserved,
+			validateResult: nil,
+		},
+		{
+			name: "click-limited link sets up its counter",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "one-time").Return(false, nil)
+				mockStorage.On("Store", mock.Anything, "one-time", mock.Anything).Return(nil)
+
+				return mockStorage
+			},
+			setupMockClickLimit: func() *mocks.ClickLimit {
+				mockLimit := mocks.NewClickLimit(t)
+				mockLimit.On("Reset", mock.Anything, "one-time", int64(1), mock.MatchedBy(func(expiresAt *time.Time) bool {
+					return expiresAt != nil && time.Until(*expiresAt) > 59*time.Minute
+				})).Return(nil).Once()
+
+				return mockLimit
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Alias:        "one-time",
+				MaxClicks:    1,
+			},
+			expectedError: nil,
+			validateResult: func(t *testing.T, code string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "one-time", code)
+			},
+		},
+		{
+			name: "click counter setup fails before storing",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "one-time").Return(false, nil)
+
+				return mockStorage
+			},
+			setupMockClickLimit: func() *mocks.ClickLimit {
+				mockLimit := mocks.NewClickLimit(t)
+				mockLimit.On("Reset", mock.Anything, "one-time", int64(1), mock.Anything).Return(assert.AnError)
+
+				return mockLimit
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:       "https://example.com",
+				Alias:     "one-time",
+				MaxClicks: 1,
+			},
+			expectedError:  assert.AnError,
+			validateResult: nil,
+		},
+		{
+			name: "password is stored hashed",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "secret").Return(false, nil)
+				mockStorage.On("Store", mock.Anything, "secret", mock.MatchedBy(func(r dto.LinkShortenRequestDto) bool {
+					return r.PasswordHash != "open-sesame" && utils.VerifyPassword("open-sesame", r.PasswordHash)
+				})).Return(nil).Once()
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:      "https://example.com",
+				Alias:    "secret",
+				Password: "open-sesame",
+			},
+			expectedError: nil,
+			validateResult: func(t *testing.T, code string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "secret", code)
+			},
+		},
+		{
+			name: "javascript destination is rejected",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				return mocks.NewUrlStorage(t)
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:   "javascript:alert(document.cookie)",
+				Alias: "my-launch",
+			},
+			expectedError:  destpolicy.ErrSchemeNotAllowed,
+			validateResult: nil,
+		},
+		{
+			name: "private address destination is rejected",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				return mocks.NewUrlStorage(t)
+			},
+			request: dto.LinkShortenRequestDto{
+				Url: "http://169.254.169.254/latest/meta-data",
+			},
+			expectedError:  destpolicy.ErrPrivateAddress,
+			validateResult: nil,
+		},
+		{
+			name: "link to the shortener itself is rejected",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				return mocks.NewUrlStorage(t)
+			},
+			request: dto.LinkShortenRequestDto{
+				Url: "https://sho.rt/v1/links/redirect/abc",
+			},
+			expectedError:  destpolicy.ErrSelfLink,
+			validateResult: nil,
+		},
+		{
+			name: "blocked destination is rejected",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				return mocks.NewUrlStorage(t)
+			},
+			request: dto.LinkShortenRequestDto{
+				Url: "https://login.evil.example/",
+			},
+			expectedError:  destpolicy.ErrDomainBlocked,
+			validateResult: nil,
+		},
+		{
+			name: "alias on a custom domain",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "my-launch@go.example.com").Return(false, nil)
+				mockStorage.On("Store", mock.Anything, "my-launch@go.example.com", mock.Anything).Return(nil)
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Alias:        "my-launch",
+				Domain:       "go.example.com",
+			},
+			validateResult: func(t *testing.T, code string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "my-launch", code)
+			},
+		},
+		{
+			name: "alias existence check fails",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeyExists", mock.Anything, "my-launch").Return(false, assert.AnError)
+
+				return mockStorage
+			},
+			request: dto.LinkShortenRequestDto{
+				Url:          "https://example.com",
+				ExpInSeconds: 3600,
+				Alias:        "my-launch",
+			},
+			expectedError:  assert.AnError,
+			validateResult: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -94,7 +381,11 @@ func TestUrlShorten_Shorten(t *testing.T) {
 			t.Parallel()
 
 			mockStorage := tc.setupMockUrlStorageRepo()
-			service := NewUrlShorten(mockStorage)
+			mockLimit := mocks.NewClickLimit(t)
+			if tc.setupMockClickLimit != nil {
+				mockLimit = tc.setupMockClickLimit()
+			}
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mockLimit, mocks.NewRateLimiter(t), testPolicy)
 
 			ctx := t.Context()
 			code, err := service.Shorten(ctx, tc.request)
@@ -106,19 +397,209 @@ func TestUrlShorten_Shorten(t *testing.T) {
 func TestUrlShorten_GetUrl(t *testing.T) {
 	t.Parallel()
 
+	maxClicks := int64(1)
+	passwordHash, err := utils.HashPassword("open-sesame")
+	require.NoError(t, err)
+	protected := func() *mocks.UrlStorage {
+		mockStorage := mocks.NewUrlStorage(t)
+		mockStorage.On("GetLink", mock.Anything, "12345678").
+			Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", PasswordHash: passwordHash}, nil)
+
+		return mockStorage
+	}
+
 	testCases := []struct {
 		name                    string
+		password                string
+		confirmed               bool
 		setupMockUrlStorageRepo func() *mocks.UrlStorage
+		setupMockClickLimit     func() *mocks.ClickLimit
+		setupMockRateLimiter    func() *mocks.RateLimiter
 		validateResult          func(t *testing.T, code string, err error)
 	}{
 		{
 			name: "normal case",
 			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
 				mockStorage := mocks.NewUrlStorage(t)
-				//// Mock CheckKeyExists to return false (key doesn't exist)
-				mockStorage.On("GetUrl", mock.Anything, mock.MatchedBy(func(code string) bool {
+				mockStorage.On("GetLink", mock.Anything, mock.MatchedBy(func(code string) bool {
 					return len(code) == 8
-				})).Return("https://google.com", nil)
+				})).Return(&model.ShortLink{Code: "12345678", Target: "https://google.com"}, nil)
+
+				return mockStorage
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "https://google.com", url)
+			},
+		},
+		{
+			name: "not found in database",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").Return(nil, gorm.ErrRecordNotFound)
+
+				return mockStorage
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrUrlNotFound)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name: "click-limited link uses up a redirect",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", MaxClicks: &maxClicks}, nil)
+
+				return mockStorage
+			},
+			setupMockClickLimit: func() *mocks.ClickLimit {
+				mockLimit := mocks.NewClickLimit(t)
+				mockLimit.On("Consume", mock.Anything, "12345678").Return(true, nil).Once()
+
+				return mockLimit
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "https://google.com", url)
+			},
+		},
+		{
+			name: "click-limited link is exhausted",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", MaxClicks: &maxClicks}, nil)
+
+				return mockStorage
+			},
+			setupMockClickLimit: func() *mocks.ClickLimit {
+				mockLimit := mocks.NewClickLimit(t)
+				mockLimit.On("Consume", mock.Anything, "12345678").Return(false, nil)
+
+				return mockLimit
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrLinkExhausted)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name:                    "protected link with correct password",
+			password:                "open-sesame",
+			setupMockUrlStorageRepo: protected,
+			setupMockRateLimiter: func() *mocks.RateLimiter {
+				mockLimiter := mocks.NewRateLimiter(t)
+				mockLimiter.On("Hit", mock.Anything, "link_password:12345678:203.0.113.7", passwordAttemptWindow).
+					Return(int64(3), passwordAttemptWindow, nil).Once()
+				mockLimiter.On("Reset", mock.Anything, "link_password:12345678:203.0.113.7").Return(nil).Once()
+
+				return mockLimiter
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "https://google.com", url)
+			},
+		},
+		{
+			name:                    "protected link without password",
+			setupMockUrlStorageRepo: protected,
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrPasswordRequired)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name:                    "protected link with wrong password",
+			password:                "guess",
+			setupMockUrlStorageRepo: protected,
+			setupMockRateLimiter: func() *mocks.RateLimiter {
+				mockLimiter := mocks.NewRateLimiter(t)
+				mockLimiter.On("Hit", mock.Anything, "link_password:12345678:203.0.113.7", passwordAttemptWindow).
+					Return(int64(1), passwordAttemptWindow, nil).Once()
+
+				return mockLimiter
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrWrongPassword)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name:                    "protected link locked after too many wrong passwords",
+			password:                "open-sesame",
+			setupMockUrlStorageRepo: protected,
+			setupMockRateLimiter: func() *mocks.RateLimiter {
+				mockLimiter := mocks.NewRateLimiter(t)
+				// Refused before the password is checked, even a correct one
+				mockLimiter.On("Hit", mock.Anything, "link_password:12345678:203.0.113.7", passwordAttemptWindow).
+					Return(int64(passwordAttemptLimit+1), passwordAttemptWindow, nil).Once()
+
+				return mockLimiter
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrTooManyAttempts)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name:                    "protected link while attempts cannot be counted",
+			password:                "open-sesame",
+			setupMockUrlStorageRepo: protected,
+			setupMockRateLimiter: func() *mocks.RateLimiter {
+				mockLimiter := mocks.NewRateLimiter(t)
+				mockLimiter.On("Hit", mock.Anything, mock.Anything, passwordAttemptWindow).Return(int64(0), time.Duration(0), assert.AnError).Once()
+
+				return mockLimiter
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name:     "protected click-limited link keeps its budget on wrong password",
+			password: "guess",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", MaxClicks: &maxClicks, PasswordHash: passwordHash}, nil)
+
+				return mockStorage
+			},
+			setupMockRateLimiter: func() *mocks.RateLimiter {
+				mockLimiter := mocks.NewRateLimiter(t)
+				mockLimiter.On("Hit", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), passwordAttemptWindow, nil)
+
+				return mockLimiter
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrWrongPassword)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name: "interstitial link without confirmation",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", MaxClicks: &maxClicks, Interstitial: true}, nil)
+
+				return mockStorage
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrConfirmationRequired)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name:      "interstitial link with confirmation",
+			confirmed: true,
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", Interstitial: true}, nil)
 
 				return mockStorage
 			},
@@ -127,6 +608,40 @@ func TestUrlShorten_GetUrl(t *testing.T) {
 				assert.Equal(t, "https://google.com", url)
 			},
 		},
+		{
+			name: "destination blocked after the link was created",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://evil.example/login", MaxClicks: &maxClicks}, nil)
+
+				return mockStorage
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, e.ErrDestinationBlocked)
+				assert.Empty(t, url)
+			},
+		},
+		{
+			name: "click counter fails",
+			setupMockUrlStorageRepo: func() *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("GetLink", mock.Anything, "12345678").
+					Return(&model.ShortLink{Code: "12345678", Target: "https://google.com", MaxClicks: &maxClicks}, nil)
+
+				return mockStorage
+			},
+			setupMockClickLimit: func() *mocks.ClickLimit {
+				mockLimit := mocks.NewClickLimit(t)
+				mockLimit.On("Consume", mock.Anything, "12345678").Return(false, assert.AnError)
+
+				return mockLimit
+			},
+			validateResult: func(t *testing.T, url string, err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.Empty(t, url)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -135,14 +650,372 @@ func TestUrlShorten_GetUrl(t *testing.T) {
 			t.Parallel()
 
 			mockStorage := tc.setupMockUrlStorageRepo()
-			service := NewUrlShorten(mockStorage)
+			mockLimit := mocks.NewClickLimit(t)
+			if tc.setupMockClickLimit != nil {
+				mockLimit = tc.setupMockClickLimit()
+			}
+			mockLimiter := mocks.NewRateLimiter(t)
+			if tc.setupMockRateLimiter != nil {
+				mockLimiter = tc.setupMockRateLimiter()
+			}
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mockLimit, mockLimiter, testPolicy)
 
 			ctx := t.Context()
 
 			code := "12345678"
-			url, err := service.GetUrl(ctx, code)
+			dest, err := service.GetUrl(ctx, dto.LinkRedirectRequestDto{Code: code, Password: tc.password, Confirmed: tc.confirmed, IP: "203.0.113.7"})
+
+			tc.validateResult(t, dest.Url, err)
+		})
+	}
+}
+
+func TestUrlShorten_GetUrlRouting(t *testing.T) {
+	t.Parallel()
+
+	link := &model.ShortLink{Code: "routed", Target: "https://example.com", Routing: &routing.Routing{
+		Rules: []routing.Rule{
+			{Name: "german-mobile", Url: "https://m.example.de", Device: "mobile", Language: "de"},
+			{Name: "phish", Url: "https://evil.example/phish", Query: "src", QueryValue: "ad"},
+		},
+		Variants: []routing.Variant{{Name: "b", Url: "https://example.com/b", Weight: 1}},
+	}}
+	iphone := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"
+
+	testCases := []struct {
+		name         string
+		link         *model.ShortLink
+		request      dto.LinkRedirectRequestDto
+		expectedDest Destination
+		expectedErr  error
+	}{
+		{
+			name:         "matching rule",
+			link:         link,
+			request:      dto.LinkRedirectRequestDto{Code: "routed", UserAgent: iphone, AcceptLanguage: "de-DE,en;q=0.5"},
+			expectedDest: Destination{Url: "https://m.example.de", Variant: "german-mobile", Status: http.StatusFound},
+		},
+		{
+			name:         "no rule matches",
+			link:         link,
+			request:      dto.LinkRedirectRequestDto{Code: "routed", UserAgent: iphone, AcceptLanguage: "en"},
+			expectedDest: Destination{Url: "https://example.com/b", Variant: "b", Status: http.StatusFound},
+		},
+		{
+			name:        "rejected routing destination",
+			link:        link,
+			request:     dto.LinkRedirectRequestDto{Code: "routed", Query: map[string][]string{"src": {"ad"}}},
+			expectedErr: e.ErrDestinationBlocked,
+		},
+		{
+			name: "utm parameters name the variant",
+			link: &model.ShortLink{Code: "routed", Target: "https://example.com", Routing: link.Routing,
+				Utm: &utm.Params{Source: "newsletter", Content: "{code}-{variant}"}},
+			request:      dto.LinkRedirectRequestDto{Code: "routed", UserAgent: iphone, AcceptLanguage: "de"},
+			expectedDest: Destination{Url: "https://m.example.de?utm_content=routed-german-mobile&utm_source=newsletter", Variant: "german-mobile", Status: http.StatusFound},
+		},
+		{
+			name:         "without routing",
+			link:         &model.ShortLink{Code: "routed", Target: "https://example.com"},
+			request:      dto.LinkRedirectRequestDto{Code: "routed", UserAgent: iphone, AcceptLanguage: "de"},
+			expectedDest: Destination{Url: "https://example.com", Variant: routing.DefaultVariant, Status: http.StatusFound},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorage := mocks.NewUrlStorage(t)
+			mockStorage.On("GetLink", mock.Anything, "routed").Return(tc.link, nil)
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), testPolicy)
+
+			dest, err := service.GetUrl(t.Context(), tc.request)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedDest, dest)
+		})
+	}
+}
+
+func TestUrlShorten_GetUrlRedirectOptions(t *testing.T) {
+	t.Parallel()
+
+	maxClicks := int64(5)
+	inTenMinutes := time.Now().Add(10 * time.Minute)
 
-			tc.validateResult(t, url, err)
+	testCases := []struct {
+		name           string
+		link           *model.ShortLink
+		expectedStatus int
+		expectedMaxAge time.Duration
+	}{
+		{
+			name:           "default redirect is not cached",
+			link:           &model.ShortLink{},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			name:           "temporary redirect is not cached",
+			link:           &model.ShortLink{RedirectStatus: http.StatusTemporaryRedirect},
+			expectedStatus: http.StatusTemporaryRedirect,
+		},
+		{
+			name:           "permanent redirect is cached",
+			link:           &model.ShortLink{RedirectStatus: http.StatusMovedPermanently},
+			expectedStatus: http.StatusMovedPermanently,
+			expectedMaxAge: permanentRedirectMaxAge,
+		},
+		{
+			name:           "permanent redirect is cached until the link expires",
+			link:           &model.ShortLink{RedirectStatus: http.StatusPermanentRedirect, ExpiresAt: &inTenMinutes},
+			expectedStatus: http.StatusPermanentRedirect,
+			expectedMaxAge: 9 * time.Minute,
+		},
+		{
+			name:           "click-limited permanent redirect is not cached",
+			link:           &model.ShortLink{RedirectStatus: http.StatusMovedPermanently, MaxClicks: &maxClicks},
+			expectedStatus: http.StatusMovedPermanently,
+		},
+		{
+			name: "routed permanent redirect is not cached",
+			link: &model.ShortLink{RedirectStatus: http.StatusMovedPermanently, Routing: &routing.Routing{
+				Variants: []routing.Variant{{Name: "b", Url: "https://example.com/b", Weight: 1}},
+			}},
+			expectedStatus: http.StatusMovedPermanently,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.link.Code = "opts"
+			tc.link.Target = "https://example.com"
+			tc.link.NoIndex = true
+			tc.link.ReferrerPolicy = "no-referrer"
+
+			mockStorage := mocks.NewUrlStorage(t)
+			mockStorage.On("GetLink", mock.Anything, "opts").Return(tc.link, nil)
+			limits := mocks.NewClickLimit(t)
+			if tc.link.MaxClicks != nil {
+				limits.On("Consume", mock.Anything, "opts").Return(true, nil)
+			}
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), limits, mocks.NewRateLimiter(t), testPolicy)
+
+			dest, err := service.GetUrl(t.Context(), dto.LinkRedirectRequestDto{Code: "opts"})
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, dest.Status)
+			if tc.link.ExpiresAt != nil {
+				assert.InDelta(t, tc.expectedMaxAge, dest.MaxAge, float64(time.Minute))
+			} else {
+				assert.Equal(t, tc.expectedMaxAge, dest.MaxAge)
+			}
+			assert.True(t, dest.NoIndex)
+			assert.Equal(t, "no-referrer", dest.ReferrerPolicy)
+		})
+	}
+}
+
+func TestUrlShorten_GetUrlSchedule(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		notBefore   *time.Time
+		expectedUrl string
+		expectedErr error
+	}{
+		{name: "link without schedule", expectedUrl: "https://example.com"},
+		{name: "link after its activation time", notBefore: &past, expectedUrl: "https://example.com"},
+		{name: "link before its activation time", notBefore: &future, expectedErr: e.ErrLinkNotYetActive},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorage := mocks.NewUrlStorage(t)
+			mockStorage.On("GetLink", mock.Anything, "launch").
+				Return(&model.ShortLink{Code: "launch", Target: "https://example.com", NotBefore: tc.notBefore}, nil)
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), testPolicy)
+
+			dest, err := service.GetUrl(t.Context(), dto.LinkRedirectRequestDto{Code: "launch"})
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedUrl, dest.Url)
+		})
+	}
+}
+
+func TestUrlShorten_ShortenOptions(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour)
+	launch := time.Now().Add(24 * time.Hour)
+	end := launch.Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		routing     *dto.LinkRoutingDto
+		utm         *dto.LinkUtmDto
+		notBefore   *time.Time
+		notAfter    *time.Time
+		expectedErr error
+	}{
+		{
+			name: "valid routing is stored",
+			routing: &dto.LinkRoutingDto{
+				Rules:    []dto.LinkRuleDto{{Name: "mobile", Url: "https://m.example.com", Device: "mobile"}},
+				Variants: []dto.LinkVariantDto{{Name: "b", Url: "https://example.com/b", Weight: 50}},
+			},
+		},
+		{
+			name:        "invalid routing",
+			routing:     &dto.LinkRoutingDto{Rules: []dto.LinkRuleDto{{Name: "all", Url: "https://m.example.com"}}},
+			expectedErr: routing.ErrInvalidRouting,
+		},
+		{
+			name:        "unknown utm placeholder",
+			utm:         &dto.LinkUtmDto{Campaign: "{campaign}"},
+			expectedErr: utm.ErrInvalidTemplate,
+		},
+		{
+			name:      "valid schedule is stored",
+			notBefore: &launch,
+			notAfter:  &end,
+		},
+		{
+			name:        "not_after before not_before",
+			notBefore:   &end,
+			notAfter:    &launch,
+			expectedErr: e.ErrInvalidSchedule,
+		},
+		{
+			name:        "not_after in the past",
+			notAfter:    &past,
+			expectedErr: e.ErrInvalidSchedule,
+		},
+		{
+			name:        "rejected variant destination",
+			routing:     &dto.LinkRoutingDto{Variants: []dto.LinkVariantDto{{Name: "b", Url: "https://evil.example/b", Weight: 1}}},
+			expectedErr: destpolicy.ErrRejected,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorage := mocks.NewUrlStorage(t)
+			r := dto.LinkShortenRequestDto{Url: "https://example.com", Alias: "routed", Routing: tc.routing, Utm: tc.utm,
+				NotBefore: tc.notBefore, NotAfter: tc.notAfter}
+			if tc.expectedErr == nil {
+				mockStorage.On("CheckKeyExists", mock.Anything, "routed").Return(false, nil).Once()
+				mockStorage.On("Store", mock.Anything, "routed", r).Return(nil).Once()
+			}
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), testPolicy)
+
+			_, err := service.Shorten(t.Context(), r)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestUrlShorten_ShortenMany(t *testing.T) {
+	t.Parallel()
+
+	requests := []dto.LinkShortenRequestDto{
+		{Url: "https://example.com/a", ExpInSeconds: 3600},
+		{Url: "https://evil.example/phish", ExpInSeconds: 3600},
+		{Url: "https://example.com/b", Alias: "my-launch"},
+		{Url: "https://example.com/c", ExpInSeconds: 3600},
+	}
+	// Plain requests are stored together, in request order
+	isBatch := mock.MatchedBy(func(rs []dto.LinkShortenRequestDto) bool {
+		return len(rs) == 2 && rs[0].Url == "https://example.com/a" && rs[1].Url == "https://example.com/c"
+	})
+
+	testCases := []struct {
+		name            string
+		setupMock       func(t *testing.T) *mocks.UrlStorage
+		expectedResults []ShortenResult
+		expectErr       error
+	}{
+		{
+			name: "plain requests are batched and the others shortened one by one",
+			setupMock: func(t *testing.T) *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeysExist", mock.Anything, mock.Anything).Return(map[string]bool{}, nil).Once()
+				mockStorage.On("StoreMany", mock.Anything, mock.Anything, isBatch).Return(nil).Once()
+				mockStorage.On("CheckKeyExists", mock.Anything, "my-launch").Return(false, nil).Once()
+				mockStorage.On("Store", mock.Anything, "my-launch", mock.Anything).Return(nil).Once()
+				return mockStorage
+			},
+		},
+		{
+			name: "failing batch creates nothing",
+			setupMock: func(t *testing.T) *mocks.UrlStorage {
+				mockStorage := mocks.NewUrlStorage(t)
+				mockStorage.On("CheckKeysExist", mock.Anything, mock.Anything).Return(map[string]bool{}, nil).Once()
+				mockStorage.On("StoreMany", mock.Anything, mock.Anything, isBatch).Return(assert.AnError).Once()
+				return mockStorage
+			},
+			expectErr: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorage := tc.setupMock(t)
+			service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), testPolicy)
+
+			results, err := service.ShortenMany(t.Context(), requests)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, len(requests))
+			assert.Len(t, results[0].Code, defaultCodeLength)
+			assert.NoError(t, results[0].Err)
+			assert.Empty(t, results[1].Code)
+			assert.ErrorIs(t, results[1].Err, destpolicy.ErrRejected)
+			assert.Equal(t, ShortenResult{Code: "my-launch"}, results[2])
+			assert.Len(t, results[3].Code, defaultCodeLength)
+			assert.NotEqual(t, results[0].Code, results[3].Code)
 		})
 	}
 }
+
+func TestUrlShorten_IncompleteDestinationCheck(t *testing.T) {
+	t.Parallel()
+
+	// An unavailable reputation service must not stop links from being created or followed
+	policy := policyMocks.NewPolicy(t)
+	policy.On("Check", mock.Anything, "https://example.com").Return(assert.AnError).Twice()
+
+	mockStorage := mocks.NewUrlStorage(t)
+	mockStorage.On("CheckKeyExists", mock.Anything, "my-launch").Return(false, nil)
+	mockStorage.On("Store", mock.Anything, "my-launch", mock.Anything).Return(nil)
+	mockStorage.On("GetLink", mock.Anything, "my-launch").
+		Return(&model.ShortLink{Code: "my-launch", Target: "https://example.com"}, nil)
+
+	service := NewUrlShorten(mockStorage, newTestCodes(t, mockStorage), mocks.NewClickLimit(t), mocks.NewRateLimiter(t), policy)
+
+	code, err := service.Shorten(t.Context(), dto.LinkShortenRequestDto{Url: "https://example.com", Alias: "my-launch"})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-launch", code)
+
+	dest, err := service.GetUrl(t.Context(), dto.LinkRedirectRequestDto{Code: code})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", dest.Url)
+}
@@ -58,7 +58,10 @@ func NewUserService(repo repository.User, jwtGen jwtUtils.JwtGenerator) User {
 
 func (u *user) Register(ctx context.Context, r dto.RegisterRequestDto) (dto.RegisterResponseDto, error) {
 	// Hash the password
-	hashedPassword := utils.HashPassword(r.Password)
+	hashedPassword, err := utils.HashPassword(r.Password)
+	if err != nil {
+		return dto.RegisterResponseDto{}, err
+	}
 
 	// Create user model
 	userModel := &model.User{
@@ -0,0 +1,225 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/routers"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+)
+
+func TestDomainEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupTestHttp  func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder
+		expectedStatus int
+		validateResp   func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "verified domain serves its own link under a code taken on the shared host",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				domain := registerTestDomain(t, setup, owner.ID, "go.example.com")
+				setup.mockResolver.On("LookupTXT", mock.Anything, domain.TxtRecordName).Return([]string{domain.TxtRecordValue}, nil).Once()
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				rec := executeRequestWithAuth(setup.app, http.MethodPost, getDomainVerifyEndpoint("go.example.com"), "", testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				// The same alias on the shared host and on the custom domain
+				rec = executeRequest(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org","alias":"launch"}`)
+				require.Equal(t, http.StatusCreated, rec.Code)
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				rec = executeRequestWithAuth(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://go.dev","alias":"launch","domain":"go.example.com"}`, testLinkToken)
+				require.Equal(t, http.StatusCreated, rec.Code)
+				var created dto.LinkShortenResponseDto
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+				assert.Equal(t, "launch", created.Code)
+				assert.Equal(t, "https://go.example.com/launch", created.ShortUrl)
+
+				shared := executeRequest(setup.app, http.MethodGet, getRedirectEndpoint("launch"), "")
+				require.Equal(t, http.StatusFound, shared.Code)
+				assert.Equal(t, "https://golang.org", shared.Header().Get("Location"))
+
+				return executeRequest(setup.app, http.MethodGet, created.ShortUrl, "")
+			},
+			expectedStatus: http.StatusFound,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "https://go.dev", rec.Header().Get("Location"))
+			},
+		},
+		{
+			name: "links cannot be created on an unverified domain",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				registerTestDomain(t, setup, owner.ID, "go.example.com")
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://go.dev","domain":"go.example.com"}`, testLinkToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "domain is not verified")
+			},
+		},
+		{
+			name: "verification fails while the TXT record is missing",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				domain := registerTestDomain(t, setup, owner.ID, "go.example.com")
+				setup.mockResolver.On("LookupTXT", mock.Anything, domain.TxtRecordName).Return([]string{"v=spf1 -all"}, nil).Once()
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(setup.app, http.MethodPost, getDomainVerifyEndpoint("go.example.com"), "", testLinkToken)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unverified claim of another user does not block the domain",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				other := createTestUser(t, setup.mockDB, "otheruser", "other@example.com", "Other User", fixture.ValidTestPassword())
+				registerTestDomain(t, setup, other.ID, "go.example.com")
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(setup.app, http.MethodPost, getDomainsEndpoint(), `{"name":"GO.example.com"}`, testLinkToken)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "domain verified by another user",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				other := createTestUser(t, setup.mockDB, "otheruser", "other@example.com", "Other User", fixture.ValidTestPassword())
+				createTestDomainLink(t, setup, other.ID, "their0001", "go.example.com")
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(setup.app, http.MethodPost, getDomainsEndpoint(), `{"name":"GO.example.com"}`, testLinkToken)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "verified custom domain cannot be a destination",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				createTestDomainLink(t, setup, owner.ID, "loop0001", "go.example.com")
+
+				return executeRequest(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://GO.example.com/loop0001"}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "host of this service cannot be registered",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(setup.app, http.MethodPost, getDomainsEndpoint(), `{"name":"localhost"}`, testLinkToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown host does not serve codes at its root",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				createOwnedLink(t, setup.mockDB, "mine0001", "https://golang.org", fixture.ShortLinkOwnerID)
+				return executeRequest(setup.app, http.MethodGet, "https://other.example.com/mine0001", "")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "deleted domain stops serving its links",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				createTestDomainLink(t, setup, owner.ID, "launch", "go.example.com")
+				require.Equal(t, http.StatusFound, executeRequest(setup.app, http.MethodGet, "https://go.example.com/launch", "").Code)
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				rec := executeRequestWithAuth(setup.app, http.MethodDelete, getDomainEndpoint("go.example.com"), "", testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+				return executeRequest(setup.app, http.MethodGet, "https://go.example.com/launch", "")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "list - own domains with their TXT records",
+			setupTestHttp: func(t *testing.T, setup *testSetup) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, setup.mockDB)
+				registerTestDomain(t, setup, owner.ID, "go.example.com")
+
+				fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+				return executeGetRequestWithAuth(setup.app, getDomainsEndpoint(), testLinkToken)
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var resp struct {
+					Data dto.DomainListResponseDto `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data.Items, 1)
+				assert.Equal(t, "go.example.com", resp.Data.Items[0].Name)
+				assert.False(t, resp.Data.Items[0].Verified)
+				assert.Equal(t, "_bookmark-verify.go.example.com", resp.Data.Items[0].TxtRecordName)
+			},
+		},
+	}
+
+	cfg := defaultTestConfig()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructure(t, cfg, true)
+			rec := tc.setupTestHttp(t, setup)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, rec)
+			}
+		})
+	}
+}
+
+// registerTestDomain registers the domain for the user through the API
+func registerTestDomain(t *testing.T, setup *testSetup, userId, name string) dto.DomainDto {
+	t.Helper()
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, userId)
+	rec := executeRequestWithAuth(setup.app, http.MethodPost, getDomainsEndpoint(), `{"name":"`+name+`"}`, testLinkToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var resp struct {
+		Data dto.DomainDto `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+// createTestDomainLink creates a verified domain of the user and a link without expiry on it
+func createTestDomainLink(t *testing.T, setup *testSetup, userId, code, domain string) {
+	t.Helper()
+	verifiedAt := time.Now().UTC()
+	require.NoError(t, setup.mockDB.Create(&model.Domain{Name: domain, OwnerId: userId, Token: "token", CreatedAt: verifiedAt, VerifiedAt: &verifiedAt}).Error)
+	createOwnedLink(t, setup.mockDB, dto.LinkKey(code, domain), "https://go.dev", userId)
+}
+
+func getDomainsEndpoint() string {
+	return "/v1" + routers.Endpoints.Domains
+}
+
+func getDomainEndpoint(domain string) string {
+	return "/v1" + strings.Replace(routers.Endpoints.Domain, ":domain", domain, 1)
+}
+
+func getDomainVerifyEndpoint(domain string) string {
+	return "/v1" + strings.Replace(routers.Endpoints.DomainVerify, ":domain", domain, 1)
+}
@@ -0,0 +1,210 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apipkg "github.com/vincent-tien/bookmark-management/internal/api"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/repository"
+	"github.com/vincent-tien/bookmark-management/internal/routers"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	jwtMocks "github.com/vincent-tien/bookmark-management/pkg/jwtUtils/mocks"
+	"gorm.io/gorm"
+)
+
+const testLinkToken = "mock.token.from.fixture"
+
+func TestLinkManagementEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupTestHttp  func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder
+		expectedStatus int
+		validateResp   func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "list - only own links, searchable",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLink(t, db, "mine0001", "https://golang.org", owner.ID)
+				createOwnedLink(t, db, "mine0002", "https://example.com", owner.ID)
+				createOwnedLink(t, db, "theirs01", "https://golang.org", "00000000-0000-0000-0000-000000000000")
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				return executeGetRequestWithAuth(api, getMyLinksEndpoint()+"?q=golang", testLinkToken)
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var resp struct {
+					Data dto.LinkListResponseDto `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, int64(1), resp.Data.Total)
+				require.Len(t, resp.Data.Items, 1)
+				assert.Equal(t, "mine0001", resp.Data.Items[0].Code)
+			},
+		},
+		{
+			name: "update - disabled link stops redirecting even when cached",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLink(t, db, "mine0001", "https://golang.org", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				// Populate the redirect cache
+				require.Equal(t, http.StatusFound, executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "").Code)
+
+				rec := executeRequestWithAuth(api, http.MethodPatch, getMyLinkEndpoint("mine0001"), `{"disabled":true}`, testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "update - new destination is used by redirects",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLink(t, db, "mine0001", "https://golang.org", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				require.Equal(t, http.StatusFound, executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "").Code)
+
+				rec := executeRequestWithAuth(api, http.MethodPatch, getMyLinkEndpoint("mine0001"), `{"url":"https://go.dev","exp":0}`, testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "")
+			},
+			expectedStatus: http.StatusFound,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "https://go.dev", rec.Header().Get("Location"))
+			},
+		},
+		{
+			name: "update - zero not_before removes the schedule",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLink(t, db, "mine0001", "https://golang.org", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				rec := executeRequestWithAuth(api, http.MethodPatch, getMyLinkEndpoint("mine0001"),
+					`{"not_before":"2099-01-01T00:00:00Z","exp":0}`, testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.Equal(t, http.StatusForbidden, executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "").Code)
+
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				rec = executeRequestWithAuth(api, http.MethodPatch, getMyLinkEndpoint("mine0001"),
+					`{"not_before":"0001-01-01T00:00:00Z"}`, testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "")
+			},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			name: "update - link of another user",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLink(t, db, "theirs01", "https://golang.org", "00000000-0000-0000-0000-000000000000")
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(api, http.MethodPatch, getMyLinkEndpoint("theirs01"), `{"disabled":true}`, testLinkToken)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "delete - code no longer resolves",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLink(t, db, "mine0001", "https://golang.org", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				require.Equal(t, http.StatusFound, executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "").Code)
+
+				rec := executeRequestWithAuth(api, http.MethodDelete, getMyLinkEndpoint("mine0001"), "", testLinkToken)
+				require.Equal(t, http.StatusOK, rec.Code)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("mine0001"), "")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "unauthorized - missing authorization header",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				return executeGetRequestWithAuth(api, getMyLinksEndpoint(), "")
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				validateUnauthorizedResponse(t, rec, "Authorization is required")
+			},
+		},
+	}
+
+	cfg := defaultTestConfig()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructure(t, cfg, true)
+			rec := tc.setupTestHttp(t, setup.app, setup.mockDB, setup.mockJwtValidator)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, rec)
+			}
+		})
+	}
+}
+
+func TestLinkManagementEndpoint_DeleteForgetsLink(t *testing.T) {
+	t.Parallel()
+
+	setup := setupTestInfrastructure(t, defaultTestConfig(), true)
+	owner := createTestUserWithDefaults(t, setup.mockDB)
+	ctx := t.Context()
+
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+	rec := executeRequestWithAuth(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`, testLinkToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created dto.LinkShortenResponseDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	// State the redirects and the code generator leave behind
+	require.NoError(t, repository.NewUniqueVisitor(setup.mockRedis).AddVisits(ctx, []repository.Visit{
+		{Code: created.Code, VisitorId: "visitor", At: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Code: created.Code, VisitorId: "visitor", At: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}))
+	require.NoError(t, setup.mockRedis.Set(ctx, "clicks_left:"+created.Code, 3, 0).Err())
+	require.NoError(t, setup.mockRedis.Set(ctx, "code_reserved:"+strings.ToLower(created.Code), 1, 0).Err())
+	require.NotEmpty(t, setup.mockRedis.Keys(ctx, "target*").Val())
+
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+	rec = executeRequestWithAuth(setup.app, http.MethodDelete, getMyLinkEndpoint(created.Code), "", testLinkToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Empty(t, setup.mockRedis.Keys(ctx, "uv:"+created.Code+":*").Val())
+	assert.Empty(t, setup.mockRedis.Keys(ctx, "target*").Val())
+	assert.Zero(t, setup.mockRedis.Exists(ctx, "clicks_left:"+created.Code, "code_reserved:"+strings.ToLower(created.Code)).Val())
+
+	// Shortening the same URL again does not hand out the deleted code
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+	rec = executeRequestWithAuth(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`, testLinkToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var again dto.LinkShortenResponseDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &again))
+	assert.NotEqual(t, created.Code, again.Code)
+}
+
+// createOwnedLink creates a link without expiry owned by ownerId
+func createOwnedLink(t *testing.T, db *gorm.DB, code, target, ownerId string) {
+	t.Helper()
+	require.NoError(t, db.Create(&model.ShortLink{Code: code, Target: target, OwnerId: &ownerId}).Error)
+}
+
+func getMyLinksEndpoint() string {
+	return "/v1" + routers.Endpoints.MyLinks
+}
+
+func getMyLinkEndpoint(code string) string {
+	return "/v1" + strings.Replace(routers.Endpoints.MyLink, ":code", code, 1)
+}
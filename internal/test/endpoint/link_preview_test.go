@@ -0,0 +1,155 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apipkg "github.com/vincent-tien/bookmark-management/internal/api"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/routers"
+)
+
+// previewTarget lies in a documentation address range, so its title is never found
+const previewTarget = "http://203.0.113.7:8080/login"
+
+func TestLinkPreviewEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupTestHttp  func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder
+		expectedStatus int
+		validateResp   func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success case - preview endpoint",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createPreviewLink(t, api, `{"url":"`+previewTarget+`","alias":"peek"}`)
+				return executeRequest(api, http.MethodGet, getLinkPreviewEndpoint("peek"), "")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decodePreview(t, rec)
+				assert.Equal(t, previewTarget, res.Url)
+				assert.Equal(t, "http://localhost:8080/v1/links/redirect/peek", res.ShortUrl)
+				assert.Equal(t, "http://localhost:8080/v1/links/redirect/peek?confirm=1", res.ContinueUrl)
+				assert.True(t, res.Suspicious)
+				assert.Len(t, res.Warnings, 3)
+				assert.Empty(t, res.Title)
+			},
+		},
+		{
+			name: "success case - plus suffix on the short URL",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createPreviewLink(t, api, `{"url":"`+previewTarget+`","alias":"peek"}`)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("peek+"), "")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Empty(t, rec.Header().Get("Location"))
+				assert.Equal(t, previewTarget, decodePreview(t, rec).Url)
+			},
+		},
+		{
+			name: "success case - html page for browsers",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createPreviewLink(t, api, `{"url":"`+previewTarget+`","alias":"peek"}`)
+				req := httptest.NewRequest(http.MethodGet, getLinkPreviewEndpoint("peek"), nil)
+				req.Header.Set("Accept", "text/html")
+				rec := httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+				assert.Contains(t, rec.Body.String(), previewTarget)
+				assert.Contains(t, rec.Body.String(), `role="alert"`)
+			},
+		},
+		{
+			name: "success case - protected link keeps its destination hidden",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createPreviewLink(t, api, `{"url":"`+previewTarget+`","alias":"peek","password":"open-sesame"}`)
+				return executeRequest(api, http.MethodGet, getLinkPreviewEndpoint("peek"), "")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decodePreview(t, rec)
+				assert.True(t, res.PasswordProtected)
+				assert.Empty(t, res.Url)
+			},
+		},
+		{
+			name: "interstitial - unconfirmed visit shows the preview",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createPreviewLink(t, api, `{"url":"https://google.com","alias":"careful","interstitial":true}`)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("careful"), "")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Empty(t, rec.Header().Get("Location"))
+				assert.Equal(t, "https://google.com", decodePreview(t, rec).Url)
+			},
+		},
+		{
+			name: "interstitial - confirmed visit is redirected",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createPreviewLink(t, api, `{"url":"https://google.com","alias":"careful","interstitial":true}`)
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("careful")+"?confirm=1", "")
+			},
+			expectedStatus: http.StatusFound,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "https://google.com", rec.Header().Get("Location"))
+			},
+		},
+		{
+			name: "not found - unknown code",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodGet, getLinkPreviewEndpoint("nonexistent"), "")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	cfg := defaultTestConfig()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructureSimple(t, cfg)
+			rec := tc.setupTestHttp(t, setup.app)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, rec)
+			}
+		})
+	}
+}
+
+// createPreviewLink shortens a link with the given raw JSON request
+func createPreviewLink(t *testing.T, api apipkg.Engine, body string) {
+	t.Helper()
+	rec := executeRequest(api, http.MethodPost, getApiEndpoint(), body)
+	require.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func decodePreview(t *testing.T, rec *httptest.ResponseRecorder) dto.LinkPreviewDto {
+	t.Helper()
+	var resp struct {
+		Data dto.LinkPreviewDto `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func getLinkPreviewEndpoint(code string) string {
+	return "/v1" + strings.Replace(routers.Endpoints.LinkPreview, ":code", code, 1)
+}
@@ -0,0 +1,114 @@
+package endpoint
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apipkg "github.com/vincent-tien/bookmark-management/internal/api"
+	"github.com/vincent-tien/bookmark-management/internal/routers"
+)
+
+func TestLinkQrEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupTestHttp  func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder
+		expectedStatus int
+		validateResp   func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success case - png",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createAliasLink(t, api, "poster")
+				return executeRequest(api, http.MethodGet, getLinkQrEndpoint("poster")+"?size=300", "")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+				assert.NotEmpty(t, rec.Header().Get("ETag"))
+				img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+				require.NoError(t, err)
+				assert.Equal(t, 300, img.Bounds().Dx())
+			},
+		},
+		{
+			name: "success case - svg",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createAliasLink(t, api, "poster")
+				return executeRequest(api, http.MethodGet, getLinkQrEndpoint("poster")+"?format=svg&fg=ff0000", "")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+				assert.Contains(t, rec.Body.String(), `fill="#ff0000"`)
+			},
+		},
+		{
+			name: "not modified - repeated request with ETag",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createAliasLink(t, api, "poster")
+				first := executeRequest(api, http.MethodGet, getLinkQrEndpoint("poster"), "")
+				require.Equal(t, http.StatusOK, first.Code)
+
+				req := httptest.NewRequest(http.MethodGet, getLinkQrEndpoint("poster"), nil)
+				req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+				rec := httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusNotModified,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Empty(t, rec.Body.Bytes())
+			},
+		},
+		{
+			name: "bad request - invalid color",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				createAliasLink(t, api, "poster")
+				return executeRequest(api, http.MethodGet, getLinkQrEndpoint("poster")+"?bg=white", "")
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found - unknown code",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodGet, getLinkQrEndpoint("nonexistent"), "")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	cfg := defaultTestConfig()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructureSimple(t, cfg)
+			rec := tc.setupTestHttp(t, setup.app)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, rec)
+			}
+		})
+	}
+}
+
+// createAliasLink shortens https://google.com under the given alias
+func createAliasLink(t *testing.T, api apipkg.Engine, alias string) {
+	t.Helper()
+	rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"`+alias+`"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func getLinkQrEndpoint(code string) string {
+	return "/v1" + strings.Replace(routers.Endpoints.LinkQr, ":code", code, 1)
+}
@@ -7,13 +7,18 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apipkg "github.com/vincent-tien/bookmark-management/internal/api"
 	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
 	"github.com/vincent-tien/bookmark-management/internal/routers"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	jwtMocks "github.com/vincent-tien/bookmark-management/pkg/jwtUtils/mocks"
+	"gorm.io/gorm"
 )
 
 func TestLinkShortenEndpoint(t *testing.T) {
@@ -61,6 +66,115 @@ func TestLinkShortenEndpoint(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "bad request - routing rule without condition",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(),
+					`{"url":"https://google.com","routing":{"rules":[{"name":"all","url":"https://google.com/all"}]}}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - unsupported redirect status",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","redirect_status":303}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - unknown referrer policy",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","referrer_policy":"everything"}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - password over 72 bytes in fewer characters",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(),
+					`{"url":"https://google.com","password":"`+strings.Repeat("密", 30)+`"}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - not_after before not_before",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com",`+
+					`"not_before":"2099-01-02T00:00:00Z","not_after":"2099-01-01T00:00:00Z"}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - not_after in the past",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","not_after":"2000-01-01T00:00:00Z"}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "success case - custom alias",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				reqBody := dto.LinkShortenRequestDto{
+					Url:   "https://example.com",
+					Alias: "my-launch",
+				}
+				return executeJSONRequest(api, http.MethodPost, getApiEndpoint(), reqBody)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "conflict - alias taken with different case",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				executeJSONRequest(api, http.MethodPost, getApiEndpoint(), dto.LinkShortenRequestDto{
+					Url:   "https://example.com",
+					Alias: "my-launch",
+				})
+				reqBody := dto.LinkShortenRequestDto{
+					Url:   "https://google.com",
+					Alias: "My-Launch",
+				}
+				return executeJSONRequest(api, http.MethodPost, getApiEndpoint(), reqBody)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "bad request - reserved alias",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				reqBody := dto.LinkShortenRequestDto{
+					Url:   "https://example.com",
+					Alias: "health-check",
+				}
+				return executeJSONRequest(api, http.MethodPost, getApiEndpoint(), reqBody)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - javascript destination",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"javascript:alert(document.cookie)"}`)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - private address destination",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				reqBody := dto.LinkShortenRequestDto{
+					Url: "http://169.254.169.254/latest/meta-data",
+				}
+				return executeJSONRequest(api, http.MethodPost, getApiEndpoint(), reqBody)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "bad request - link to another short link of this service",
+			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
+				reqBody := dto.LinkShortenRequestDto{
+					Url: "http://localhost:8080/v1/links/redirect/abc",
+				}
+				return executeJSONRequest(api, http.MethodPost, getApiEndpoint(), reqBody)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name: "success case - default expiration",
 			setupTestHttp: func(api apipkg.Engine) *httptest.ResponseRecorder {
@@ -84,7 +198,7 @@ func TestLinkShortenEndpoint(t *testing.T) {
 
 			assert.Equal(t, tc.expectedStatus, rec.Code)
 			switch tc.expectedStatus {
-			case http.StatusBadRequest:
+			case http.StatusBadRequest, http.StatusConflict:
 				assert.Contains(t, rec.Body.String(), "error")
 			case http.StatusCreated:
 				var resp struct {
@@ -100,14 +214,365 @@ func TestLinkShortenEndpoint(t *testing.T) {
 	}
 }
 
-func TestRedirectLinkEndpoint(t *testing.T) {
+func TestLinkShortenEndpoint_OptionalAuth(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupTestHttp  func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder
+		expectedStatus int
+		validateResp   func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "signed-in caller owns the new link",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				rec := executeRequestWithAuth(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org","alias":"my-owned"}`, testLinkToken)
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				return executeGetRequestWithAuth(api, getMyLinksEndpoint(), testLinkToken)
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), `"code":"my-owned"`)
+			},
+		},
+		{
+			name: "anonymous link has no owner",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org","alias":"anon-link"}`)
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				link := &model.ShortLink{}
+				require.NoError(t, db.Where("code = ?", "anon-link").First(link).Error)
+				assert.Nil(t, link.OwnerId)
+				return rec
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "signed-in caller shortening the same URL again gets the same code",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				codes := make([]string, 0, 3)
+				for _, body := range []string{
+					`{"url":"https://golang.org/doc?b=2&a=1"}`,
+					`{"url":"HTTPS://golang.org:443/doc?a=1&b=2"}`,
+					`{"url":"https://golang.org/doc?a=1&b=2","exp":60}`,
+				} {
+					fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+					rec := executeRequestWithAuth(api, http.MethodPost, getApiEndpoint(), body, testLinkToken)
+					require.Equal(t, http.StatusCreated, rec.Code)
+
+					var created dto.LinkShortenResponseDto
+					require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+					codes = append(codes, created.Code)
+				}
+
+				assert.Equal(t, codes[0], codes[1])
+				// Different options get their own link
+				assert.NotEqual(t, codes[0], codes[2])
+
+				// Anonymous callers are not deduplicated by default
+				first := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org/doc?a=1&b=2"}`)
+				second := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org/doc?a=1&b=2"}`)
+				assert.NotEqual(t, first.Body.String(), second.Body.String())
+				return second
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "unauthorized - invalid token is not downgraded to anonymous",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				fixture.SetupMockJwtValidatorWithError(mockJwtValidator)
+				return executeRequestWithAuth(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`, "expired.token")
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				validateUnauthorizedResponse(t, rec, "invalid token")
+			},
+		},
+		{
+			name: "too many requests - anonymous limit",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				for i := 0; i < 2; i++ {
+					rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`)
+					require.Equal(t, http.StatusCreated, rec.Code)
+				}
+				return executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+				assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+				assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name: "signed-in callers get the higher limit",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				for i := 0; i < 2; i++ {
+					executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`)
+				}
+
+				owner := createTestUserWithDefaults(t, db)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				return executeRequestWithAuth(api, http.MethodPost, getApiEndpoint(), `{"url":"https://golang.org"}`, testLinkToken)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "5", rec.Header().Get("X-RateLimit-Limit"))
+				assert.Equal(t, "4", rec.Header().Get("X-RateLimit-Remaining"))
+			},
+		},
+	}
+
+	cfg := defaultTestConfig()
+	cfg.ShortenLimitAnonymous = 2
+	cfg.ShortenLimitUser = 5
+	cfg.ShortenLimitWindow = time.Hour
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructure(t, cfg, true)
+			rec := tc.setupTestHttp(t, setup.app, setup.mockDB, setup.mockJwtValidator)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, rec)
+			}
+		})
+	}
+}
+
+func TestLinkShortenEndpoint_TrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		expectedStatus int
+	}{
+		{name: "forwarded client IP of an untrusted peer is ignored", expectedStatus: http.StatusTooManyRequests},
+		{name: "forwarded client IP of a trusted proxy is used", trustedProxies: []string{"192.0.2.0/24"}, expectedStatus: http.StatusCreated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := defaultTestConfig()
+			cfg.ShortenLimitAnonymous = 1
+			cfg.ShortenLimitWindow = time.Hour
+			cfg.TrustedProxies = tc.trustedProxies
+			setup := setupTestInfrastructure(t, cfg, true)
+
+			// httptest requests come from 192.0.2.1
+			var rec *httptest.ResponseRecorder
+			for _, client := range []string{"203.0.113.1", "203.0.113.2"} {
+				req := httptest.NewRequest(http.MethodPost, getApiEndpoint(), strings.NewReader(`{"url":"https://golang.org"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("X-Forwarded-For", client)
+				rec = httptest.NewRecorder()
+				setup.app.ServeHTTP(rec, req)
+			}
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestLinkShortenEndpoint_CodeStrategies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		strategy      string
+		validateCodes func(t *testing.T, codes []string)
+	}{
+		{
+			name:     "counter codes are base36 sequence numbers",
+			strategy: "counter",
+			validateCodes: func(t *testing.T, codes []string) {
+				assert.Equal(t, []string{"aaaaab", "aaaaac", "aaaaad"}, codes)
+			},
+		},
+		{
+			name:     "hashids codes are distinct and not sequential",
+			strategy: "hashids",
+			validateCodes: func(t *testing.T, codes []string) {
+				assert.Len(t, codes[0], 6)
+				assert.NotEqual(t, codes[0], codes[1])
+				assert.NotEqual(t, codes[0][:5], codes[1][:5])
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := defaultTestConfig()
+			cfg.CodeStrategy = tc.strategy
+			cfg.CodeLength = 6
+			cfg.CodeSalt = "pepper"
+			setup := setupTestInfrastructureSimple(t, cfg)
+
+			codes := make([]string, 0, 3)
+			for i := 0; i < 3; i++ {
+				rec := executeRequest(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://example.com"}`)
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				var created dto.LinkShortenResponseDto
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+				codes = append(codes, created.Code)
+
+				redirect := executeRequest(setup.app, http.MethodGet, getRedirectEndpoint(created.Code), "")
+				assert.Equal(t, http.StatusFound, redirect.Code)
+			}
+
+			tc.validateCodes(t, codes)
+		})
+	}
+}
+
+func TestLinkShortenBulkEndpoint(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
 		name           string
-		setupTestHttp  func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder
+		setupTestHttp  func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder
 		expectedStatus int
-		expectedLoc    string
+		validateResp   func(t *testing.T, api apipkg.Engine, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "json items are shortened and owned by the caller",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				body := `[{"url":"https://golang.org"},{"url":"ftp://golang.org"},{"url":"https://go.dev","alias":"bulk-alias"},{"url":"https://pkg.go.dev"}]`
+				rec := executeRequestWithAuth(api, http.MethodPost, getBulkEndpoint(), body, testLinkToken)
+
+				var owned int64
+				require.NoError(t, db.Model(&model.ShortLink{}).Where("owner_id = ?", owner.ID).Count(&owned).Error)
+				assert.Equal(t, int64(3), owned)
+				return rec
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, api apipkg.Engine, rec *httptest.ResponseRecorder) {
+				res := decodeBulkResponse(t, rec)
+				assert.Equal(t, 3, res.Created)
+				assert.Equal(t, 1, res.Failed)
+				assert.Equal(t, "bulk-alias", res.Items[2].Code)
+				assert.Contains(t, res.Items[1].Error, "scheme is not allowed")
+				assert.NotEqual(t, res.Items[0].Code, res.Items[3].Code)
+
+				for _, i := range []int{0, 2, 3} {
+					redirect := executeRequest(api, http.MethodGet, getRedirectEndpoint(res.Items[i].Code), "")
+					assert.Equal(t, http.StatusFound, redirect.Code)
+					assert.Equal(t, res.Items[i].Url, redirect.Header().Get("Location"))
+				}
+			},
+		},
+		{
+			name: "csv body is shortened",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				req := httptest.NewRequest(http.MethodPost, getBulkEndpoint(), strings.NewReader("url,exp\nhttps://golang.org,60\nhttps://go.dev,\n"))
+				req.Header.Set("Content-Type", "text/csv")
+				req.Header.Set("Authorization", "Bearer "+testLinkToken)
+				rec := httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, api apipkg.Engine, rec *httptest.ResponseRecorder) {
+				res := decodeBulkResponse(t, rec)
+				assert.Equal(t, 2, res.Created)
+				for _, item := range res.Items {
+					redirect := executeRequest(api, http.MethodGet, getRedirectEndpoint(item.Code), "")
+					assert.Equal(t, http.StatusFound, redirect.Code)
+				}
+			},
+		},
+		{
+			name: "unauthorized - anonymous caller",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				return executeRequest(api, http.MethodPost, getBulkEndpoint(), `[{"url":"https://golang.org"}]`)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructure(t, defaultTestConfig(), true)
+			rec := tc.setupTestHttp(t, setup.app, setup.mockDB, setup.mockJwtValidator)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, setup.app, rec)
+			}
+		})
+	}
+}
+
+func TestLinkShortenBulkEndpoint_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultTestConfig()
+	cfg.ShortenLimitUser = 5
+	cfg.ShortenLimitWindow = time.Hour
+	setup := setupTestInfrastructure(t, cfg, true)
+	owner := createTestUserWithDefaults(t, setup.mockDB)
+
+	// Every item counts against the limit
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+	rec := executeRequestWithAuth(setup.app, http.MethodPost, getBulkEndpoint(),
+		`[{"url":"https://golang.org"},{"url":"https://go.dev"},{"url":"https://pkg.go.dev"}]`, testLinkToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Remaining"))
+
+	// A batch larger than the remaining quota is rejected as a whole and not counted
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+	rec = executeRequestWithAuth(setup.app, http.MethodPost, getBulkEndpoint(),
+		`[{"url":"https://golang.org"},{"url":"https://go.dev"},{"url":"https://pkg.go.dev"}]`, testLinkToken)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	var owned int64
+	require.NoError(t, setup.mockDB.Model(&model.ShortLink{}).Where("owner_id = ?", owner.ID).Count(&owned).Error)
+	assert.Equal(t, int64(3), owned)
+
+	fixture.SetupMockJwtValidatorWithUserID(setup.mockJwtValidator, owner.ID)
+	rec = executeRequestWithAuth(setup.app, http.MethodPost, getApiEndpoint(), `{"url":"https://go.dev/doc"}`, testLinkToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+}
+
+func decodeBulkResponse(t *testing.T, rec *httptest.ResponseRecorder) dto.LinkBulkShortenResponseDto {
+	t.Helper()
+	var res dto.LinkBulkShortenResponseDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res
+}
+
+func TestRedirectLinkEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		setupTestHttp   func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder
+		expectedStatus  int
+		expectedLoc     string
+		expectedHeaders map[string]string
 	}{
 		{
 			name: "success case",
@@ -121,9 +586,244 @@ func TestRedirectLinkEndpoint(t *testing.T) {
 				api.ServeHTTP(rec, req)
 				return rec
 			},
+			expectedStatus:  http.StatusFound,
+			expectedLoc:     "https://google.com",
+			expectedHeaders: map[string]string{"Cache-Control": "no-store"},
+		},
+		{
+			name: "success case - permanent redirect is cacheable and sends the link's headers",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"moved","exp":-1,`+
+					`"redirect_status":301,"no_index":true,"referrer_policy":"no-referrer"}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("moved"), "")
+			},
+			expectedStatus: http.StatusMovedPermanently,
+			expectedLoc:    "https://google.com",
+			expectedHeaders: map[string]string{
+				"Cache-Control":   "public, max-age=86400",
+				"X-Robots-Tag":    "noindex",
+				"Referrer-Policy": "no-referrer",
+			},
+		},
+		{
+			name: "success case - temporary redirect is never cached",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"moving","redirect_status":307}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("moving"), "")
+			},
+			expectedStatus:  http.StatusTemporaryRedirect,
+			expectedLoc:     "https://google.com",
+			expectedHeaders: map[string]string{"Cache-Control": "no-store", "X-Robots-Tag": ""},
+		},
+		{
+			name: "scheduled link is not yet available and not cached",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"launch",`+
+					`"not_before":"2099-01-01T09:00:00Z","redirect_status":301}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("launch"), "")
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"Cache-Control": "no-store", "Location": ""},
+		},
+		{
+			name: "scheduled link does not reveal its destination in the preview",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"launch",`+
+					`"not_before":"2099-01-01T09:00:00Z"}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("launch+"), "")
+			},
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"Cache-Control": "no-store"},
+		},
+		{
+			name: "success case - scheduled link after its activation time",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"launched",`+
+					`"not_before":"2000-01-01T09:00:00Z","not_after":"2099-01-01T09:00:00Z"}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("launched"), "")
+			},
 			expectedStatus: http.StatusFound,
 			expectedLoc:    "https://google.com",
 		},
+		{
+			name: "forbidden - destination rejected at redirect time",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				// Links stored before the policy existed are checked when followed
+				ctx := context.Background()
+				mockRedis.Set(ctx, "legacy", "http://10.0.0.1/admin", 0)
+
+				req := httptest.NewRequest(http.MethodGet, getRedirectEndpoint("legacy"), nil)
+				rec := httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedLoc:    "",
+		},
+		{
+			name: "gone - one-time link already used",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"one-time","max_clicks":1}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				first := executeRequest(api, http.MethodGet, getRedirectEndpoint("one-time"), "")
+				if first.Code != http.StatusFound {
+					return first
+				}
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("one-time"), "")
+			},
+			expectedStatus: http.StatusGone,
+			expectedLoc:    "",
+		},
+		{
+			name: "success case - lost click counter is rebuilt from the clicks used",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"two-time","max_clicks":2}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				first := executeRequest(api, http.MethodGet, getRedirectEndpoint("two-time"), "")
+				if first.Code != http.StatusFound {
+					return first
+				}
+				mockRedis.Del(context.Background(), "clicks_left:two-time")
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("two-time"), "")
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com",
+		},
+		{
+			name: "gone - lost click counter of a used up link",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"one-time","max_clicks":1}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				first := executeRequest(api, http.MethodGet, getRedirectEndpoint("one-time"), "")
+				if first.Code != http.StatusFound {
+					return first
+				}
+				mockRedis.Del(context.Background(), "clicks_left:one-time")
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("one-time"), "")
+			},
+			expectedStatus: http.StatusGone,
+			expectedLoc:    "",
+		},
+		{
+			name: "success case - routing rule matches the visitor",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"routed","routing":{`+
+					`"rules":[{"name":"newsletter","url":"https://google.com/news","query":"src","query_value":"email"}],`+
+					`"variants":[{"name":"only","url":"https://google.com/split","weight":1}]}}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("routed")+"?src=email", "")
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com/news",
+		},
+		{
+			name: "success case - visitors no rule matches get a variant",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"routed","routing":{`+
+					`"rules":[{"name":"newsletter","url":"https://google.com/news","query":"src","query_value":"email"}],`+
+					`"variants":[{"name":"only","url":"https://google.com/split","weight":1}]}}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("routed")+"?src=ad", "")
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com/split",
+		},
+		{
+			name: "success case - utm parameters merged into the destination",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com/?utm_source=partner","alias":"campaign",`+
+					`"utm":{"source":"newsletter","medium":"email","campaign":"spring-{code}"}}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("campaign"), "")
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com/?utm_source=partner&utm_campaign=spring-campaign&utm_medium=email",
+		},
+		{
+			name: "unauthorized - protected link without password",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"secret","password":"open-sesame"}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				return executeRequest(api, http.MethodGet, getRedirectEndpoint("secret"), "")
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedLoc:    "",
+		},
+		{
+			name: "success case - protected link with password header",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"secret","password":"open-sesame"}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				req := httptest.NewRequest(http.MethodGet, getRedirectEndpoint("secret"), nil)
+				req.Header.Set("X-Link-Password", "open-sesame")
+				rec = httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://google.com",
+		},
+		{
+			name: "success case - protected link with password form",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				rec := executeRequest(api, http.MethodPost, getApiEndpoint(), `{"url":"https://google.com","alias":"secret","password":"open-sesame"}`)
+				if rec.Code != http.StatusCreated {
+					return rec
+				}
+
+				req := httptest.NewRequest(http.MethodPost, getRedirectEndpoint("secret"), strings.NewReader("password=open-sesame"))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				rec = httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusSeeOther,
+			expectedLoc:    "https://google.com",
+		},
 		{
 			name: "not found - URL not found",
 			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
@@ -162,6 +862,27 @@ func TestRedirectLinkEndpoint(t *testing.T) {
 			expectedStatus: http.StatusFound,
 			expectedLoc:    "https://example.com",
 		},
+		{
+			name: "success case - link survives cache flush",
+			setupTestHttp: func(api apipkg.Engine, mockRedis *redis.Client) *httptest.ResponseRecorder {
+				createRec := executeJSONRequest(api, http.MethodPost, getApiEndpoint(), dto.LinkShortenRequestDto{
+					ExpInSeconds: 3600,
+					Url:          "https://golang.org",
+				})
+				var created dto.LinkShortenResponseDto
+				_ = json.Unmarshal(createRec.Body.Bytes(), &created)
+
+				// Drop every cached link so the redirect has to read the database
+				mockRedis.FlushAll(context.Background())
+
+				req := httptest.NewRequest(http.MethodGet, getRedirectEndpoint(created.Code), nil)
+				rec := httptest.NewRecorder()
+				api.ServeHTTP(rec, req)
+				return rec
+			},
+			expectedStatus: http.StatusFound,
+			expectedLoc:    "https://golang.org",
+		},
 	}
 
 	cfg := defaultTestConfig()
@@ -174,8 +895,11 @@ func TestRedirectLinkEndpoint(t *testing.T) {
 			rec := tc.setupTestHttp(setup.app, setup.mockRedis)
 
 			assert.Equal(t, tc.expectedStatus, rec.Code)
+			for name, value := range tc.expectedHeaders {
+				assert.Equal(t, value, rec.Header().Get(name), name)
+			}
 			switch tc.expectedStatus {
-			case http.StatusFound:
+			case http.StatusFound, http.StatusSeeOther, http.StatusMovedPermanently, http.StatusTemporaryRedirect:
 				// Check redirect location header
 				assert.Equal(t, tc.expectedLoc, rec.Header().Get("Location"))
 			case http.StatusNotFound:
@@ -199,6 +923,10 @@ func getApiEndpoint() string {
 	return "/v1" + routers.Endpoints.LinkShorten
 }
 
+func getBulkEndpoint() string {
+	return "/v1" + routers.Endpoints.LinkBulk
+}
+
 func getRedirectEndpoint(code string) string {
 	// The route uses wildcard *code, so we append the code directly
 	basePath := "/v1" + strings.TrimSuffix(routers.Endpoints.LinkRedirect, "*code")
@@ -0,0 +1,129 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apipkg "github.com/vincent-tien/bookmark-management/internal/api"
+	"github.com/vincent-tien/bookmark-management/internal/dto"
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"github.com/vincent-tien/bookmark-management/internal/routers"
+	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	jwtMocks "github.com/vincent-tien/bookmark-management/pkg/jwtUtils/mocks"
+	"gorm.io/gorm"
+)
+
+func TestLinkStatsEndpoint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		setupTestHttp  func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder
+		expectedStatus int
+		validateResp   func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success case",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLinkWithClicks(t, db, "stats001", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				return executeGetRequestWithAuth(api, getLinkStatsEndpoint("stats001")+"?from=2026-01-01T00:00:00Z&to=2026-01-03T00:00:00Z", "mock.token.from.fixture")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var resp struct {
+					Data dto.LinkStatsResponseDto `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, int64(3), resp.Data.TotalClicks)
+				assert.Equal(t, []dto.LinkStatsPointDto{
+					{Time: "2026-01-01T00:00:00Z", Clicks: 2},
+					{Time: "2026-01-02T00:00:00Z", Clicks: 1},
+				}, resp.Data.Series)
+				assert.Equal(t, []dto.LinkStatsBreakdownDto{{Value: "Chrome", Clicks: 2}, {Value: "Safari", Clicks: 1}}, resp.Data.Browsers)
+			},
+		},
+		{
+			name: "success case - include bots",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLinkWithClicks(t, db, "stats001", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, owner.ID)
+				return executeGetRequestWithAuth(api, getLinkStatsEndpoint("stats001")+"?from=2026-01-01T00:00:00Z&to=2026-01-03T00:00:00Z&include_bots=true", "mock.token.from.fixture")
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var resp struct {
+					Data dto.LinkStatsResponseDto `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.True(t, resp.Data.IncludeBots)
+				assert.Equal(t, int64(4), resp.Data.TotalClicks)
+				assert.Contains(t, resp.Data.Browsers, dto.LinkStatsBreakdownDto{Value: "Slackbot", Clicks: 1})
+			},
+		},
+		{
+			name: "not found - link owned by another user",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				owner := createTestUserWithDefaults(t, db)
+				createOwnedLinkWithClicks(t, db, "stats001", owner.ID)
+				fixture.SetupMockJwtValidatorWithUserID(mockJwtValidator, "00000000-0000-0000-0000-000000000000")
+				return executeGetRequestWithAuth(api, getLinkStatsEndpoint("stats001"), "mock.token.from.fixture")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "unauthorized - missing authorization header",
+			setupTestHttp: func(t *testing.T, api apipkg.Engine, db *gorm.DB, mockJwtValidator *jwtMocks.JwtValidator) *httptest.ResponseRecorder {
+				return executeGetRequestWithAuth(api, getLinkStatsEndpoint("stats001"), "")
+			},
+			expectedStatus: http.StatusUnauthorized,
+			validateResp: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				validateUnauthorizedResponse(t, rec, "Authorization is required")
+			},
+		},
+	}
+
+	cfg := defaultTestConfig()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setup := setupTestInfrastructure(t, cfg, true)
+			rec := tc.setupTestHttp(t, setup.app, setup.mockDB, setup.mockJwtValidator)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			if tc.validateResp != nil {
+				tc.validateResp(t, rec)
+			}
+		})
+	}
+}
+
+// createOwnedLinkWithClicks creates a link owned by ownerId with three clicks on 2026-01-01 and 2026-01-02
+// and one bot click on 2026-01-01
+func createOwnedLinkWithClicks(t *testing.T, db *gorm.DB, code, ownerId string) {
+	t.Helper()
+	require.NoError(t, db.Create(&model.ShortLink{Code: code, Target: "https://google.com", OwnerId: &ownerId}).Error)
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clicks := []*model.LinkClick{
+		{Code: code, ClickedAt: day.Add(time.Hour), Browser: "Chrome"},
+		{Code: code, ClickedAt: day.Add(2 * time.Hour), Browser: "Chrome"},
+		{Code: code, ClickedAt: day.Add(25 * time.Hour), Browser: "Safari"},
+		{Code: code, ClickedAt: day.Add(3 * time.Hour), Browser: "Slackbot", Device: "bot", IsBot: true},
+	}
+	require.NoError(t, db.Create(clicks).Error)
+}
+
+func getLinkStatsEndpoint(code string) string {
+	return "/v1" + strings.Replace(routers.Endpoints.LinkStats, ":code", code, 1)
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,7 @@ import (
 	"github.com/vincent-tien/bookmark-management/internal/model"
 	"github.com/vincent-tien/bookmark-management/internal/routers"
 	"github.com/vincent-tien/bookmark-management/internal/test/fixture"
+	dnsMocks "github.com/vincent-tien/bookmark-management/pkg/dnsverify/mocks"
 	"github.com/vincent-tien/bookmark-management/pkg/jwtUtils"
 	jwtMocks "github.com/vincent-tien/bookmark-management/pkg/jwtUtils/mocks"
 	redisPkg "github.com/vincent-tien/bookmark-management/pkg/redis"
@@ -38,6 +40,7 @@ type testSetup struct {
 	jwtGenerator     jwtUtils.JwtGenerator
 	jwtValidator     jwtUtils.JwtValidator
 	mockJwtValidator *jwtMocks.JwtValidator
+	mockResolver     *dnsMocks.Resolver
 	app              apipkg.Engine
 }
 
@@ -48,8 +51,8 @@ func setupTestInfrastructure(t *testing.T, cfg *config.Config, useMockValidator
 	mockRedis := redisPkg.InitMockRedis(t)
 	mockDB := sqldbPkg.InitMockDb(t)
 
-	// Migrate user table
-	require.NoError(t, mockDB.AutoMigrate(&model.User{}))
+	// Migrate user, short link, click and domain tables
+	require.NoError(t, mockDB.AutoMigrate(&model.User{}, &model.ShortLink{}, &model.LinkClick{}, &model.Domain{}))
 
 	jwtGenerator, err := jwtUtils.NewJwtGenerator(privateKeyPath)
 	if err != nil {
@@ -70,7 +73,8 @@ func setupTestInfrastructure(t *testing.T, cfg *config.Config, useMockValidator
 		jwtValidator = realValidator
 	}
 
-	app := apipkg.New(cfg, mockRedis, mockDB, jwtGenerator, jwtValidator)
+	mockResolver := dnsMocks.NewResolver(t)
+	app := apipkg.New(cfg, mockRedis, mockDB, jwtGenerator, jwtValidator, mockResolver)
 
 	return &testSetup{
 		mockRedis:        mockRedis,
@@ -78,6 +82,7 @@ func setupTestInfrastructure(t *testing.T, cfg *config.Config, useMockValidator
 		jwtGenerator:     jwtGenerator,
 		jwtValidator:     jwtValidator,
 		mockJwtValidator: mockJwtValidator,
+		mockResolver:     mockResolver,
 		app:              app,
 	}
 }
@@ -89,6 +94,9 @@ func setupTestInfrastructureSimple(t *testing.T, cfg *config.Config) *testSetup
 	mockRedis := redisPkg.InitMockRedis(t)
 	mockDB := sqldbPkg.InitMockDb(t)
 
+	// Migrate short link, click and domain tables; redirects look up the domain of the request host
+	require.NoError(t, mockDB.AutoMigrate(&model.ShortLink{}, &model.LinkClick{}, &model.Domain{}))
+
 	jwtGenerator, err := jwtUtils.NewJwtGenerator(privateKeyPath)
 	if err != nil {
 		t.Fatalf("Failed to create JWT generator: %v", err)
@@ -99,13 +107,15 @@ func setupTestInfrastructureSimple(t *testing.T, cfg *config.Config) *testSetup
 		t.Fatalf("Failed to create JWT validator: %v", err)
 	}
 
-	app := apipkg.New(cfg, mockRedis, mockDB, jwtGenerator, jwtValidator)
+	mockResolver := dnsMocks.NewResolver(t)
+	app := apipkg.New(cfg, mockRedis, mockDB, jwtGenerator, jwtValidator, mockResolver)
 
 	return &testSetup{
 		mockRedis:    mockRedis,
 		mockDB:       mockDB,
 		jwtGenerator: jwtGenerator,
 		jwtValidator: jwtValidator,
+		mockResolver: mockResolver,
 		app:          app,
 	}
 }
@@ -148,7 +158,8 @@ func executeRequest(api apipkg.Engine, method, endpoint string, body string) *ht
 // createTestUser creates a test user in the database with the given credentials
 func createTestUser(t *testing.T, db *gorm.DB, username, email, displayName, password string) *model.User {
 	t.Helper()
-	hashedPassword := utils.HashPassword(password)
+	hashedPassword, err := utils.HashPassword(password)
+	require.NoError(t, err)
 	testUser := &model.User{
 		Username:    username,
 		Password:    hashedPassword,
@@ -167,8 +178,12 @@ func createTestUserWithDefaults(t *testing.T, db *gorm.DB) *model.User {
 // defaultTestConfig returns a default test configuration
 func defaultTestConfig() *config.Config {
 	return &config.Config{
-		ServiceName: "bookmark_service",
-		InstanceId:  "",
+		ServiceName:         "bookmark_service",
+		InstanceId:          "",
+		ShortUrlBase:        "http://localhost:8080/v1/links/redirect/",
+		PreviewFetchTimeout: time.Second,
+		PendingLinkStatus:   http.StatusForbidden,
+		PendingLinkMessage:  "Coming soon",
 	}
 }
 
@@ -237,3 +252,15 @@ func executeGetRequestWithAuth(api apipkg.Engine, endpoint, token string) *httpt
 	api.ServeHTTP(rec, req)
 	return rec
 }
+
+// executeRequestWithAuth executes an HTTP request with a raw JSON body (may be empty) and Authorization header
+func executeRequestWithAuth(api apipkg.Engine, method, endpoint, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, endpoint, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	return rec
+}
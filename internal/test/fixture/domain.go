@@ -0,0 +1,50 @@
+package fixture
+
+import (
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+// DomainFixture is a fixture for the Domain model.
+// It provides a verified and an unverified domain owned by ShortLinkOwnerID.
+type DomainFixture struct {
+	// db is the database connection used by the fixture.
+	db *gorm.DB
+}
+
+func (d *DomainFixture) SetupDB(db *gorm.DB) {
+	d.db = db
+}
+
+func (d *DomainFixture) DB() *gorm.DB {
+	return d.db
+}
+
+func (d *DomainFixture) Migrate() error {
+	return d.db.AutoMigrate(&model.Domain{})
+}
+
+func (d *DomainFixture) GenerateData() error {
+	db := d.db.Session(&gorm.Session{})
+
+	verifiedAt := time.Now().UTC().Add(-time.Hour)
+	domains := []*model.Domain{
+		{
+			Name:       "go.example.com",
+			OwnerId:    ShortLinkOwnerID,
+			Token:      "verified-token",
+			CreatedAt:  verifiedAt.Add(-time.Hour),
+			VerifiedAt: &verifiedAt,
+		},
+		{
+			Name:      "links.example.org",
+			OwnerId:   ShortLinkOwnerID,
+			Token:     "pending-token",
+			CreatedAt: verifiedAt,
+		},
+	}
+
+	return db.Create(domains).Error
+}
@@ -0,0 +1,79 @@
+package fixture
+
+import (
+	"time"
+
+	"github.com/vincent-tien/bookmark-management/internal/model"
+	"gorm.io/gorm"
+)
+
+// ShortLinkOwnerID is the owner of the owned links created by ShortLinkFixture.
+const ShortLinkOwnerID = "5b8a3f4e-6c1d-4e2a-9f7b-2d3c4b5a6e7f"
+
+// ShortLinkFixture is a fixture for the ShortLink model.
+// It provides an active, an expired and a disabled anonymous link and two
+// links owned by ShortLinkOwnerID, the first of them with a recorded click.
+type ShortLinkFixture struct {
+	// db is the database connection used by the fixture.
+	db *gorm.DB
+}
+
+func (s *ShortLinkFixture) SetupDB(db *gorm.DB) {
+	s.db = db
+}
+
+func (s *ShortLinkFixture) DB() *gorm.DB {
+	return s.db
+}
+
+func (s *ShortLinkFixture) Migrate() error {
+	return s.db.AutoMigrate(&model.ShortLink{}, &model.LinkClick{})
+}
+
+func (s *ShortLinkFixture) GenerateData() error {
+	db := s.db.Session(&gorm.Session{})
+
+	future := time.Now().UTC().Add(time.Hour)
+	past := time.Now().UTC().Add(-time.Hour)
+	owner := ShortLinkOwnerID
+
+	links := []*model.ShortLink{
+		{
+			Code:      "active01",
+			Target:    "https://google.com",
+			ExpiresAt: &future,
+		},
+		{
+			Code:   "forever1",
+			Target: "https://example.com",
+		},
+		{
+			Code:      "expired1",
+			Target:    "https://expired.example.com",
+			ExpiresAt: &past,
+		},
+		{
+			Code:     "disabled",
+			Target:   "https://disabled.example.com",
+			Disabled: true,
+		},
+		{
+			Code:      "owned001",
+			Target:    "https://golang.org/doc",
+			OwnerId:   &owner,
+			CreatedAt: past.Add(-time.Hour),
+		},
+		{
+			Code:      "owned002",
+			Target:    "https://example.org/50%off",
+			OwnerId:   &owner,
+			CreatedAt: past,
+			ExpiresAt: &future,
+		},
+	}
+	if err := db.CreateInBatches(links, 10).Error; err != nil {
+		return err
+	}
+
+	return db.Create(&model.LinkClick{Code: "owned001", ClickedAt: past}).Error
+}
@@ -0,0 +1,280 @@
+package destpolicy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrRejected is wrapped by every error returned for a destination that violates the policy.
+var ErrRejected = errors.New("destination rejected")
+
+// Reasons a destination is rejected; all of them wrap ErrRejected.
+var (
+	ErrInvalidUrl       = fmt.Errorf("%w: invalid URL", ErrRejected)
+	ErrSchemeNotAllowed = fmt.Errorf("%w: scheme is not allowed", ErrRejected)
+	ErrPrivateAddress   = fmt.Errorf("%w: private or local addresses are not allowed", ErrRejected)
+	ErrSelfLink         = fmt.Errorf("%w: links to this service are not allowed", ErrRejected)
+	ErrDomainBlocked    = fmt.Errorf("%w: domain is blocked", ErrRejected)
+	ErrDomainNotAllowed = fmt.Errorf("%w: domain is not on the allowlist", ErrRejected)
+	ErrUnsafe           = fmt.Errorf("%w: destination is reported as unsafe", ErrRejected)
+)
+
+// DefaultSchemes are the schemes allowed when Config.Schemes is empty.
+var DefaultSchemes = []string{"http", "https"}
+
+// nonPublicNets are the non-public IPv4 ranges the net.IP predicates do not cover.
+var nonPublicNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // this network
+	mustParseCIDR("100.64.0.0/10"), // carrier-grade NAT
+}
+
+//go:generate mockery --name=DomainResolver --filename=domain_resolver.go
+
+// DomainResolver defines the interface for looking up the custom domains this service
+// serves links under, which must not be used as destinations either.
+type DomainResolver interface {
+	// ResolveHost returns the custom domain served at the host, or an empty string if there is none.
+	ResolveHost(ctx context.Context, host string) (string, error)
+}
+
+//go:generate mockery --name=ReputationChecker --filename=reputation_checker.go
+
+// ReputationChecker defines the interface for external URL reputation services,
+// such as phishing and malware lists. Implementations should cache their verdicts,
+// since redirects are checked as well.
+type ReputationChecker interface {
+	// IsUnsafe reports whether the URL is known to be malicious.
+	IsUnsafe(ctx context.Context, u *url.URL) (bool, error)
+}
+
+// Config holds the rules of a destination policy.
+// Domain lists match the domain itself and all of its subdomains.
+type Config struct {
+	// Schemes allowed in destinations, DefaultSchemes when empty
+	Schemes []string
+	// SelfHosts are the host names this service is reachable under
+	SelfHosts []string
+	// CustomDomains looks up the custom domains served by this service, nil to skip the check
+	CustomDomains DomainResolver
+	// Blocklist holds domains that are always rejected
+	Blocklist []string
+	// Allowlist restricts destinations to these domains when not empty
+	Allowlist []string
+	// Reputation is consulted last, nil to skip the check
+	Reputation ReputationChecker
+}
+
+//go:generate mockery --name=Policy --filename=policy.go
+
+// Policy defines the interface for deciding whether a URL may be used as a short link destination.
+type Policy interface {
+	// Check returns nil for acceptable destinations and an error wrapping ErrRejected otherwise.
+	// Other errors mean the custom domain or reputation check could not be completed.
+	Check(ctx context.Context, rawUrl string) error
+}
+
+type policy struct {
+	schemes       map[string]struct{}
+	selfHosts     []string
+	customDomains DomainResolver
+	blocklist     []string
+	allowlist     []string
+	reputation    ReputationChecker
+}
+
+// New creates a Policy from the config.
+func New(cfg Config) Policy {
+	schemes := cfg.Schemes
+	if len(schemes) == 0 {
+		schemes = DefaultSchemes
+	}
+
+	p := &policy{
+		schemes:       make(map[string]struct{}, len(schemes)),
+		selfHosts:     normalizeDomains(cfg.SelfHosts),
+		customDomains: cfg.CustomDomains,
+		blocklist:     normalizeDomains(cfg.Blocklist),
+		allowlist:     normalizeDomains(cfg.Allowlist),
+		reputation:    cfg.Reputation,
+	}
+	for _, scheme := range schemes {
+		p.schemes[strings.ToLower(scheme)] = struct{}{}
+	}
+
+	return p
+}
+
+// Check returns nil for acceptable destinations and an error wrapping ErrRejected otherwise.
+func (p *policy) Check(ctx context.Context, rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ErrInvalidUrl
+	}
+	if _, ok := p.schemes[strings.ToLower(u.Scheme)]; !ok {
+		return ErrSchemeNotAllowed
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return ErrInvalidUrl
+	}
+	if isLocalHost(host) {
+		return ErrPrivateAddress
+	}
+	if matchesDomain(host, p.selfHosts) {
+		return ErrSelfLink
+	}
+	if matchesDomain(host, p.blocklist) {
+		return ErrDomainBlocked
+	}
+	if len(p.allowlist) > 0 && !matchesDomain(host, p.allowlist) {
+		return ErrDomainNotAllowed
+	}
+
+	// Custom domains are looked up only once the static rules passed
+	if p.customDomains != nil {
+		domain, err := p.customDomains.ResolveHost(ctx, host)
+		if err != nil {
+			return fmt.Errorf("custom domain check: %w", err)
+		}
+		if domain != "" {
+			return ErrSelfLink
+		}
+	}
+
+	if p.reputation != nil {
+		unsafe, err := p.reputation.IsUnsafe(ctx, u)
+		if err != nil {
+			return fmt.Errorf("reputation check: %w", err)
+		}
+		if unsafe {
+			return ErrUnsafe
+		}
+	}
+
+	return nil
+}
+
+// LoadDomainList reads a domain list file with one domain per line.
+// Blank lines and lines starting with '#' are ignored. An empty path yields an empty list.
+func LoadDomainList(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+
+	return domains, scanner.Err()
+}
+
+// isLocalHost reports whether the host is a loopback, private or otherwise non-public
+// address, including IPv4 addresses in the short and numeric forms clients still accept,
+// such as http://2130706433/, http://127.1/ or http://0x7f.1/.
+func isLocalHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ip = parseIPv4(host)
+	}
+	if ip == nil {
+		return false
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIPv4 parses IPv4 addresses the way inet_aton does: one to four parts, each decimal,
+// octal with a leading 0 or hexadecimal with 0x, the last part filling the remaining bytes.
+// Returns nil if the host is not such an address.
+func parseIPv4(host string) net.IP {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	var n uint64
+	for i, part := range parts {
+		bits := 8
+		if i == len(parts)-1 {
+			bits = 8 * (4 - i)
+		}
+		v, err := strconv.ParseUint(part, 0, bits)
+		if err != nil {
+			return nil
+		}
+		n = n<<bits | v
+	}
+
+	return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// matchesDomain reports whether the host is one of the domains or a subdomain of one.
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeDomains lower-cases the domains and strips ports and trailing dots.
+func normalizeDomains(domains []string) []string {
+	res := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if h, _, err := net.SplitHostPort(domain); err == nil {
+			domain = h
+		}
+		domain = strings.TrimSuffix(domain, ".")
+		if domain != "" {
+			res = append(res, domain)
+		}
+	}
+
+	return res
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
@@ -0,0 +1,182 @@
+package destpolicy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vincent-tien/bookmark-management/pkg/destpolicy/mocks"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		SelfHosts: []string{"sho.rt", "localhost:8080"},
+		Blocklist: []string{"evil.example", "Bad.Example."},
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         Config
+		url         string
+		expectedErr error
+	}{
+		{name: "https destination", cfg: cfg, url: "https://example.com/path?q=1"},
+		{name: "http destination", cfg: cfg, url: "http://example.com"},
+		{name: "javascript scheme", cfg: cfg, url: "javascript:alert(1)", expectedErr: ErrSchemeNotAllowed},
+		{name: "data scheme", cfg: cfg, url: "data:text/html,<script>alert(1)</script>", expectedErr: ErrSchemeNotAllowed},
+		{name: "ftp not allowed by default", cfg: cfg, url: "ftp://example.com/file", expectedErr: ErrSchemeNotAllowed},
+		{name: "ftp allowed when configured", cfg: Config{Schemes: []string{"FTP"}}, url: "ftp://example.com/file"},
+		{name: "missing host", cfg: cfg, url: "https:///path", expectedErr: ErrInvalidUrl},
+		{name: "unparsable", cfg: cfg, url: "http://[::1", expectedErr: ErrInvalidUrl},
+		{name: "loopback address", cfg: cfg, url: "http://127.0.0.1:6379/", expectedErr: ErrPrivateAddress},
+		{name: "private address", cfg: cfg, url: "http://10.0.0.5/admin", expectedErr: ErrPrivateAddress},
+		{name: "cloud metadata address", cfg: cfg, url: "http://169.254.169.254/latest/meta-data", expectedErr: ErrPrivateAddress},
+		{name: "ipv6 loopback", cfg: cfg, url: "http://[::1]/", expectedErr: ErrPrivateAddress},
+		{name: "decimal ipv4", cfg: cfg, url: "http://2130706433/", expectedErr: ErrPrivateAddress},
+		{name: "short ipv4", cfg: cfg, url: "http://127.1/", expectedErr: ErrPrivateAddress},
+		{name: "hex short ipv4", cfg: cfg, url: "http://0x7f.1/", expectedErr: ErrPrivateAddress},
+		{name: "octal ipv4", cfg: cfg, url: "http://0177.0.0.1/", expectedErr: ErrPrivateAddress},
+		{name: "ipv4 mapped ipv6 loopback", cfg: cfg, url: "http://[::ffff:127.0.0.1]/", expectedErr: ErrPrivateAddress},
+		{name: "carrier-grade nat address", cfg: cfg, url: "http://100.64.0.1/", expectedErr: ErrPrivateAddress},
+		{name: "public short ipv4", cfg: cfg, url: "http://8.8.2056/"},
+		{name: "numeric looking domain", cfg: cfg, url: "http://1.2.3.4.5.example/"},
+		{name: "localhost name", cfg: Config{}, url: "http://LOCALHOST./", expectedErr: ErrPrivateAddress},
+		{name: "public address", cfg: cfg, url: "http://203.0.113.10/"},
+		{name: "self link", cfg: cfg, url: "https://sho.rt/v1/links/redirect/abc", expectedErr: ErrSelfLink},
+		{name: "self link subdomain", cfg: cfg, url: "https://www.Sho.rt/abc", expectedErr: ErrSelfLink},
+		{name: "blocked domain", cfg: cfg, url: "https://evil.example/login", expectedErr: ErrDomainBlocked},
+		{name: "blocked subdomain", cfg: cfg, url: "https://login.bad.example/", expectedErr: ErrDomainBlocked},
+		{name: "similar domain is not blocked", cfg: cfg, url: "https://notevil.example/"},
+		{name: "allowlisted domain", cfg: Config{Allowlist: []string{"example.com"}}, url: "https://docs.example.com/"},
+		{name: "domain not on allowlist", cfg: Config{Allowlist: []string{"example.com"}}, url: "https://example.org/", expectedErr: ErrDomainNotAllowed},
+		{name: "blocklist wins over allowlist", cfg: Config{Allowlist: []string{"example.com"}, Blocklist: []string{"bad.example.com"}}, url: "https://bad.example.com/", expectedErr: ErrDomainBlocked},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := New(tc.cfg).Check(t.Context(), tc.url)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, ErrRejected)
+			}
+		})
+	}
+}
+
+func TestPolicy_Check_Reputation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		unsafe        bool
+		checkErr      error
+		expectedErr   error
+		expectRejects bool
+	}{
+		{name: "safe destination"},
+		{name: "unsafe destination", unsafe: true, expectedErr: ErrUnsafe, expectRejects: true},
+		{name: "checker unavailable", checkErr: assert.AnError, expectedErr: assert.AnError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := mocks.NewReputationChecker(t)
+			checker.On("IsUnsafe", mock.Anything, mock.Anything).Return(tc.unsafe, tc.checkErr).Once()
+
+			err := New(Config{Reputation: checker}).Check(t.Context(), "https://example.com")
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectRejects, errors.Is(err, ErrRejected))
+		})
+	}
+}
+
+func TestPolicy_Check_ReputationSkippedForRejected(t *testing.T) {
+	t.Parallel()
+
+	// The checker fails the test if it is called
+	checker := mocks.NewReputationChecker(t)
+
+	err := New(Config{Reputation: checker}).Check(t.Context(), "javascript:alert(1)")
+
+	assert.ErrorIs(t, err, ErrSchemeNotAllowed)
+}
+
+func TestPolicy_Check_CustomDomains(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		url           string
+		host          string
+		domain        string
+		resolveErr    error
+		expectedErr   error
+		expectRejects bool
+	}{
+		{name: "other host", url: "https://example.com", host: "example.com"},
+		{
+			name:          "verified custom domain",
+			url:           "https://Go.Example.com./abc",
+			host:          "go.example.com",
+			domain:        "go.example.com",
+			expectedErr:   ErrSelfLink,
+			expectRejects: true,
+		},
+		{name: "lookup fails", url: "https://example.com", host: "example.com", resolveErr: assert.AnError, expectedErr: assert.AnError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := mocks.NewDomainResolver(t)
+			resolver.On("ResolveHost", mock.Anything, tc.host).Return(tc.domain, tc.resolveErr).Once()
+
+			err := New(Config{CustomDomains: resolver}).Check(t.Context(), tc.url)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectRejects, errors.Is(err, ErrRejected))
+		})
+	}
+}
+
+func TestPolicy_Check_CustomDomainsSkippedForRejected(t *testing.T) {
+	t.Parallel()
+
+	// The resolver fails the test if it is called
+	resolver := mocks.NewDomainResolver(t)
+
+	err := New(Config{CustomDomains: resolver, Blocklist: []string{"example.com"}}).Check(t.Context(), "https://example.com")
+
+	assert.ErrorIs(t, err, ErrDomainBlocked)
+}
+
+func TestLoadDomainList(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# phishing\nevil.example\n\n  bad.example  \n"), 0o600))
+
+	domains, err := LoadDomainList(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evil.example", "bad.example"}, domains)
+
+	domains, err = LoadDomainList("")
+	require.NoError(t, err)
+	assert.Empty(t, domains)
+
+	_, err = LoadDomainList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
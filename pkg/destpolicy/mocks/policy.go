@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Policy is an autogenerated mock type for the Policy type
+type Policy struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: ctx, rawUrl
+func (_m *Policy) Check(ctx context.Context, rawUrl string) error {
+	ret := _m.Called(ctx, rawUrl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, rawUrl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPolicy creates a new instance of Policy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPolicy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Policy {
+	mock := &Policy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	url "net/url"
+)
+
+// ReputationChecker is an autogenerated mock type for the ReputationChecker type
+type ReputationChecker struct {
+	mock.Mock
+}
+
+// IsUnsafe provides a mock function with given fields: ctx, u
+func (_m *ReputationChecker) IsUnsafe(ctx context.Context, u *url.URL) (bool, error) {
+	ret := _m.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsUnsafe")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *url.URL) (bool, error)); ok {
+		return rf(ctx, u)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *url.URL) bool); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *url.URL) error); ok {
+		r1 = rf(ctx, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReputationChecker creates a new instance of ReputationChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReputationChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReputationChecker {
+	mock := &ReputationChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,55 @@
+package dnsverify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// RecordPrefix is prepended to a domain to name the TXT record proving its ownership.
+const RecordPrefix = "_bookmark-verify."
+
+// valuePrefix is prepended to the token in the TXT record value.
+const valuePrefix = "bookmark-verify="
+
+//go:generate mockery --name=Resolver --filename=resolver.go
+
+// Resolver defines the interface for looking up DNS TXT records.
+// It is implemented by *net.Resolver, e.g. net.DefaultResolver.
+type Resolver interface {
+	// LookupTXT returns the TXT records of the given name.
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// RecordName returns the name of the TXT record proving ownership of the domain.
+func RecordName(domain string) string {
+	return RecordPrefix + domain
+}
+
+// RecordValue returns the value of the TXT record carrying the token.
+func RecordValue(token string) string {
+	return valuePrefix + token
+}
+
+// HasToken reports whether the domain publishes the token in its verification TXT record.
+// A missing record is not an error; lookups that fail for other reasons are.
+func HasToken(ctx context.Context, r Resolver, domain, token string) (bool, error) {
+	records, err := r.LookupTXT(ctx, RecordName(domain))
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	want := RecordValue(token)
+	for _, record := range records {
+		if strings.TrimSpace(record) == want {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,55 @@
+package dnsverify
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vincent-tien/bookmark-management/pkg/dnsverify/mocks"
+)
+
+func TestHasToken(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		records     []string
+		lookupErr   error
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name:     "token published",
+			records:  []string{"v=spf1 -all", " bookmark-verify=abc123 "},
+			expected: true,
+		},
+		{
+			name:    "other token published",
+			records: []string{"bookmark-verify=other"},
+		},
+		{
+			name:      "no record",
+			lookupErr: &net.DNSError{Err: "no such host", Name: "_bookmark-verify.go.example.com", IsNotFound: true},
+		},
+		{
+			name:        "lookup fails",
+			lookupErr:   &net.DNSError{Err: "i/o timeout", Name: "_bookmark-verify.go.example.com", IsTimeout: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := mocks.NewResolver(t)
+			resolver.On("LookupTXT", mock.Anything, "_bookmark-verify.go.example.com").Return(tc.records, tc.lookupErr)
+
+			ok, err := HasToken(t.Context(), resolver, "go.example.com", "abc123")
+
+			assert.Equal(t, tc.expected, ok)
+			assert.Equal(t, tc.expectedErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE short_links
+(
+    code       VARCHAR(32) PRIMARY KEY,
+    target     TEXT        NOT NULL,
+    owner_id   UUID        REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ,
+    disabled   BOOLEAN     NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_short_links_owner_id ON short_links (owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS short_links;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE link_clicks
+(
+    id            BIGSERIAL PRIMARY KEY,
+    code          VARCHAR(32) NOT NULL,
+    clicked_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    referrer      TEXT        NOT NULL DEFAULT '',
+    referrer_host VARCHAR(255) NOT NULL DEFAULT '',
+    user_agent    TEXT        NOT NULL DEFAULT '',
+    device        VARCHAR(20) NOT NULL DEFAULT '',
+    browser       VARCHAR(50) NOT NULL DEFAULT '',
+    os            VARCHAR(50) NOT NULL DEFAULT '',
+    ip_address    VARCHAR(45) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_link_clicks_code_clicked_at ON link_clicks (code, clicked_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS link_clicks;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE link_clicks ADD COLUMN variant VARCHAR(32) NOT NULL DEFAULT 'default';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE link_clicks DROP COLUMN IF EXISTS variant;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE domains
+(
+    name        VARCHAR(253) PRIMARY KEY,
+    owner_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token       VARCHAR(64) NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    verified_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_domains_owner_id ON domains (owner_id);
+
+-- Links on custom domains are stored under "code@domain"
+ALTER TABLE short_links ALTER COLUMN code TYPE VARCHAR(300);
+ALTER TABLE link_clicks ALTER COLUMN code TYPE VARCHAR(300);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM link_clicks WHERE code LIKE '%@%';
+DELETE FROM short_links WHERE code LIKE '%@%';
+ALTER TABLE link_clicks ALTER COLUMN code TYPE VARCHAR(32);
+ALTER TABLE short_links ALTER COLUMN code TYPE VARCHAR(32);
+DROP TABLE IF EXISTS domains;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE short_links
+    ADD COLUMN redirect_status SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN no_index BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN referrer_policy VARCHAR(32) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE short_links
+    DROP COLUMN IF EXISTS referrer_policy,
+    DROP COLUMN IF EXISTS no_index,
+    DROP COLUMN IF EXISTS redirect_status;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Several users may claim a domain; the first to verify it takes it over
+ALTER TABLE domains DROP CONSTRAINT domains_pkey;
+ALTER TABLE domains ADD PRIMARY KEY (name, owner_id);
+
+CREATE UNIQUE INDEX idx_domains_verified_name ON domains (name) WHERE verified_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_domains_verified_name;
+
+-- Keep the verified claim of every domain, or its oldest claim if none is verified
+DELETE FROM domains d
+WHERE EXISTS (SELECT 1
+              FROM domains o
+              WHERE o.name = d.name
+                AND o.owner_id <> d.owner_id
+                AND (o.verified_at IS NOT NULL
+                    OR (d.verified_at IS NULL AND (o.created_at, o.owner_id) < (d.created_at, d.owner_id))));
+
+ALTER TABLE domains DROP CONSTRAINT domains_pkey;
+ALTER TABLE domains ADD PRIMARY KEY (name);
+-- +goose StatementEnd
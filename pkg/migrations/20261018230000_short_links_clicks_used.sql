@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Redirects taken from the Redis counter of a click-limited link, so a lost counter can be rebuilt
+ALTER TABLE short_links ADD COLUMN clicks_used BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE short_links DROP COLUMN IF EXISTS clicks_used;
+-- +goose StatementEnd
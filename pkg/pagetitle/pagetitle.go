@@ -0,0 +1,143 @@
+package pagetitle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// maxBodySize bounds how much of a page is read looking for its title.
+	maxBodySize = 256 << 10
+	// maxTitleLength bounds the length of a returned title in characters.
+	maxTitleLength = 200
+	maxRedirects   = 5
+	userAgent      = "Mozilla/5.0 (compatible; LinkPreview/1.0)"
+)
+
+// ErrForbiddenAddress is returned when a page resolves to a loopback, private or otherwise non-public address.
+var ErrForbiddenAddress = errors.New("forbidden address")
+
+//go:generate mockery --name=Fetcher --filename=fetcher.go
+
+// Fetcher defines the interface for looking up the title of a web page.
+type Fetcher interface {
+	// Fetch downloads the page and returns its trimmed <title>, or an empty string if it has none.
+	Fetch(ctx context.Context, url string) (string, error)
+}
+
+type fetcher struct {
+	client *http.Client
+}
+
+// NewFetcher creates a Fetcher whose requests, including redirects, time out after the given duration.
+// Only public addresses are contacted, so previews cannot be used to probe internal services.
+func NewFetcher(timeout time.Duration) Fetcher {
+	return newFetcher(timeout, false)
+}
+
+func newFetcher(timeout time.Duration, allowPrivate bool) *fetcher {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = rejectNonPublic
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &fetcher{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Fetch downloads the page and returns its trimmed <title>, or an empty string if it has none.
+func (f *fetcher) Fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q", req.URL.Scheme)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return "", nil
+	}
+
+	return parseTitle(io.LimitReader(resp.Body, maxBodySize)), nil
+}
+
+// parseTitle returns the text of the first <title> element, with whitespace collapsed.
+func parseTitle(r io.Reader) string {
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if atom.Lookup(name) != atom.Title {
+				continue
+			}
+			if z.Next() != html.TextToken {
+				return ""
+			}
+			return truncate(strings.Join(strings.Fields(string(z.Text())), " "))
+		}
+	}
+}
+
+func truncate(s string) string {
+	if utf8.RuneCountInString(s) <= maxTitleLength {
+		return s
+	}
+
+	return string([]rune(s)[:maxTitleLength-1]) + "…"
+}
+
+// rejectNonPublic refuses connections to addresses that are not reachable on the public internet.
+func rejectNonPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrForbiddenAddress
+	}
+
+	return nil
+}
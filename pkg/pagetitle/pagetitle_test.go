@@ -0,0 +1,100 @@
+package pagetitle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedTitle string
+		expectError   bool
+	}{
+		{
+			name: "title with collapsed whitespace",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				_, _ = w.Write([]byte("<html><head><title>\n  Example   Domain\n</title></head><body><title>Other</title></body></html>"))
+			},
+			expectedTitle: "Example Domain",
+		},
+		{
+			name: "title is truncated",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("<title>" + strings.Repeat("a", 300) + "</title>"))
+			},
+			expectedTitle: strings.Repeat("a", maxTitleLength-1) + "…",
+		},
+		{
+			name: "page without title",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("<html><body>hello</body></html>"))
+			},
+			expectedTitle: "",
+		},
+		{
+			name: "not an html page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/pdf")
+				_, _ = w.Write([]byte("<title>Not parsed</title>"))
+			},
+			expectedTitle: "",
+		},
+		{
+			name: "error status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			title, err := newFetcher(time.Second, true).Fetch(t.Context(), server.URL)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTitle, title)
+		})
+	}
+}
+
+func TestFetcher_Fetch_RejectsPrivateAddresses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<title>Internal</title>"))
+	}))
+	defer server.Close()
+
+	_, err := NewFetcher(time.Second).Fetch(t.Context(), server.URL)
+
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+}
+
+func TestFetcher_Fetch_RejectsOtherSchemes(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFetcher(time.Second).Fetch(t.Context(), "file:///etc/passwd")
+
+	assert.Error(t, err)
+}
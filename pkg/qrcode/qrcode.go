@@ -0,0 +1,142 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Error correction levels, from the smallest code to the most damage tolerant.
+const (
+	LevelLow      = "L"
+	LevelMedium   = "M"
+	LevelQuartile = "Q"
+	LevelHigh     = "H"
+)
+
+// ErrInvalidColor is returned for colors that are not six hex digits.
+var ErrInvalidColor = errors.New("invalid color")
+
+// Options controls how a QR code is drawn.
+// Size is the requested width and height in pixels; codes that need more
+// modules than pixels are drawn one pixel per module instead.
+// Margin is the width of the quiet zone around the code in modules.
+type Options struct {
+	Size       int
+	Margin     int
+	Level      string
+	Foreground color.RGBA
+	Background color.RGBA
+}
+
+var levels = map[string]qr.ErrorCorrectionLevel{
+	LevelLow:      qr.L,
+	LevelMedium:   qr.M,
+	LevelQuartile: qr.Q,
+	LevelHigh:     qr.H,
+}
+
+// ParseHexColor parses a color written as six hex digits, with or without a leading '#'.
+func ParseHexColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return color.RGBA{}, ErrInvalidColor
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrInvalidColor
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// PNG draws the QR code of the content as a PNG image.
+func PNG(content string, opts Options) ([]byte, error) {
+	code, err := encode(content, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := code.Bounds().Dx() + 2*opts.Margin
+	scale := max(opts.Size/modules, 1)
+	size := max(opts.Size, modules)
+	// Center the code when the size is not a multiple of the module count
+	offset := (size - scale*modules) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{opts.Background, opts.Foreground})
+	for y := 0; y < code.Bounds().Dy(); y++ {
+		for x := 0; x < code.Bounds().Dx(); x++ {
+			if !isDark(code, x, y) {
+				continue
+			}
+			left := offset + (x+opts.Margin)*scale
+			top := offset + (y+opts.Margin)*scale
+			for py := top; py < top+scale; py++ {
+				for px := left; px < left+scale; px++ {
+					img.SetColorIndex(px, py, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SVG draws the QR code of the content as an SVG document.
+// The drawing is scalable, Size only sets its default width and height.
+func SVG(content string, opts Options) ([]byte, error) {
+	code, err := encode(content, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := code.Bounds().Dx() + 2*opts.Margin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, modules, modules, hexColor(opts.Background))
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, hexColor(opts.Foreground))
+	for y := 0; y < code.Bounds().Dy(); y++ {
+		for x := 0; x < code.Bounds().Dx(); x++ {
+			if isDark(code, x, y) {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x+opts.Margin, y+opts.Margin)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes(), nil
+}
+
+func encode(content, level string) (barcode.Barcode, error) {
+	ecl, ok := levels[level]
+	if !ok {
+		return nil, fmt.Errorf("unknown error correction level %q", level)
+	}
+
+	return qr.Encode(content, ecl, qr.Auto)
+}
+
+func isDark(code barcode.Barcode, x, y int) bool {
+	r, _, _, _ := code.At(x, y).RGBA()
+	return r == 0
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
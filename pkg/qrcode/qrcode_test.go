@@ -0,0 +1,110 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	black = color.RGBA{A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+func TestParseHexColor(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		input       string
+		expected    color.RGBA
+		expectedErr error
+	}{
+		{name: "without hash", input: "1a2b3c", expected: color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}},
+		{name: "with hash", input: "#FFFFFF", expected: white},
+		{name: "too short", input: "fff", expectedErr: ErrInvalidColor},
+		{name: "not hex", input: "zzzzzz", expectedErr: ErrInvalidColor},
+		{name: "sign is not a digit", input: "+fffff", expectedErr: ErrInvalidColor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := ParseHexColor(tc.input)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+func TestPNG(t *testing.T) {
+	t.Parallel()
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	data, err := PNG("https://example.com/abc", Options{Size: 256, Margin: 4, Level: LevelMedium, Foreground: red, Background: white})
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 256, img.Bounds().Dx())
+	assert.Equal(t, 256, img.Bounds().Dy())
+
+	// The quiet zone is background and the top-left finder pattern starts right after it
+	toRGBA := func(c color.Color) color.RGBA {
+		return color.RGBAModel.Convert(c).(color.RGBA)
+	}
+	assert.Equal(t, white, toRGBA(img.At(0, 0)))
+	code, err := encode("https://example.com/abc", LevelMedium)
+	require.NoError(t, err)
+	modules := code.Bounds().Dx() + 2*4
+	scale := 256 / modules
+	start := (256-scale*modules)/2 + 4*scale
+	assert.Equal(t, white, toRGBA(img.At(start-1, start-1)))
+	assert.Equal(t, red, toRGBA(img.At(start, start)))
+	// The finder pattern is seven modules wide
+	assert.Equal(t, red, toRGBA(img.At(start+7*scale-1, start)))
+	assert.Equal(t, white, toRGBA(img.At(start+7*scale, start)))
+}
+
+func TestPNG_SmallerThanModules(t *testing.T) {
+	t.Parallel()
+
+	data, err := PNG("https://example.com/abc", Options{Size: 10, Margin: 4, Level: LevelHigh, Foreground: black, Background: white})
+	require.NoError(t, err)
+
+	// Every module keeps at least one pixel
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Greater(t, img.Bounds().Dx(), 10)
+}
+
+func TestSVG(t *testing.T) {
+	t.Parallel()
+
+	data, err := SVG("https://example.com/abc", Options{Size: 300, Margin: 2, Level: LevelLow, Foreground: black, Background: white})
+	require.NoError(t, err)
+
+	svg := string(data)
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Contains(t, svg, `width="300" height="300"`)
+	assert.Regexp(t, `viewBox="0 0 (\d+) (\d+)"`, svg)
+	assert.Contains(t, svg, `fill="#ffffff"`)
+	assert.Contains(t, svg, `fill="#000000"`)
+	// The top-left finder pattern starts right after the margin
+	assert.Contains(t, svg, "M2 2h1v1h-1z")
+}
+
+func TestEncode_UnknownLevel(t *testing.T) {
+	t.Parallel()
+
+	_, err := PNG("https://example.com/abc", Options{Size: 256, Level: "X"})
+
+	assert.Error(t, err)
+}
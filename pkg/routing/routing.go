@@ -0,0 +1,258 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultVariant names the link's own target, used when no rule matches and the link has no split.
+const DefaultVariant = "default"
+
+// Limits of a single link's routing.
+const (
+	MaxRules    = 20
+	MaxVariants = 10
+	MaxWeight   = 1000
+)
+
+// Device classes rules can match, as reported by useragent.Parse.
+var Devices = []string{"desktop", "mobile", "tablet"}
+
+// ErrInvalidRouting is wrapped by every error returned by Validate.
+var ErrInvalidRouting = errors.New("invalid routing")
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Rule sends visitors matching all of its conditions to Url.
+// At least one condition must be set.
+type Rule struct {
+	// Name is recorded in analytics for visits the rule routes
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	// Device matches the device class of the visitor's user agent
+	Device string `json:"device,omitempty"`
+	// Language matches the visitor's preferred language, either exactly or as its
+	// primary subtag: "de" matches "de-AT", "pt-BR" matches "pt-BR" only
+	Language string `json:"language,omitempty"`
+	// Query matches visits carrying this query parameter
+	Query string `json:"query,omitempty"`
+	// QueryValue restricts Query to this value, any value when empty
+	QueryValue string `json:"query_value,omitempty"`
+}
+
+// Variant is one destination of a weighted split.
+type Variant struct {
+	// Name is recorded in analytics for visits sent to this variant
+	Name   string `json:"name"`
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// Routing sends visitors of one short link to different destinations.
+// Rules are evaluated in order and the first match wins; visitors no rule matches
+// are split across the variants by weight, or sent to the link's own target
+// if there are none.
+type Routing struct {
+	Rules    []Rule    `json:"rules,omitempty"`
+	Variants []Variant `json:"variants,omitempty"`
+}
+
+// Visitor holds the request details rules are matched against.
+type Visitor struct {
+	// Device class of the visitor's user agent
+	Device string
+	// AcceptLanguage is the raw Accept-Language header
+	AcceptLanguage string
+	// Query holds the query parameters of the visited short URL
+	Query url.Values
+	// Key identifies the visitor, so repeated visits land in the same variant.
+	// Visitors without a key get a random variant on every visit.
+	Key string
+}
+
+// IsEmpty reports whether the routing has neither rules nor variants.
+func (r *Routing) IsEmpty() bool {
+	return r == nil || len(r.Rules) == 0 && len(r.Variants) == 0
+}
+
+// Urls returns the destinations of all rules and variants.
+func (r *Routing) Urls() []string {
+	if r == nil {
+		return nil
+	}
+
+	urls := make([]string, 0, len(r.Rules)+len(r.Variants))
+	for _, rule := range r.Rules {
+		urls = append(urls, rule.Url)
+	}
+	for _, variant := range r.Variants {
+		urls = append(urls, variant.Url)
+	}
+
+	return urls
+}
+
+// Validate checks the limits, that names are unique and that every rule has a condition.
+// Returned errors wrap ErrInvalidRouting.
+func (r *Routing) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if len(r.Rules) > MaxRules {
+		return fmt.Errorf("%w: at most %d rules", ErrInvalidRouting, MaxRules)
+	}
+	if len(r.Variants) > MaxVariants {
+		return fmt.Errorf("%w: at most %d variants", ErrInvalidRouting, MaxVariants)
+	}
+
+	names := make(map[string]struct{}, len(r.Rules)+len(r.Variants))
+	checkName := func(name string) error {
+		if !namePattern.MatchString(name) {
+			return fmt.Errorf("%w: name %q must be 1 to 32 letters, digits, \"-\" or \"_\"", ErrInvalidRouting, name)
+		}
+		key := strings.ToLower(name)
+		if key == DefaultVariant {
+			return fmt.Errorf("%w: name %q is reserved", ErrInvalidRouting, name)
+		}
+		if _, ok := names[key]; ok {
+			return fmt.Errorf("%w: name %q is used twice", ErrInvalidRouting, name)
+		}
+		names[key] = struct{}{}
+		return nil
+	}
+
+	for _, rule := range r.Rules {
+		if err := checkName(rule.Name); err != nil {
+			return err
+		}
+		if rule.Url == "" {
+			return fmt.Errorf("%w: rule %q has no url", ErrInvalidRouting, rule.Name)
+		}
+		if rule.Device == "" && rule.Language == "" && rule.Query == "" {
+			return fmt.Errorf("%w: rule %q has no condition", ErrInvalidRouting, rule.Name)
+		}
+		if rule.Device != "" && !slices.Contains(Devices, rule.Device) {
+			return fmt.Errorf("%w: rule %q has unknown device %q", ErrInvalidRouting, rule.Name, rule.Device)
+		}
+		if rule.QueryValue != "" && rule.Query == "" {
+			return fmt.Errorf("%w: rule %q has a query value without query", ErrInvalidRouting, rule.Name)
+		}
+	}
+	for _, variant := range r.Variants {
+		if err := checkName(variant.Name); err != nil {
+			return err
+		}
+		if variant.Url == "" {
+			return fmt.Errorf("%w: variant %q has no url", ErrInvalidRouting, variant.Name)
+		}
+		if variant.Weight < 1 || variant.Weight > MaxWeight {
+			return fmt.Errorf("%w: variant %q needs a weight from 1 to %d", ErrInvalidRouting, variant.Name, MaxWeight)
+		}
+	}
+
+	return nil
+}
+
+// Choose returns the destination and the name of the rule or variant for the visitor.
+// It returns false if the visitor is to be sent to the link's own target.
+func (r *Routing) Choose(v Visitor) (string, string, bool) {
+	if r == nil {
+		return "", "", false
+	}
+
+	for _, rule := range r.Rules {
+		if rule.matches(v) {
+			return rule.Url, rule.Name, true
+		}
+	}
+
+	total := 0
+	for _, variant := range r.Variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return "", "", false
+	}
+
+	bucket := pickBucket(v.Key, total)
+	for _, variant := range r.Variants {
+		if bucket < variant.Weight {
+			return variant.Url, variant.Name, true
+		}
+		bucket -= variant.Weight
+	}
+
+	return "", "", false
+}
+
+func (rule Rule) matches(v Visitor) bool {
+	if rule.Device != "" && !strings.EqualFold(rule.Device, v.Device) {
+		return false
+	}
+	if rule.Language != "" && !matchesLanguage(rule.Language, PreferredLanguage(v.AcceptLanguage)) {
+		return false
+	}
+	if rule.Query != "" {
+		values, ok := v.Query[rule.Query]
+		if !ok || rule.QueryValue != "" && !slices.Contains(values, rule.QueryValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesLanguage reports whether the language tag is the wanted one or one of its subtags.
+func matchesLanguage(want, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	return strings.EqualFold(want, tag) ||
+		len(tag) > len(want) && tag[len(want)] == '-' && strings.EqualFold(want, tag[:len(want)])
+}
+
+// PreferredLanguage returns the language tag with the highest quality in an Accept-Language
+// header, the first of them on a tie. It returns an empty string if no language is accepted.
+func PreferredLanguage(header string) string {
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > bestQuality {
+			best, bestQuality = tag, quality
+		}
+	}
+
+	return best
+}
+
+// pickBucket maps the visitor key to a bucket in [0, total), at random without a key.
+func pickBucket(key string, total int) int {
+	if key == "" {
+		return rand.IntN(total)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum64() % uint64(total))
+}
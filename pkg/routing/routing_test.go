@@ -0,0 +1,208 @@
+package routing
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouting_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		routing     *Routing
+		expectedErr error
+	}{
+		{name: "nil routing", routing: nil},
+		{
+			name: "rules and variants",
+			routing: &Routing{
+				Rules:    []Rule{{Name: "mobile", Url: "https://m.example.com", Device: "mobile"}},
+				Variants: []Variant{{Name: "a", Url: "https://a.example.com", Weight: 1}, {Name: "b", Url: "https://b.example.com", Weight: 3}},
+			},
+		},
+		{
+			name:        "rule without condition",
+			routing:     &Routing{Rules: []Rule{{Name: "all", Url: "https://example.com"}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "unknown device",
+			routing:     &Routing{Rules: []Rule{{Name: "tv", Url: "https://example.com", Device: "tv"}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "query value without query",
+			routing:     &Routing{Rules: []Rule{{Name: "promo", Url: "https://example.com", Device: "mobile", QueryValue: "x"}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name: "duplicate names regardless of case",
+			routing: &Routing{
+				Rules:    []Rule{{Name: "A", Url: "https://example.com", Device: "mobile"}},
+				Variants: []Variant{{Name: "a", Url: "https://example.com", Weight: 1}},
+			},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "reserved name",
+			routing:     &Routing{Variants: []Variant{{Name: "Default", Url: "https://example.com", Weight: 1}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "invalid name",
+			routing:     &Routing{Variants: []Variant{{Name: "a b", Url: "https://example.com", Weight: 1}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "zero weight",
+			routing:     &Routing{Variants: []Variant{{Name: "a", Url: "https://example.com"}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "variant without url",
+			routing:     &Routing{Variants: []Variant{{Name: "a", Weight: 1}}},
+			expectedErr: ErrInvalidRouting,
+		},
+		{
+			name:        "too many variants",
+			routing:     &Routing{Variants: make([]Variant, MaxVariants+1)},
+			expectedErr: ErrInvalidRouting,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorIs(t, tc.routing.Validate(), tc.expectedErr)
+		})
+	}
+}
+
+func TestRouting_Choose(t *testing.T) {
+	t.Parallel()
+
+	routing := &Routing{
+		Rules: []Rule{
+			{Name: "newsletter", Url: "https://example.com/news", Query: "src", QueryValue: "email"},
+			{Name: "german-mobile", Url: "https://m.example.de", Device: "mobile", Language: "de"},
+			{Name: "tracked", Url: "https://example.com/tracked", Query: "ref"},
+		},
+		Variants: []Variant{{Name: "only", Url: "https://example.com/split", Weight: 1}},
+	}
+
+	testCases := []struct {
+		name            string
+		routing         *Routing
+		visitor         Visitor
+		expectedUrl     string
+		expectedVariant string
+		expectedOk      bool
+	}{
+		{
+			name:            "query value",
+			routing:         routing,
+			visitor:         Visitor{Device: "mobile", AcceptLanguage: "de", Query: url.Values{"src": {"email"}}},
+			expectedUrl:     "https://example.com/news",
+			expectedVariant: "newsletter",
+			expectedOk:      true,
+		},
+		{
+			name:            "device and language subtag",
+			routing:         routing,
+			visitor:         Visitor{Device: "mobile", AcceptLanguage: "en;q=0.5, de-AT"},
+			expectedUrl:     "https://m.example.de",
+			expectedVariant: "german-mobile",
+			expectedOk:      true,
+		},
+		{
+			name:            "query without value",
+			routing:         routing,
+			visitor:         Visitor{Device: "desktop", AcceptLanguage: "de", Query: url.Values{"ref": {""}, "src": {"ad"}}},
+			expectedUrl:     "https://example.com/tracked",
+			expectedVariant: "tracked",
+			expectedOk:      true,
+		},
+		{
+			name:            "no rule matches falls back to the split",
+			routing:         routing,
+			visitor:         Visitor{Device: "mobile", AcceptLanguage: "deu"},
+			expectedUrl:     "https://example.com/split",
+			expectedVariant: "only",
+			expectedOk:      true,
+		},
+		{
+			name:    "no rule matches and no split",
+			routing: &Routing{Rules: routing.Rules},
+			visitor: Visitor{Device: "desktop"},
+		},
+		{
+			name:    "nil routing",
+			routing: nil,
+			visitor: Visitor{Device: "desktop"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			url, variant, ok := tc.routing.Choose(tc.visitor)
+
+			assert.Equal(t, tc.expectedUrl, url)
+			assert.Equal(t, tc.expectedVariant, variant)
+			assert.Equal(t, tc.expectedOk, ok)
+		})
+	}
+}
+
+func TestRouting_ChooseSplit(t *testing.T) {
+	t.Parallel()
+
+	routing := &Routing{Variants: []Variant{
+		{Name: "a", Url: "https://example.com/a", Weight: 1},
+		{Name: "b", Url: "https://example.com/b", Weight: 3},
+	}}
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		key := fmt.Sprintf("visitor-%d", i)
+		_, variant, ok := routing.Choose(Visitor{Key: key})
+		assert.True(t, ok)
+		counts[variant]++
+
+		// The same visitor always lands in the same variant
+		_, again, _ := routing.Choose(Visitor{Key: key})
+		assert.Equal(t, variant, again)
+	}
+
+	assert.InDelta(t, 1000, counts["a"], 150)
+	assert.InDelta(t, 3000, counts["b"], 150)
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		header   string
+		expected string
+	}{
+		{header: "", expected: ""},
+		{header: "de-AT", expected: "de-AT"},
+		{header: "en-US,en;q=0.9,de;q=0.8", expected: "en-US"},
+		{header: "fr;q=0.4, pt-BR;q=0.7, *;q=0.9", expected: "pt-BR"},
+		{header: "es;q=0, it;q=bad", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, PreferredLanguage(tc.header))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package shortcode
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultAlphabet holds the characters of base62 codes.
+const DefaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// LowerAlphabet holds the characters of lowercase base36 codes, which stay
+// distinct when codes are compared case-insensitively.
+const LowerAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// MinAlphabetLength is the smallest alphabet accepted; shorter ones make codes
+// long and obfuscated sequences easy to follow.
+const MinAlphabetLength = 16
+
+// ErrInvalidAlphabet is returned for alphabets that are too short, repeat a
+// character or contain characters that are not safe in a URL path.
+var ErrInvalidAlphabet = errors.New("invalid code alphabet")
+
+// ValidateAlphabet checks that the alphabet has at least MinAlphabetLength distinct
+// characters, all of them letters, digits, '-' or '_'.
+func ValidateAlphabet(alphabet string) error {
+	if len(alphabet) < MinAlphabetLength {
+		return fmt.Errorf("%w: needs at least %d characters", ErrInvalidAlphabet, MinAlphabetLength)
+	}
+
+	seen := make(map[byte]struct{}, len(alphabet))
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if !isCodeChar(c) {
+			return fmt.Errorf("%w: %q is not allowed", ErrInvalidAlphabet, c)
+		}
+		if _, ok := seen[c]; ok {
+			return fmt.Errorf("%w: %q is repeated", ErrInvalidAlphabet, c)
+		}
+		seen[c] = struct{}{}
+	}
+
+	return nil
+}
+
+// IsCaseless reports whether no two characters of the alphabet differ only in case,
+// so that distinct codes stay distinct when compared case-insensitively.
+func IsCaseless(alphabet string) bool {
+	seen := make(map[rune]struct{}, len(alphabet))
+	for _, c := range strings.ToLower(alphabet) {
+		if _, ok := seen[c]; ok {
+			return false
+		}
+		seen[c] = struct{}{}
+	}
+
+	return true
+}
+
+func isCodeChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// Random returns a code of the given length with characters drawn uniformly
+// from the alphabet using a cryptographically secure source.
+func Random(alphabet string, length int) (string, error) {
+	if length <= 0 {
+		return "", errors.New("invalid length")
+	}
+
+	// Bytes at or above limit are dropped so every character is equally likely
+	limit := 256 - 256%len(alphabet)
+	res := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(res) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(res) < length {
+				res = append(res, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+
+	return string(res), nil
+}
+
+// Encode writes n in the base of the alphabet, left-padded with its first
+// character to at least minLength characters. With DefaultAlphabet this is base62.
+func Encode(n uint64, alphabet string, minLength int) string {
+	return string(encode(n, []byte(alphabet), minLength))
+}
+
+func encode(n uint64, alphabet []byte, minLength int) []byte {
+	base := uint64(len(alphabet))
+
+	var digits []byte
+	for {
+		digits = append(digits, alphabet[n%base])
+		n /= base
+		if n == 0 {
+			break
+		}
+	}
+	for len(digits) < minLength {
+		digits = append(digits, alphabet[0])
+	}
+
+	// Digits were collected least significant first
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+
+	return digits
+}
+
+// Obfuscator turns sequence numbers into codes that do not reveal their order,
+// in the style of hashids: the alphabet is shuffled by a secret salt and again
+// for every number, keyed by a leading "lottery" character. Distinct numbers
+// always give distinct codes.
+type Obfuscator struct {
+	alphabet []byte
+	salt     []byte
+}
+
+// NewObfuscator creates an Obfuscator for the alphabet and salt.
+// The alphabet must pass ValidateAlphabet; the salt may be empty but then
+// anyone who knows the alphabet can recover the sequence.
+func NewObfuscator(alphabet, salt string) *Obfuscator {
+	a := []byte(alphabet)
+	shuffle(a, []byte(salt))
+
+	return &Obfuscator{
+		alphabet: a,
+		salt:     []byte(salt),
+	}
+}
+
+// Encode returns the code for n with at least minLength characters.
+func (o *Obfuscator) Encode(n uint64, minLength int) string {
+	lottery := o.alphabet[n%uint64(len(o.alphabet))]
+
+	key := make([]byte, 0, 1+len(o.salt)+len(o.alphabet))
+	key = append(key, lottery)
+	key = append(key, o.salt...)
+	key = append(key, o.alphabet...)
+
+	alphabet := append([]byte(nil), o.alphabet...)
+	shuffle(alphabet, key[:len(alphabet)])
+
+	return string(lottery) + string(encode(n, alphabet, minLength-1))
+}
+
+// shuffle permutes the alphabet in place, deterministically for the same key.
+// It is the consistent shuffle of hashids.
+func shuffle(alphabet, key []byte) {
+	if len(key) == 0 {
+		return
+	}
+
+	for i, v, p := len(alphabet)-1, 0, 0; i > 0; i-- {
+		v %= len(key)
+		c := int(key[v])
+		p += c
+		j := (c + v + p) % i
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+		v++
+	}
+}
@@ -0,0 +1,106 @@
+package shortcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAlphabet(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		alphabet    string
+		expectedErr error
+	}{
+		{name: "default alphabet", alphabet: DefaultAlphabet},
+		{name: "lower alphabet", alphabet: LowerAlphabet},
+		{name: "url safe symbols", alphabet: "0123456789abcdef-_"},
+		{name: "too short", alphabet: "abc", expectedErr: ErrInvalidAlphabet},
+		{name: "repeated character", alphabet: "0123456789abcdea", expectedErr: ErrInvalidAlphabet},
+		{name: "preview suffix", alphabet: "0123456789abcdef+", expectedErr: ErrInvalidAlphabet},
+		{name: "path separator", alphabet: "0123456789abcdef/", expectedErr: ErrInvalidAlphabet},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorIs(t, ValidateAlphabet(tc.alphabet), tc.expectedErr)
+		})
+	}
+}
+
+func TestIsCaseless(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsCaseless(LowerAlphabet))
+	assert.True(t, IsCaseless("0123456789ABCDEF-_"))
+	assert.False(t, IsCaseless(DefaultAlphabet))
+	assert.False(t, IsCaseless("0123456789abcdefA"))
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	alphabet := "0123456789abcdef"
+	code, err := Random(alphabet, 32)
+	require.NoError(t, err)
+	assert.Len(t, code, 32)
+	for _, c := range code {
+		assert.True(t, strings.ContainsRune(alphabet, c), "unexpected character %q", c)
+	}
+
+	_, err = Random(alphabet, 0)
+	assert.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		n         uint64
+		minLength int
+		expected  string
+	}{
+		{name: "zero", n: 0, minLength: 0, expected: "a"},
+		{name: "single digit", n: 61, minLength: 0, expected: "9"},
+		{name: "two digits", n: 62, minLength: 0, expected: "ba"},
+		{name: "padded", n: 62, minLength: 6, expected: "aaaaba"},
+		{name: "longer than minimum", n: 62 * 62 * 62, minLength: 3, expected: "baaa"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, Encode(tc.n, DefaultAlphabet, tc.minLength))
+		})
+	}
+}
+
+func TestObfuscator_Encode(t *testing.T) {
+	t.Parallel()
+
+	o := NewObfuscator(DefaultAlphabet, "pepper")
+
+	seen := make(map[string]uint64)
+	for n := uint64(0); n < 20000; n++ {
+		code := o.Encode(n, 6)
+		require.GreaterOrEqual(t, len(code), 6)
+		prev, dup := seen[code]
+		require.False(t, dup, "%d and %d both encode to %s", prev, n, code)
+		seen[code] = n
+	}
+
+	// Same input, same code
+	assert.Equal(t, o.Encode(42, 6), NewObfuscator(DefaultAlphabet, "pepper").Encode(42, 6))
+	// Neighbours do not share a prefix the way counters do
+	assert.NotEqual(t, o.Encode(1000, 6)[:3], o.Encode(1001, 6)[:3])
+	// The salt changes every code
+	assert.NotEqual(t, o.Encode(42, 6), NewObfuscator(DefaultAlphabet, "salt").Encode(42, 6))
+}
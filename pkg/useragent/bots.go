@@ -0,0 +1,72 @@
+package useragent
+
+import "strings"
+
+// DeviceBot is the device class reported for crawlers and link-preview fetchers.
+const DeviceBot = "bot"
+
+// OtherBot is the name reported for crawlers that only match a generic token.
+const OtherBot = "Other bot"
+
+// bots lists known crawlers and link-preview fetchers. Specific clients come
+// first so they are reported under their own name; the generic tokens at the
+// end catch the long tail of self-identifying bots.
+// Keep entries lower case and add new unfurlers here as they show up in stats.
+var bots = []family{
+	// Chat and social link previews
+	{"slackbot", "Slackbot"},
+	{"slack-imgproxy", "Slackbot"},
+	{"twitterbot", "Twitterbot"},
+	{"facebookexternalhit", "Facebook"},
+	{"facebookcatalog", "Facebook"},
+	{"meta-externalagent", "Facebook"},
+	{"linkedinbot", "LinkedInBot"},
+	{"discordbot", "Discordbot"},
+	{"telegrambot", "TelegramBot"},
+	{"whatsapp", "WhatsApp"},
+	{"skypeuripreview", "Microsoft Teams"},
+	{"microsoftpreview", "Microsoft Teams"},
+	{"pinterestbot", "Pinterest"},
+	{"redditbot", "Redditbot"},
+	{"embedly", "Embedly"},
+	{"iframely", "Iframely"},
+	{"mastodon", "Mastodon"},
+	{"bluesky", "Bluesky"},
+	{"vkshare", "VK"},
+	{"applebot", "Applebot"},
+	// Search engines
+	{"googlebot", "Googlebot"},
+	{"google-inspectiontool", "Googlebot"},
+	{"adsbot-google", "Googlebot"},
+	{"bingbot", "Bingbot"},
+	{"bingpreview", "Bingbot"},
+	{"yandexbot", "YandexBot"},
+	{"duckduckbot", "DuckDuckBot"},
+	{"baiduspider", "Baiduspider"},
+	{"yahoo! slurp", "Yahoo Slurp"},
+	// Headless browsers and scanners
+	{"headlesschrome", "Headless Chrome"},
+	{"phantomjs", "PhantomJS"},
+	{"lighthouse", "Lighthouse"},
+	// Generic self-identifying crawlers
+	{"crawler", OtherBot},
+	{"spider", OtherBot},
+	{"bot/", OtherBot},
+	{"bot;", OtherBot},
+	{"bot)", OtherBot},
+	{"+http", OtherBot},
+}
+
+// IsBot reports whether the User-Agent belongs to a known crawler or link-preview fetcher.
+func IsBot(ua string) bool {
+	return botName(strings.ToLower(ua)) != ""
+}
+
+// botName returns the name of the bot matching the lower-cased User-Agent, or "" for humans.
+func botName(ua string) string {
+	if name := match(ua, bots); name != Unknown {
+		return name
+	}
+
+	return ""
+}
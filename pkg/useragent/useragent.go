@@ -0,0 +1,111 @@
+package useragent
+
+import "strings"
+
+// Device classes returned by Parse.
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceUnknown = "unknown"
+)
+
+// Unknown is used for browser and OS families that could not be detected.
+const Unknown = "Other"
+
+// Info holds the details extracted from a User-Agent header.
+// For bots Device is DeviceBot and Browser holds the bot name.
+type Info struct {
+	Device  string
+	Browser string
+	OS      string
+	Bot     bool
+}
+
+// family maps a User-Agent token to the name it is reported under.
+// Lists are ordered: the first matching token wins.
+type family struct {
+	token string
+	name  string
+}
+
+var browsers = []family{
+	{"edg/", "Edge"},
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"yabrowser/", "Yandex"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"chromium/", "Chrome"},
+	{"fxios/", "Firefox"},
+	{"firefox/", "Firefox"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+}
+
+var operatingSystems = []family{
+	{"windows", "Windows"},
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"cros", "ChromeOS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+// Parse extracts the device class, browser family and operating system from a
+// User-Agent header using simple token matching. It never fails; unknown
+// values are reported as Unknown (or DeviceUnknown for an empty header).
+// Known crawlers and link-preview fetchers are flagged as bots.
+func Parse(ua string) Info {
+	if strings.TrimSpace(ua) == "" {
+		return Info{Device: DeviceUnknown, Browser: Unknown, OS: Unknown}
+	}
+
+	lower := strings.ToLower(ua)
+
+	if name := botName(lower); name != "" {
+		return Info{
+			Device:  DeviceBot,
+			Browser: name,
+			OS:      match(lower, operatingSystems),
+			Bot:     true,
+		}
+	}
+
+	return Info{
+		Device:  parseDevice(lower),
+		Browser: match(lower, browsers),
+		OS:      match(lower, operatingSystems),
+	}
+}
+
+func parseDevice(ua string) string {
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+func match(ua string, families []family) string {
+	for _, f := range families {
+		if strings.Contains(ua, f.token) {
+			return f.name
+		}
+	}
+
+	return Unknown
+}
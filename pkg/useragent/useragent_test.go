@@ -0,0 +1,125 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		ua       string
+		expected Info
+	}{
+		{
+			name:     "empty user agent",
+			ua:       "",
+			expected: Info{Device: DeviceUnknown, Browser: Unknown, OS: Unknown},
+		},
+		{
+			name:     "chrome on windows",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: Info{Device: DeviceDesktop, Browser: "Chrome", OS: "Windows"},
+		},
+		{
+			name:     "edge is not reported as chrome",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			expected: Info{Device: DeviceDesktop, Browser: "Edge", OS: "Windows"},
+		},
+		{
+			name:     "safari on iphone",
+			ua:       "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			expected: Info{Device: DeviceMobile, Browser: "Safari", OS: "iOS"},
+		},
+		{
+			name:     "android tablet",
+			ua:       "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: Info{Device: DeviceTablet, Browser: "Chrome", OS: "Android"},
+		},
+		{
+			name:     "firefox on linux",
+			ua:       "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			expected: Info{Device: DeviceDesktop, Browser: "Firefox", OS: "Linux"},
+		},
+		{
+			name:     "slack link preview",
+			ua:       "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+			expected: Info{Device: DeviceBot, Browser: "Slackbot", OS: Unknown, Bot: true},
+		},
+		{
+			name:     "googlebot smartphone",
+			ua:       "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: Info{Device: DeviceBot, Browser: "Googlebot", OS: "Android", Bot: true},
+		},
+		{
+			name:     "generic crawler",
+			ua:       "Mozilla/5.0 (compatible; SomeCrawler/1.0)",
+			expected: Info{Device: DeviceBot, Browser: OtherBot, OS: Unknown, Bot: true},
+		},
+		{
+			name:     "unknown client",
+			ua:       "SomeClient/1.0",
+			expected: Info{Device: DeviceDesktop, Browser: Unknown, OS: Unknown},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, Parse(tc.ua))
+		})
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		ua       string
+		expected bool
+	}{
+		{
+			name:     "twitter card fetcher",
+			ua:       "Twitterbot/1.0",
+			expected: true,
+		},
+		{
+			name:     "facebook unfurler",
+			ua:       "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)",
+			expected: true,
+		},
+		{
+			name:     "whatsapp preview",
+			ua:       "WhatsApp/2.23.20.0",
+			expected: true,
+		},
+		{
+			name:     "discord embed",
+			ua:       "Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)",
+			expected: true,
+		},
+		{
+			name:     "desktop browser",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: false,
+		},
+		{
+			name:     "empty user agent",
+			ua:       "",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, IsBot(tc.ua))
+		})
+	}
+}
@@ -21,3 +21,9 @@ func GetUserIDFromContext(c *gin.Context) (string, bool) {
 
 	return userIdValue, true
 }
+
+// GetLinkDomainFromContext extracts the custom domain serving the request from the
+// link domain middleware context. It returns an empty string for the shared host.
+func GetLinkDomainFromContext(c *gin.Context) string {
+	return c.GetString(middleware.LinkDomainKey)
+}
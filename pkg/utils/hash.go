@@ -3,10 +3,14 @@ package utils
 import "golang.org/x/crypto/bcrypt"
 
 // HashPassword hashes the provided password using bcrypt.
-func HashPassword(s string) string {
-	hashBytes, _ := bcrypt.GenerateFromPassword([]byte(s), bcrypt.DefaultCost)
+// Returns bcrypt.ErrPasswordTooLong for passwords longer than 72 bytes.
+func HashPassword(s string) (string, error) {
+	hashBytes, err := bcrypt.GenerateFromPassword([]byte(s), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
 
-	return string(hashBytes)
+	return string(hashBytes), nil
 }
 
 // VerifyPassword verifies if the provided password matches the hashed password.
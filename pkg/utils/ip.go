@@ -0,0 +1,19 @@
+package utils
+
+import "net"
+
+// AnonymizeIP zeroes the host part of an IP address so it can be stored without
+// identifying a single client: the last octet for IPv4 and the last 80 bits for IPv6.
+// Returns an empty string if the input is not a valid IP address.
+func AnonymizeIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
@@ -0,0 +1,122 @@
+package utm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Placeholders expanded in parameter values at redirect time.
+const (
+	// PlaceholderCode is replaced by the short code of the link
+	PlaceholderCode = "{code}"
+	// PlaceholderVariant is replaced by the routing rule or variant the visitor was sent to
+	PlaceholderVariant = "{variant}"
+)
+
+// ErrInvalidTemplate is returned when a value uses an unknown placeholder.
+var ErrInvalidTemplate = errors.New("invalid utm template")
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// Params holds the UTM parameters attached to the destination of a short link.
+// Values may contain the placeholders {code} and {variant}.
+type Params struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// IsEmpty reports whether no parameter is set.
+func (p *Params) IsEmpty() bool {
+	return p == nil || *p == Params{}
+}
+
+// Validate checks that the values only use known placeholders.
+// Returned errors wrap ErrInvalidTemplate.
+func (p *Params) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	for _, param := range p.fields() {
+		for _, placeholder := range placeholderPattern.FindAllString(param.value, -1) {
+			if placeholder != PlaceholderCode && placeholder != PlaceholderVariant {
+				return fmt.Errorf("%w: %s uses unknown placeholder %s", ErrInvalidTemplate, param.name, placeholder)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Expand returns the parameters with the placeholders replaced.
+func (p *Params) Expand(code, variant string) Params {
+	if p == nil {
+		return Params{}
+	}
+
+	replacer := strings.NewReplacer(PlaceholderCode, code, PlaceholderVariant, variant)
+	return Params{
+		Source:   replacer.Replace(p.Source),
+		Medium:   replacer.Replace(p.Medium),
+		Campaign: replacer.Replace(p.Campaign),
+		Term:     replacer.Replace(p.Term),
+		Content:  replacer.Replace(p.Content),
+	}
+}
+
+// Apply expands the parameters and merges them into the destination URL.
+// Parameters the destination already carries are kept as they are, and
+// destinations that cannot be parsed are returned unchanged.
+func (p *Params) Apply(destination, code, variant string) string {
+	if p.IsEmpty() {
+		return destination
+	}
+
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+
+	expanded := p.Expand(code, variant)
+	present := u.Query()
+	missing := url.Values{}
+	for _, param := range expanded.fields() {
+		if param.value != "" && !present.Has(param.name) {
+			missing.Set(param.name, param.value)
+		}
+	}
+	if len(missing) == 0 {
+		return destination
+	}
+
+	// Append rather than re-encode, so the existing query keeps its order and encoding
+	if u.RawQuery != "" {
+		u.RawQuery += "&"
+	}
+	u.RawQuery += missing.Encode()
+	// Drop an empty "?" the original URL may have ended with
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+func (p Params) fields() []field {
+	return []field{
+		{"utm_source", p.Source},
+		{"utm_medium", p.Medium},
+		{"utm_campaign", p.Campaign},
+		{"utm_term", p.Term},
+		{"utm_content", p.Content},
+	}
+}
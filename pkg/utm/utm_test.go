@@ -0,0 +1,88 @@
+package utm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParams_Apply(t *testing.T) {
+	t.Parallel()
+
+	campaign := &Params{Source: "newsletter", Medium: "email", Campaign: "spring-{code}", Content: "{variant}"}
+
+	testCases := []struct {
+		name        string
+		params      *Params
+		destination string
+		expected    string
+	}{
+		{
+			name:        "destination without query",
+			params:      campaign,
+			destination: "https://example.com/landing",
+			expected:    "https://example.com/landing?utm_campaign=spring-abc&utm_content=b&utm_medium=email&utm_source=newsletter",
+		},
+		{
+			name:        "existing parameters are kept",
+			params:      campaign,
+			destination: "https://example.com/landing?b=2&a=1&utm_source=partner#top",
+			expected:    "https://example.com/landing?b=2&a=1&utm_source=partner&utm_campaign=spring-abc&utm_content=b&utm_medium=email#top",
+		},
+		{
+			name:        "empty parameter is still present",
+			params:      &Params{Source: "newsletter"},
+			destination: "https://example.com/?utm_source=",
+			expected:    "https://example.com/?utm_source=",
+		},
+		{
+			name:        "values are encoded",
+			params:      &Params{Campaign: "spring sale & more"},
+			destination: "https://example.com/?",
+			expected:    "https://example.com/?utm_campaign=spring+sale+%26+more",
+		},
+		{
+			name:        "no parameters",
+			params:      nil,
+			destination: "https://example.com/landing",
+			expected:    "https://example.com/landing",
+		},
+		{
+			name:        "unparsable destination",
+			params:      campaign,
+			destination: "https://exa mple.com/%zz",
+			expected:    "https://exa mple.com/%zz",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.params.Apply(tc.destination, "abc", "b"))
+		})
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		params      *Params
+		expectedErr error
+	}{
+		{name: "nil params", params: nil},
+		{name: "known placeholders", params: &Params{Campaign: "{code}-{variant}", Content: "plain"}},
+		{name: "unknown placeholder", params: &Params{Term: "{keyword}"}, expectedErr: ErrInvalidTemplate},
+		{name: "empty placeholder", params: &Params{Source: "{}"}, expectedErr: ErrInvalidTemplate},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ErrorIs(t, tc.params.Validate(), tc.expectedErr)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"regexp"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -13,6 +14,21 @@ var (
 	lowerRegex    = regexp.MustCompile(`[a-z]`)
 	numberRegex   = regexp.MustCompile(`[0-9]`)
 	specialRegex  = regexp.MustCompile(`[!@#$%^&*()_+\-=\[\]{};':"\\|,.<>\/?]`)
+
+	// Short link alias: letters, digits, dash and underscore, 3 to 32 characters
+	aliasRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{3,32}$`)
+
+	// Values of the Referrer-Policy header
+	referrerPolicies = map[string]bool{
+		"no-referrer":                     true,
+		"no-referrer-when-downgrade":      true,
+		"origin":                          true,
+		"origin-when-cross-origin":        true,
+		"same-origin":                     true,
+		"strict-origin":                   true,
+		"strict-origin-when-cross-origin": true,
+		"unsafe-url":                      true,
+	}
 )
 
 // RegisterCustomValidators registers custom validation functions
@@ -21,6 +37,18 @@ func RegisterCustomValidators(v *validator.Validate) error {
 	if err := v.RegisterValidation("strong_password", validateStrongPassword); err != nil {
 		return err
 	}
+	// Register short link alias validator
+	if err := v.RegisterValidation("short_alias", validateShortAlias); err != nil {
+		return err
+	}
+	// Register referrer policy validator
+	if err := v.RegisterValidation("referrer_policy", validateReferrerPolicy); err != nil {
+		return err
+	}
+	// Register byte length validator
+	if err := v.RegisterValidation("max_bytes", validateMaxBytes); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -40,3 +68,30 @@ func validateStrongPassword(fl validator.FieldLevel) bool {
 		numberRegex.MatchString(password) &&
 		specialRegex.MatchString(password)
 }
+
+// validateShortAlias validates a custom short link alias:
+// - Between 3 and 32 characters long
+// - Only letters, digits, "-" and "_"
+func validateShortAlias(fl validator.FieldLevel) bool {
+	return aliasRegex.MatchString(fl.Field().String())
+}
+
+// validateReferrerPolicy validates the Referrer-Policy of a short link:
+// - One of the policies defined by the Referrer Policy specification
+// - Or empty, for no policy
+func validateReferrerPolicy(fl validator.FieldLevel) bool {
+	policy := fl.Field().String()
+
+	return policy == "" || referrerPolicies[policy]
+}
+
+// validateMaxBytes validates the length of a string in bytes rather than characters:
+// - At most as many bytes as the parameter, e.g. max_bytes=72 for bcrypt passwords
+func validateMaxBytes(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+
+	return len(fl.Field().String()) <= limit
+}